@@ -0,0 +1,89 @@
+// audit-dlq - админская утилита для просмотра и повторной отправки
+// аудит-сообщений из DLQ (task_audit_logs.dlq) обратно в task_audit_logs
+//
+//	audit-dlq list [-limit 20]
+//	audit-dlq replay [-limit 20]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/St1cky1/task-service/internal/infrastructure/client"
+)
+
+// errUsage - неизвестная команда или не хватает аргументов
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:])
+	stop()
+
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, "Использование: audit-dlq list|replay [-limit N]")
+		os.Exit(2)
+	case err != nil:
+		log.Print("❌ ", err)
+		os.Exit(1)
+	}
+}
+
+// run выполняет команду; все ресурсы закрываются до выхода из процесса
+func run(ctx context.Context, args []string) error {
+	if len(args) < 1 {
+		return errUsage
+	}
+
+	command := args[0]
+	if command != "list" && command != "replay" {
+		return errUsage
+	}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	limit := flags.Int("limit", 20, "максимальное количество сообщений")
+	flags.Parse(args[1:])
+
+	rabbitMQURL := fmt.Sprintf("amqp://%s:%s@%s:%s/",
+		os.Getenv("RABBITMQ_USER"),
+		os.Getenv("RABBITMQ_PASSWORD"),
+		os.Getenv("RABBITMQ_HOST"),
+		os.Getenv("RABBITMQ_PORT"))
+
+	rabbitMQ, err := client.NewRabbitMQClient(rabbitMQURL)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения к RabbitMQ: %w", err)
+	}
+	defer rabbitMQ.Close()
+
+	switch command {
+	case "list":
+		letters, err := rabbitMQ.ListDeadLetters(ctx, *limit)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения DLQ: %w", err)
+		}
+		if len(letters) == 0 {
+			fmt.Println("DLQ пуста")
+			return nil
+		}
+		for i, letter := range letters {
+			fmt.Printf("#%d [%s] повторов: %d, ошибка: %s\n    %s\n",
+				i+1, letter.DeadLetteredAt.Format("2006-01-02 15:04:05"), letter.RetryCount, letter.LastError, letter.Body)
+		}
+
+	case "replay":
+		replayed, err := rabbitMQ.ReplayDeadLetters(ctx, *limit)
+		if err != nil {
+			return fmt.Errorf("ошибка переотправки (перенесено %d): %w", replayed, err)
+		}
+		fmt.Printf("✅ Перенесено в %s: %d сообщений\n", client.AuditQueueName, replayed)
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -15,6 +17,8 @@ import (
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/infrastructure/auth"
 	"github.com/St1cky1/task-service/internal/infrastructure/client"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
 	"github.com/St1cky1/task-service/internal/infrastructure/worker"
 	"github.com/St1cky1/task-service/internal/repository"
 	"github.com/St1cky1/task-service/internal/usecase"
@@ -71,15 +75,90 @@ func main() {
 	taskAuditRepo := repository.NewTaskAuditRepository(db)
 	avatarRepo := repository.NewAvatarRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	authAuditRepo := repository.NewAuthAuditRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	outboxRepo := repository.NewAuditOutboxRepository(db)
+	labelRepo := repository.NewLabelRepository(db)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	collaboratorRepo := repository.NewCollaboratorRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	transactor := repository.NewTransactor(db)
 
 	// Инициализируем auth компоненты
 	passwordManager := auth.NewPasswordManager()
 	jwtManager := auth.NewJWTManager()
 
+	// Файлы (аватарки и вложения задач): BLOB_STORE=local (каталог BLOB_LOCAL_DIR, по умолчанию var)
+	// или s3 (S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY)
+	blobStore, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		log.Fatal("❌ Ошибка настройки хранилища файлов:", err)
+	}
+	blobService := usecase.NewBlobService(blobStore)
+
+	// Почта: MAILER=log (по умолчанию, в лог или файл MAIL_LOG_FILE)
+	// или smtp (SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD), отправитель - MAIL_FROM
+	mailerConfig, err := mailer.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("❌ Ошибка настройки почты:", err)
+	}
+	mailSender, err := mailer.New(mailerConfig)
+	if err != nil {
+		log.Fatal("❌ Ошибка настройки почты:", err)
+	}
+
 	// Инициализируем сервисы
-	taskService := usecase.NewTaskService(taskRepo, userRepo, taskAuditRepo, rabbitMQ)
-	userService := usecase.NewUserService(userRepo, avatarRepo, passwordManager, jwtManager, refreshTokenRepo)
-	authService := usecase.NewAuthService(userRepo, refreshTokenRepo, passwordManager, jwtManager)
+	roleService := usecase.NewRoleService(roleRepo, userRepo)
+	if err := roleService.LoadPermissions(context.Background()); err != nil {
+		log.Fatal("❌ Ошибка загрузки прав ролей:", err)
+	}
+	// Права, которых лишены пользователи с неподтвержденным email, через запятую
+	// (по умолчанию task:write), none - без ограничений
+	if spec, ok := os.LookupEnv("UNVERIFIED_DENY_PERMISSIONS"); ok {
+		if err := roleService.SetUnverifiedPolicy(parsePermissions(spec)); err != nil {
+			log.Fatal("❌ Ошибка в UNVERIFIED_DENY_PERMISSIONS:", err)
+		}
+	}
+
+	taskService := usecase.NewTaskService(taskRepo, userRepo, labelRepo, dependencyRepo, collaboratorRepo, projectRepo, commentRepo, attachmentRepo, taskAuditRepo, outboxRepo, transactor, blobService)
+	if spec := os.Getenv("TASK_STATUS_TRANSITIONS"); spec != "" {
+		statusMachine, err := newStatusMachine(spec)
+		if err != nil {
+			log.Fatal("❌ Ошибка в TASK_STATUS_TRANSITIONS:", err)
+		}
+		taskService.SetStatusMachine(statusMachine)
+	}
+	// cascade - удалять подзадачи вместе с задачей, reparent (по умолчанию) - поднимать к ее родителю
+	if policy := os.Getenv("TASK_DELETE_SUBTASKS"); policy != "" {
+		if err := taskService.SetSubtaskPolicy(entity.SubtaskPolicy(policy)); err != nil {
+			log.Fatal("❌ Ошибка в TASK_DELETE_SUBTASKS:", err)
+		}
+	}
+	attachmentLimits, err := attachmentLimitsFromEnv()
+	if err != nil {
+		log.Fatal("❌ Ошибка в лимитах вложений:", err)
+	}
+	if err := taskService.SetAttachmentLimits(attachmentLimits); err != nil {
+		log.Fatal("❌ Ошибка в лимитах вложений:", err)
+	}
+	projectService := usecase.NewProjectService(projectRepo, userRepo, taskService, transactor)
+	userService := usecase.NewUserService(userRepo, avatarRepo, blobService, passwordManager, jwtManager, refreshTokenRepo, transactor)
+	authService := usecase.NewAuthService(userRepo, refreshTokenRepo, passwordResetRepo, emailVerificationRepo, loginAttemptRepo, authAuditRepo, roleService, passwordManager, jwtManager, mailSender, transactor)
+	if resetURL := os.Getenv("PASSWORD_RESET_URL"); resetURL != "" {
+		if err := authService.SetPasswordResetURL(resetURL); err != nil {
+			log.Fatal("❌ Ошибка в PASSWORD_RESET_URL:", err)
+		}
+	}
+	if verificationURL := os.Getenv("EMAIL_VERIFICATION_URL"); verificationURL != "" {
+		if err := authService.SetEmailVerificationURL(verificationURL); err != nil {
+			log.Fatal("❌ Ошибка в EMAIL_VERIFICATION_URL:", err)
+		}
+	}
 
 	// Запускаем воркер для обработки аудит-сообщений
 	auditWorker := worker.NewAuditWorker(rabbitMQ, taskAuditRepo)
@@ -93,6 +172,15 @@ func main() {
 		auditWorker.Start(workerCtx)
 	}()
 
+	// Запускаем relay, который публикует аудит из outbox в RabbitMQ
+	outboxRelay := worker.NewOutboxRelay(outboxRepo, rabbitMQ, worker.DefaultOutboxRelayConfig())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fmt.Println("Запуск Outbox Relay...")
+		outboxRelay.Start(workerCtx)
+	}()
+
 	// Запускаем непрерывную генерацию задач
 	taskGenCtx, taskGenCancel := context.WithCancel(context.Background())
 	defer taskGenCancel()
@@ -102,13 +190,13 @@ func main() {
 		continuousTaskGeneration(taskGenCtx, taskService, userRepo)
 	}()
 
-	// Запускаем gRPC сервер со всеми сервисами (Task, User, Auth)
-	grpcServer := grpcapi.NewGRPCServer(taskService, userService, authService)
+	// Запускаем gRPC сервер со всеми сервисами (Task, Project, User, Auth)
+	grpcServer := grpcapi.NewGRPCServer(taskService, userService, authService, roleService, projectService, jwtManager)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		fmt.Println("Запуск gRPC сервера на порту 9090...")
-		fmt.Println("📋 TaskService, UserService и AuthService готовы к работе!")
+		fmt.Println("📋 TaskService, ProjectService, UserService и AuthService готовы к работе!")
 		if err := grpcServer.Start("9090"); err != nil {
 			log.Printf("❌ gRPC server error: %v", err)
 		}
@@ -147,6 +235,7 @@ func main() {
 
 	// Ждем сигнал завершения
 	waitForShutdown(workerCancel, taskGenCancel, userGenCancel)
+	authService.WaitMail()
 }
 
 // Непрерывная генерация задач для всех пользователей
@@ -207,8 +296,11 @@ func continuousTaskGeneration(ctx context.Context, taskService *usecase.TaskServ
 			if taskCounter%3 == 0 {
 				// Обновляем задачу
 				updateReq := entity.UpdateTaskRequest{
-					Title:  fmt.Sprintf("обновленная задача #%d", taskCounter),
-					Status: entity.StatusCompleted,
+					Title: fmt.Sprintf("обновленная задача #%d", taskCounter),
+				}
+				// Отмененную задачу граф статусов по умолчанию завершить не дает
+				if task.Status != entity.StatusCancelled {
+					updateReq.Status = entity.StatusCompleted
 				}
 
 				updatedTask, err := taskService.UpdateTask(ctx, task.ID, user.ID, &updateReq)
@@ -221,7 +313,7 @@ func continuousTaskGeneration(ctx context.Context, taskService *usecase.TaskServ
 
 			// Удаляем каждую 5-ю задачу
 			if taskCounter%5 == 0 {
-				err = taskService.DeleteTask(ctx, task.ID, user.ID)
+				err = taskService.DeleteTask(ctx, task.ID, user.ID, 0, "")
 				if err != nil {
 					log.Printf("❌ Ошибка удаления авто-задачи: %v", err)
 				} else {
@@ -231,11 +323,13 @@ func continuousTaskGeneration(ctx context.Context, taskService *usecase.TaskServ
 
 			// Показываем статистику каждые 10 задач
 			if taskCounter%10 == 0 {
-				totalTasks := 0
+				var totalTasks int64
 				for _, u := range users {
-					tasks, err := taskService.ListTasks(ctx, u.ID, "")
+					page, err := taskService.ListTasks(ctx, u.ID, &entity.ListTasksRequest{
+						Page: entity.PageRequest{PageSize: 1, Total: entity.TotalExact},
+					})
 					if err == nil {
-						totalTasks += len(tasks)
+						totalTasks += page.Total
 					}
 				}
 				fmt.Printf("📊 Статистика: создано %d задач, активных пользователей: %d, всего задач в БД: %d\n",
@@ -264,6 +358,51 @@ func waitForShutdown(workerCancel, taskGenCancel, userGenCancel context.CancelFu
 	fmt.Println("✅ Приложение завершено корректно")
 }
 
+// newStatusMachine строит граф переходов статусов из TASK_STATUS_TRANSITIONS,
+// например "pending:in_progress,cancelled;in_progress:completed,cancelled"
+func newStatusMachine(spec string) (*usecase.StatusMachine, error) {
+	transitions, err := usecase.ParseStatusTransitions(spec)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewStatusMachine(transitions)
+}
+
+// parsePermissions разбирает список прав через запятую, none или пустая строка - пустой список
+func parsePermissions(spec string) []entity.Permission {
+	var permissions []entity.Permission
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "none" {
+			continue
+		}
+		permissions = append(permissions, entity.Permission(part))
+	}
+	return permissions
+}
+
+// attachmentLimitsFromEnv читает лимиты вложений в байтах из ATTACHMENT_MAX_FILE_SIZE,
+// ATTACHMENT_TASK_QUOTA и ATTACHMENT_USER_QUOTA, незаданные остаются по умолчанию
+func attachmentLimitsFromEnv() (entity.AttachmentLimits, error) {
+	limits := usecase.DefaultAttachmentLimits()
+	for env, limit := range map[string]*int64{
+		"ATTACHMENT_MAX_FILE_SIZE": &limits.MaxFileSize,
+		"ATTACHMENT_TASK_QUOTA":    &limits.TaskQuota,
+		"ATTACHMENT_USER_QUOTA":    &limits.UserQuota,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return limits, fmt.Errorf("%s: %w", env, err)
+		}
+		*limit = parsed
+	}
+	return limits, nil
+}
+
 func runMigrations(dbURL string) error {
 	m, err := migrate.New("file://migrations", dbURL)
 	if err != nil {
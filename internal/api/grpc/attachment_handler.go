@@ -0,0 +1,163 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UploadAttachment прикрепляет файл к задаче (клиентский stream)
+func (s *TaskServiceServer) UploadAttachment(stream pb.TaskService_UploadAttachmentServer) error {
+	userID, err := callerID(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	// Первый пакет задает задачу и имя файла
+	firstMsg, err := stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return status.Error(codes.InvalidArgument, "empty stream")
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	// Доступ к задаче проверяем до приема файла, чанки сразу уходят во временный объект хранилища
+	req := &entity.UploadAttachmentRequest{
+		TaskID:   int(firstMsg.TaskId),
+		FileName: firstMsg.FileName,
+	}
+	upload, err := s.taskService.NewAttachmentUpload(stream.Context(), userID, req)
+	if err != nil {
+		return attachmentError(err)
+	}
+	defer upload.Abort()
+
+	if _, err := upload.Write(firstMsg.Data); err != nil {
+		return attachmentError(err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+		// Превышение лимита обрывает загрузку сразу, не дожидаясь конца потока
+		if _, err := upload.Write(msg.Data); err != nil {
+			return attachmentError(err)
+		}
+	}
+
+	attachment, err := s.taskService.UploadAttachment(stream.Context(), userID, req, upload)
+	if err != nil {
+		return attachmentError(err)
+	}
+
+	return stream.SendAndClose(convertAttachment(attachment))
+}
+
+// DownloadAttachment отдает содержимое вложения (серверный stream)
+func (s *TaskServiceServer) DownloadAttachment(req *pb.DownloadAttachmentRequest, stream pb.TaskService_DownloadAttachmentServer) error {
+	userID, err := callerID(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	attachment, dataChan, errChan, err := s.taskService.DownloadAttachment(stream.Context(), userID, int(req.TaskId), int(req.AttachmentId), 64*1024) // 64KB chunks
+	if err != nil {
+		return attachmentError(err)
+	}
+
+	// Метаданные идут в первом сообщении
+	first := true
+	for data := range dataChan {
+		msg := &pb.DownloadAttachmentResponse{Data: data}
+		if first {
+			msg.ContentType = attachment.ContentType
+			msg.FileName = attachment.FileName
+			first = false
+		}
+		if err := stream.Send(msg); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := <-errChan; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// ListAttachments возвращает вложения задачи
+func (s *TaskServiceServer) ListAttachments(ctx context.Context, req *pb.ListAttachmentsRequest) (*pb.ListAttachmentsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.taskService.ListAttachments(ctx, userID, int(req.TaskId))
+	if err != nil {
+		return nil, attachmentError(err)
+	}
+
+	pbAttachments := make([]*pb.TaskAttachment, len(attachments))
+	for i := range attachments {
+		pbAttachments[i] = convertAttachment(&attachments[i])
+	}
+	return &pb.ListAttachmentsResponse{Attachments: pbAttachments}, nil
+}
+
+// DeleteAttachment удаляет вложение
+func (s *TaskServiceServer) DeleteAttachment(ctx context.Context, req *pb.DeleteAttachmentRequest) (*pb.DeleteAttachmentResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskService.DeleteAttachment(ctx, userID, int(req.TaskId), int(req.AttachmentId)); err != nil {
+		return nil, attachmentError(err)
+	}
+
+	return &pb.DeleteAttachmentResponse{Success: true}, nil
+}
+
+// attachmentError переводит ошибки операций с вложениями в gRPC статус
+func attachmentError(err error) error {
+	switch err {
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrAttachmentNotFound:
+		return status.Error(codes.NotFound, "attachment not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrTaskArchived:
+		return status.Error(codes.FailedPrecondition, "task is archived")
+	case entity.ErrInvalidAttachment:
+		return status.Error(codes.InvalidArgument, "file name must be 1-255 characters without control characters")
+	case entity.ErrEmptyFile:
+		return status.Error(codes.InvalidArgument, "file is empty")
+	case entity.ErrFileTooLarge, entity.ErrQuotaExceeded:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertAttachment конвертирует entity.TaskAttachment в protobuf
+func convertAttachment(attachment *entity.TaskAttachment) *pb.TaskAttachment {
+	return &pb.TaskAttachment{
+		Id:          int32(attachment.ID),
+		TaskId:      int32(attachment.TaskID),
+		UploaderId:  int32(formatOptionalID(attachment.UploaderID)),
+		FileName:    attachment.FileName,
+		FileSize:    attachment.FileSize,
+		ContentType: attachment.ContentType,
+		CreatedAt:   attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,7 +2,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	pb "github.com/St1cky1/task-service/proto/pb"
@@ -20,10 +22,14 @@ func (s *UserServiceServer) Register(ctx context.Context, req *pb.RegisterReques
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(ctx),
 	}
 
 	loginResp, err := s.authService.Register(ctx, registerReq)
 	if err != nil {
+		if err == entity.ErrInvalidPassword {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -39,11 +45,19 @@ func (s *UserServiceServer) Login(ctx context.Context, req *pb.LoginRequest) (*p
 	loginReq := &entity.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(ctx),
 	}
 
 	loginResp, err := s.authService.Login(ctx, loginReq)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		switch {
+		case err == entity.ErrInvalidCredentials:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, entity.ErrLoginThrottled):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	return convertLoginResponse(loginResp), nil
@@ -55,9 +69,16 @@ func (s *UserServiceServer) RefreshToken(ctx context.Context, req *pb.RefreshTok
 		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
 	}
 
-	refreshResp, err := s.authService.RefreshToken(ctx, req.RefreshToken)
+	refreshResp, err := s.authService.RefreshToken(ctx, req.RefreshToken, clientInfo(ctx))
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		switch err {
+		case entity.ErrInvalidRefreshToken:
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired refresh token")
+		case entity.ErrRefreshTokenReused:
+			return nil, status.Error(codes.Unauthenticated, "refresh token was already used, session revoked")
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	return &pb.RefreshTokenResponse{
@@ -68,11 +89,17 @@ func (s *UserServiceServer) RefreshToken(ctx context.Context, req *pb.RefreshTok
 
 // Logout откатывает все refresh токены пользователя
 func (s *UserServiceServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
-	if req.UserId == 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Разлогинить можно только себя, независимо от роли
+	if req.UserId != 0 && int(req.UserId) != userID {
+		return nil, status.Error(codes.PermissionDenied, "access denied")
 	}
 
-	err := s.authService.Logout(ctx, int(req.UserId))
+	err = s.authService.Logout(ctx, userID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to logout: %v", err))
 	}
@@ -83,6 +110,133 @@ func (s *UserServiceServer) Logout(ctx context.Context, req *pb.LogoutRequest) (
 	}, nil
 }
 
+// ListSessions возвращает активные сессии вызывающего пользователя
+func (s *UserServiceServer) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.authService.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbSessions := make([]*pb.UserSession, len(sessions))
+	for i, session := range sessions {
+		pbSessions[i] = &pb.UserSession{
+			Id:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastUsedAt: session.LastUsedAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		}
+	}
+
+	return &pb.ListSessionsResponse{Sessions: pbSessions}, nil
+}
+
+// RevokeSession завершает одну из сессий вызывающего пользователя
+func (s *UserServiceServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.RevokeSession(ctx, userID, req.SessionId); err != nil {
+		if err == entity.ErrSessionNotFound {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.RevokeSessionResponse{Success: true}, nil
+}
+
+// RequestPasswordReset отправляет письмо для сброса пароля
+func (s *UserServiceServer) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.authService.RequestPasswordReset(ctx, req.Email, clientInfo(ctx)); err != nil {
+		if err == entity.ErrTooManyRequests {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.RequestPasswordResetResponse{
+		Success: true,
+		Message: "if the email is registered, a reset link has been sent",
+	}, nil
+}
+
+// ConfirmPasswordReset задает новый пароль по токену из письма
+func (s *UserServiceServer) ConfirmPasswordReset(ctx context.Context, req *pb.ConfirmPasswordResetRequest) (*pb.ConfirmPasswordResetResponse, error) {
+	if req.Token == "" || req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "token and new_password are required")
+	}
+
+	if err := s.authService.ConfirmPasswordReset(ctx, req.Token, req.NewPassword); err != nil {
+		if err == entity.ErrInvalidResetToken || err == entity.ErrInvalidPassword {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.ConfirmPasswordResetResponse{
+		Success: true,
+		Message: "password changed, all sessions have been signed out",
+	}, nil
+}
+
+// VerifyEmail подтверждает email по токену из письма
+func (s *UserServiceServer) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if _, err := s.authService.VerifyEmail(ctx, req.Token); err != nil {
+		if err == entity.ErrInvalidVerificationToken {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.VerifyEmailResponse{
+		Success: true,
+		Message: "email verified, refresh the access token to lift restrictions",
+	}, nil
+}
+
+// ResendVerification повторно отправляет письмо подтверждения вызывающему пользователю
+func (s *UserServiceServer) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.ResendVerification(ctx, userID); err != nil {
+		switch err {
+		case entity.ErrUserNotFound:
+			return nil, status.Error(codes.NotFound, "user not found")
+		case entity.ErrEmailAlreadyVerified, entity.ErrInvalidUserData:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case entity.ErrTooManyRequests:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return &pb.ResendVerificationResponse{
+		Success: true,
+		Message: "verification email sent",
+	}, nil
+}
+
 // convertLoginResponse конвертирует entity.LoginResponse в pb.LoginResponse
 func convertLoginResponse(resp *entity.LoginResponse) *pb.LoginResponse {
 	return &pb.LoginResponse{
@@ -105,7 +259,7 @@ func convertRegisterResponse(resp *entity.LoginResponse) *pb.RegisterResponse {
 func convertUser(user *entity.User) *pb.UserResponse {
 	var lastLogin string
 	if user.LastLogin != nil {
-		lastLogin = user.LastLogin.Format("2006-01-02T15:04:05Z07:00")
+		lastLogin = user.LastLogin.Format(time.RFC3339)
 	}
 
 	var email string
@@ -113,19 +267,15 @@ func convertUser(user *entity.User) *pb.UserResponse {
 		email = *user.Email
 	}
 
-	var avatarURL string
-	if user.AvatarURL != nil {
-		avatarURL = *user.AvatarURL
-	}
-
 	return &pb.UserResponse{
-		Id:        int32(user.ID),
-		Name:      user.Name,
-		Email:     email,
-		AvatarUrl: avatarURL,
-		IsActive:  user.IsActive,
-		LastLogin: lastLogin,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Id:            int32(user.ID),
+		Name:          user.Name,
+		Email:         email,
+		AvatarUrl:     userAvatarURL(user),
+		IsActive:      user.IsActive,
+		LastLogin:     lastLogin,
+		CreatedAt:     user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.Format(time.RFC3339),
+		EmailVerified: user.EmailVerified(),
 	}
 }
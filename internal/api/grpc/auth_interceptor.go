@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods - RPC, которые можно вызывать без access token
+var publicMethods = map[string]bool{
+	"/user.v1.UserService/Register":     true,
+	"/user.v1.UserService/Login":        true,
+	"/user.v1.UserService/RefreshToken": true,
+	// Сброс пароля нужен как раз тогда, когда войти нельзя
+	"/user.v1.UserService/RequestPasswordReset": true,
+	"/user.v1.UserService/ConfirmPasswordReset": true,
+	// Ссылку из письма открывают и без входа
+	"/user.v1.UserService/VerifyEmail": true,
+	// Аватарки публичны: GET /api/v1/users/{id}/avatar на gateway обслуживается мимо
+	// интерцептора (<img> не передает access token), и gRPC отдает тот же файл без токена
+	"/user.v1.UserService/DownloadAvatar": true,
+}
+
+type claimsContextKey struct{}
+
+// AuthInterceptor проверяет JWT из metadata, политику доступа RPC
+// и кладет claims и права вызывающего в контекст
+type AuthInterceptor struct {
+	jwtManager    *auth.JWTManager
+	roleService   *usecase.RoleService
+	publicMethods map[string]bool
+}
+
+// NewAuthInterceptor создает новый AuthInterceptor
+func NewAuthInterceptor(jwtManager *auth.JWTManager, roleService *usecase.RoleService) *AuthInterceptor {
+	return &AuthInterceptor{
+		jwtManager:    jwtManager,
+		roleService:   roleService,
+		publicMethods: publicMethods,
+	}
+}
+
+// Unary возвращает unary interceptor для аутентификации
+func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if i.publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream возвращает stream interceptor для аутентификации
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.publicMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := i.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authServerStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// authorize валидирует Bearer токен из metadata, проверяет политику RPC
+// и возвращает контекст с claims и правами вызывающего
+func (i *AuthInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	claims, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Токены, выпущенные до появления ролей, считаем токенами обычного пользователя
+	roles := claims.Roles
+	if len(roles) == 0 {
+		roles = []entity.Role{entity.RoleUser}
+	}
+
+	emailVerified, err := i.emailVerified(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := i.roleService.Permissions(roles)
+	if !emailVerified {
+		// Отказ только из-за неподтвержденного email объясняем отдельно
+		restricted := i.roleService.RestrictUnverified(permissions)
+		if checkPolicy(fullMethod, restricted) != nil && checkPolicy(fullMethod, permissions) == nil {
+			return nil, status.Error(codes.PermissionDenied, "email address is not verified")
+		}
+		permissions = restricted
+	}
+	if err := checkPolicy(fullMethod, permissions); err != nil {
+		return nil, err
+	}
+
+	ctx = ContextWithClaims(ctx, claims)
+	return contextWithPermissions(ctx, permissions), nil
+}
+
+// emailVerified возвращает статус email из токена, а для токенов, выпущенных
+// до проверки email, смотрит его у пользователя
+func (i *AuthInterceptor) emailVerified(ctx context.Context, claims *entity.JWTClaims) (bool, error) {
+	if claims.EmailVerified != nil {
+		return *claims.EmailVerified, nil
+	}
+	verified, err := i.roleService.EmailVerified(ctx, claims.UserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return false, status.Error(codes.Unauthenticated, "user not found")
+	}
+	if err != nil {
+		return false, status.Error(codes.Internal, "failed to check email verification")
+	}
+	return verified, nil
+}
+
+// authenticate достает Bearer токен из metadata и валидирует его
+func (i *AuthInterceptor) authenticate(ctx context.Context) (*entity.JWTClaims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata is not provided")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization token is not provided")
+	}
+
+	tokenString, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found || tokenString == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization header must be in format: Bearer <token>")
+	}
+
+	claims, err := i.jwtManager.ValidateAccessToken(tokenString)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	return claims, nil
+}
+
+// authServerStream подменяет контекст stream'а на контекст с claims
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// ContextWithClaims кладет claims вызывающего пользователя в контекст
+func ContextWithClaims(ctx context.Context, claims *entity.JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext достает claims вызывающего пользователя из контекста
+func ClaimsFromContext(ctx context.Context) (*entity.JWTClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*entity.JWTClaims)
+	return claims, ok && claims != nil
+}
+
+// callerID возвращает ID вызывающего пользователя или Unauthenticated
+func callerID(ctx context.Context) (int, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,285 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/repository"
+	"github.com/St1cky1/task-service/internal/usecase"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeRoleRepository - in-memory IRoleRepository с правами из миграции
+type fakeRoleRepository struct{}
+
+func (r *fakeRoleRepository) GetByUserID(ctx context.Context, userID int) ([]entity.Role, error) {
+	return nil, nil
+}
+
+func (r *fakeRoleRepository) SetForUser(ctx context.Context, userID int, roles []entity.Role) error {
+	return nil
+}
+
+func (r *fakeRoleRepository) GetPermissions(ctx context.Context) (map[entity.Role][]entity.Permission, error) {
+	return map[entity.Role][]entity.Permission{
+		entity.RoleUser:  {entity.PermissionTaskRead, entity.PermissionTaskWrite, entity.PermissionUserRead, entity.PermissionUserWrite},
+		entity.RoleAdmin: {entity.PermissionTaskRead, entity.PermissionTaskWrite, entity.PermissionUserRead, entity.PermissionUserWrite, entity.PermissionUserReadAny, entity.PermissionUserManage},
+	}, nil
+}
+
+func newTestInterceptor(t *testing.T, jwtManager *auth.JWTManager) *AuthInterceptor {
+	roleService := usecase.NewRoleService(&fakeRoleRepository{}, nil)
+	if err := roleService.LoadPermissions(context.Background()); err != nil {
+		t.Fatalf("Failed to load permissions: %v", err)
+	}
+	return NewAuthInterceptor(jwtManager, roleService)
+}
+
+func TestAuthInterceptorPublicMethod(t *testing.T) {
+	interceptor := newTestInterceptor(t, auth.NewJWTManager())
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/Login"}
+	_, err := interceptor.Unary()(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		if _, ok := ClaimsFromContext(ctx); ok {
+			t.Errorf("Expected no claims for public method")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestAuthInterceptorPublicAvatarStream(t *testing.T) {
+	interceptor := newTestInterceptor(t, auth.NewJWTManager())
+
+	// Аватарка доступна без токена и по gRPC, как и по HTTP маршруту gateway
+	info := &grpc.StreamServerInfo{FullMethod: "/user.v1.UserService/DownloadAvatar"}
+	called := false
+	err := interceptor.Stream()(nil, &authServerStream{ctx: context.Background()}, info, func(srv any, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	if err != nil || !called {
+		t.Errorf("Expected DownloadAvatar to be public, got %v", err)
+	}
+}
+
+func TestAuthInterceptorMissingToken(t *testing.T) {
+	interceptor := newTestInterceptor(t, auth.NewJWTManager())
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/GetTask"}
+	_, err := interceptor.Unary()(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		t.Errorf("Handler must not be called without token")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestAuthInterceptorValidToken(t *testing.T) {
+	jwtManager := auth.NewJWTManager()
+	interceptor := newTestInterceptor(t, jwtManager)
+
+	token, err := jwtManager.GenerateAccessToken(42, "user@example.com", []entity.Role{entity.RoleUser}, true)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	info := &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/GetTask"}
+	_, err = interceptor.Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		userID, err := callerID(ctx)
+		if err != nil {
+			t.Fatalf("Expected caller in context, got %v", err)
+		}
+		if userID != 42 {
+			t.Errorf("Expected user ID 42, got %d", userID)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestAuthInterceptorRejectsRefreshToken(t *testing.T) {
+	jwtManager := auth.NewJWTManager()
+	interceptor := newTestInterceptor(t, jwtManager)
+
+	token, err := jwtManager.GenerateRefreshToken(42, "user@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	info := &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/ListTasks"}
+	_, err = interceptor.Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		t.Errorf("Handler must not be called with refresh token")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestAuthInterceptorPolicy(t *testing.T) {
+	jwtManager := auth.NewJWTManager()
+	interceptor := newTestInterceptor(t, jwtManager)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/ListUsers"}
+
+	tests := []struct {
+		name     string
+		roles    []entity.Role
+		wantCode codes.Code
+	}{
+		{name: "user", roles: []entity.Role{entity.RoleUser}, wantCode: codes.PermissionDenied},
+		{name: "no roles claim", roles: nil, wantCode: codes.PermissionDenied},
+		{name: "admin", roles: []entity.Role{entity.RoleAdmin}, wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwtManager.GenerateAccessToken(1, "user@example.com", tt.roles, true)
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+			_, err = interceptor.Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("Expected %v, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptorUnknownMethodDenied(t *testing.T) {
+	jwtManager := auth.NewJWTManager()
+	interceptor := newTestInterceptor(t, jwtManager)
+
+	token, err := jwtManager.GenerateAccessToken(1, "admin@example.com", []entity.Role{entity.RoleAdmin}, true)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	info := &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/Unknown"}
+	_, err = interceptor.Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		t.Errorf("Handler must not be called for method without policy")
+		return nil, nil
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestAuthInterceptorUnverifiedEmail(t *testing.T) {
+	jwtManager := auth.NewJWTManager()
+	interceptor := newTestInterceptor(t, jwtManager)
+
+	token, err := jwtManager.GenerateAccessToken(1, "user@example.com", []entity.Role{entity.RoleUser}, false)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	call := func(method string) error {
+		_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			if hasPermission(ctx, entity.PermissionTaskWrite) {
+				t.Errorf("Expected task:write to be withheld from unverified user")
+			}
+			return nil, nil
+		})
+		return err
+	}
+
+	// По умолчанию неподтвержденный пользователь читает, но не создает задачи
+	if err := call("/task.v1.TaskService/GetTask"); err != nil {
+		t.Errorf("Expected GetTask to be allowed, got %v", err)
+	}
+	err = call("/task.v1.TaskService/CreateTask")
+	if status.Code(err) != codes.PermissionDenied || status.Convert(err).Message() != "email address is not verified" {
+		t.Errorf("Expected PermissionDenied for unverified email, got %v", err)
+	}
+	if err := call("/user.v1.UserService/ResendVerification"); err != nil {
+		t.Errorf("Expected ResendVerification to be allowed, got %v", err)
+	}
+
+	if err := interceptor.roleService.SetUnverifiedPolicy(nil); err != nil {
+		t.Fatalf("SetUnverifiedPolicy: %v", err)
+	}
+	_, err = interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/CreateTask"}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("Expected CreateTask to be allowed without restrictions, got %v", err)
+	}
+	if err := interceptor.roleService.SetUnverifiedPolicy([]entity.Permission{"task:fly"}); err == nil {
+		t.Error("Expected unknown permission to be rejected")
+	}
+}
+
+// fakeUserRepository - IUserRepository, из которого интерцептор читает только GetById
+type fakeUserRepository struct {
+	repository.IUserRepository
+	users map[int]*entity.User
+}
+
+func (r *fakeUserRepository) GetById(ctx context.Context, id int) (*entity.User, error) {
+	return r.users[id], nil
+}
+
+func TestAuthInterceptorTokenWithoutEmailVerifiedClaim(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	jwtManager := auth.NewJWTManager()
+	verifiedAt := time.Now()
+	users := &fakeUserRepository{users: map[int]*entity.User{
+		1: {ID: 1, EmailVerifiedAt: &verifiedAt},
+		2: {ID: 2},
+	}}
+	roleService := usecase.NewRoleService(&fakeRoleRepository{}, users)
+	if err := roleService.LoadPermissions(context.Background()); err != nil {
+		t.Fatalf("Failed to load permissions: %v", err)
+	}
+	interceptor := NewAuthInterceptor(jwtManager, roleService)
+
+	// Токен в формате до появления проверки email: claim email_verified нет
+	call := func(userID int) error {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": userID,
+			"email":   "user@example.com",
+			"roles":   []entity.Role{entity.RoleUser},
+			"exp":     time.Now().Add(time.Minute).Unix(),
+			"iat":     time.Now().Unix(),
+			"type":    "access",
+		}).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err = interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/task.v1.TaskService/CreateTask"}, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	if err := call(1); err != nil {
+		t.Errorf("Expected verified user to create tasks with an old token, got %v", err)
+	}
+	if err := call(2); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for unverified user with an old token, got %v", err)
+	}
+	if err := call(3); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for deleted user, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// methodPolicy описывает, какие права нужны для вызова RPC
+type methodPolicy struct {
+	// permission - право для вызова RPC. Пустое - достаточно аутентификации
+	permission entity.Permission
+	// otherUserPermission - право для действий над чужим профилем
+	otherUserPermission entity.Permission
+}
+
+// methodPolicies - политика доступа для каждого RPC. RPC, которых нет в таблице
+// (и нет в publicMethods), запрещены
+var methodPolicies = map[string]methodPolicy{
+	// TaskService
+	"/task.v1.TaskService/CreateTask":           {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/GetTask":              {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/UpdateTask":           {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/DeleteTask":           {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/ListTasks":            {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/GetTaskHistory":       {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/ListSubtasks":         {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/GetTaskTree":          {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/AddDependency":        {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/RemoveDependency":     {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/ListDependencies":     {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/AssignTask":           {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/UnassignTask":         {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/ShareTask":            {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/UnshareTask":          {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/ListTaskShares":       {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/CreateComment":        {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/ListComments":         {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/UpdateComment":        {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/DeleteComment":        {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/ListCommentRevisions": {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/UploadAttachment":     {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/DownloadAttachment":   {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/ListAttachments":      {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/DeleteAttachment":     {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/CreateLabel":          {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/ListLabels":           {permission: entity.PermissionTaskRead},
+	"/task.v1.TaskService/UpdateLabel":          {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/DeleteLabel":          {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/AttachLabel":          {permission: entity.PermissionTaskWrite},
+	"/task.v1.TaskService/DetachLabel":          {permission: entity.PermissionTaskWrite},
+
+	// ProjectService: доступ к конкретному проекту проверяется по роли участника
+	"/project.v1.ProjectService/CreateProject":       {permission: entity.PermissionTaskWrite},
+	"/project.v1.ProjectService/GetProject":          {permission: entity.PermissionTaskRead},
+	"/project.v1.ProjectService/ListProjects":        {permission: entity.PermissionTaskRead},
+	"/project.v1.ProjectService/UpdateProject":       {permission: entity.PermissionTaskWrite},
+	"/project.v1.ProjectService/DeleteProject":       {permission: entity.PermissionTaskWrite},
+	"/project.v1.ProjectService/SetProjectMember":    {permission: entity.PermissionTaskWrite},
+	"/project.v1.ProjectService/RemoveProjectMember": {permission: entity.PermissionTaskWrite},
+	"/project.v1.ProjectService/ListProjectMembers":  {permission: entity.PermissionTaskRead},
+
+	// UserService
+	"/user.v1.UserService/Logout":             {},
+	"/user.v1.UserService/ListSessions":       {},
+	"/user.v1.UserService/RevokeSession":      {},
+	"/user.v1.UserService/ResendVerification": {},
+	"/user.v1.UserService/CreateUser":         {permission: entity.PermissionUserManage},
+	"/user.v1.UserService/GetUser":            {permission: entity.PermissionUserRead, otherUserPermission: entity.PermissionUserReadAny},
+	"/user.v1.UserService/UpdateUser":         {permission: entity.PermissionUserWrite, otherUserPermission: entity.PermissionUserManage},
+	"/user.v1.UserService/DeleteUser":         {permission: entity.PermissionUserWrite, otherUserPermission: entity.PermissionUserManage},
+	"/user.v1.UserService/ListUsers":          {permission: entity.PermissionUserReadAny},
+	"/user.v1.UserService/SetUserRoles":       {permission: entity.PermissionUserManage},
+	"/user.v1.UserService/UploadAvatar":       {permission: entity.PermissionUserWrite, otherUserPermission: entity.PermissionUserManage},
+}
+
+type permissionsContextKey struct{}
+
+// checkPolicy проверяет, что у вызывающего есть право на вызов RPC
+func checkPolicy(fullMethod string, permissions map[entity.Permission]bool) error {
+	policy, ok := methodPolicies[fullMethod]
+	if !ok {
+		return status.Error(codes.PermissionDenied, "access denied")
+	}
+	if policy.permission != "" && !permissions[policy.permission] {
+		return status.Error(codes.PermissionDenied, "access denied")
+	}
+	return nil
+}
+
+// contextWithPermissions кладет права вызывающего пользователя в контекст
+func contextWithPermissions(ctx context.Context, permissions map[entity.Permission]bool) context.Context {
+	return context.WithValue(ctx, permissionsContextKey{}, permissions)
+}
+
+// hasPermission проверяет право вызывающего пользователя
+func hasPermission(ctx context.Context, permission entity.Permission) bool {
+	permissions, _ := ctx.Value(permissionsContextKey{}).(map[entity.Permission]bool)
+	return permissions[permission]
+}
+
+// authorizeUserTarget проверяет, что вызывающий может действовать над профилем targetUserID:
+// над своим - всегда, над чужим - только с otherUserPermission из политики RPC
+func authorizeUserTarget(ctx context.Context, targetUserID int) error {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+	if targetUserID == userID {
+		return nil
+	}
+
+	method, _ := grpc.Method(ctx)
+	policy := methodPolicies[method]
+	if policy.otherUserPermission == "" || !hasPermission(ctx, policy.otherUserPermission) {
+		return status.Error(codes.PermissionDenied, "access denied")
+	}
+	return nil
+}
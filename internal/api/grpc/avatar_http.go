@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// avatarRoute - HTTP маршрут аватарки на gateway. Он обслуживается напрямую, без gRPC:
+// браузеру нужны Content-Type, кеширующие заголовки и Range, а не поток protobuf сообщений
+const avatarRoute = "/api/v1/users/{id}/avatar"
+
+// avatarURL возвращает URL аватарки пользователя на gateway
+func avatarURL(userID int) string {
+	return fmt.Sprintf("/api/v1/users/%d/avatar", userID)
+}
+
+// userAvatarURL возвращает URL аватарки или пустую строку, если аватарки нет
+func userAvatarURL(user *entity.User) string {
+	if user.AvatarURL == nil {
+		return ""
+	}
+	return avatarURL(user.ID)
+}
+
+// registerAvatarRoute подключает GET и HEAD /api/v1/users/{id}/avatar[?size=64|128|512].
+// Маршрут публичный, как и аватарки в большинстве сервисов: <img> не умеет передавать access token.
+// gRPC DownloadAvatar тоже в publicMethods, чтобы у файла было одно правило доступа
+func (s *Server) registerAvatarRoute(mux *runtime.ServeMux) error {
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		if err := mux.HandlePath(method, avatarRoute, s.serveAvatar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serveAvatar отдает файл аватарки с ETag, Last-Modified, поддержкой Range и 304
+func (s *Server) serveAvatar(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	userID, err := strconv.Atoi(pathParams["id"])
+	if err != nil || userID <= 0 {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+	size := 0
+	if value := r.URL.Query().Get("size"); value != "" {
+		if size, err = strconv.Atoi(value); err != nil {
+			http.Error(w, entity.ErrInvalidAvatarSize.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	avatar, err := s.userService.GetAvatar(r.Context(), userID, size)
+	if err != nil {
+		switch err {
+		case entity.ErrUserNotFound, entity.ErrAvatarNotFound:
+			http.Error(w, "avatar not found", http.StatusNotFound)
+		case entity.ErrInvalidAvatarSize:
+			http.Error(w, "size must be 0, 64, 128 or 512", http.StatusBadRequest)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Файл не читается в память: http.ServeContent читает только запрошенный диапазон,
+	// а на 304 и 412 не читает его вовсе
+	content, err := s.userService.OpenAvatar(r.Context(), avatar, size)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "avatar not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	writeAvatar(w, r, avatar, size, content)
+}
+
+// writeAvatar выставляет заголовки и отдает содержимое через http.ServeContent:
+// он обрабатывает If-None-Match, If-Modified-Since, Range и HEAD
+func writeAvatar(w http.ResponseWriter, r *http.Request, avatar *entity.Avatar, size int, content io.ReadSeeker) {
+	w.Header().Set("Content-Type", avatar.ContentType)
+	w.Header().Set("ETag", avatarETag(avatar, size))
+	// Браузер хранит копию, но каждый раз сверяет ETag: после смены аватарки URL не меняется
+	w.Header().Set("Cache-Control", "no-cache")
+
+	http.ServeContent(w, r, "", avatar.UpdatedAt, content)
+}
+
+// avatarETag строится из SHA-256 полной версии: превью выводятся из нее, поэтому
+// к хешу достаточно добавить размер. У файлов без хеша (загруженных раньше) - хеш ключа,
+// он тоже меняется с каждой загрузкой
+func avatarETag(avatar *entity.Avatar, size int) string {
+	hash := avatar.SHA256
+	if hash == "" {
+		sum := sha256.Sum256([]byte(avatar.StorageKey))
+		hash = hex.EncodeToString(sum[:])
+	}
+	if size != 0 {
+		hash += "-" + strconv.Itoa(size)
+	}
+	return strconv.Quote(hash)
+}
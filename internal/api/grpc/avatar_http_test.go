@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+	"github.com/St1cky1/task-service/internal/usecase"
+)
+
+func TestWriteAvatarCachingAndRange(t *testing.T) {
+	avatar := &entity.Avatar{
+		UserID:    7,
+		Blob:      entity.Blob{StorageKey: "avatars/avatar_7_abc", ContentType: "image/png", SHA256: "deadbeef"},
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	store := &countingStore{BlobStore: storage.NewLocalStore(t.TempDir())}
+	if err := store.Put(context.Background(), avatar.StorageKey, strings.NewReader("0123456789"), 10, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	blobs := usecase.NewBlobService(store)
+	serve := func(header http.Header, size int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, avatarURL(7), nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		recorder := httptest.NewRecorder()
+		content, err := blobs.Open(req.Context(), &avatar.Blob)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		defer content.Close()
+		writeAvatar(recorder, req, avatar, size, content)
+		return recorder
+	}
+
+	resp := serve(nil, 0)
+	if resp.Code != http.StatusOK || resp.Body.String() != "0123456789" {
+		t.Fatalf("Expected full content, got %d %q", resp.Code, resp.Body.String())
+	}
+	if resp.Header().Get("Content-Type") != "image/png" || resp.Header().Get("ETag") != `"deadbeef"` ||
+		resp.Header().Get("Last-Modified") != "Fri, 02 Jan 2026 03:04:05 GMT" {
+		t.Errorf("Unexpected headers %v", resp.Header())
+	}
+
+	store.offsets = nil
+	resp = serve(http.Header{"If-None-Match": {`"deadbeef"`}}, 0)
+	if resp.Code != http.StatusNotModified || len(store.offsets) != 0 {
+		t.Errorf("Expected 304 without reading the file, got %d (reads %v)", resp.Code, store.offsets)
+	}
+	resp = serve(http.Header{"If-None-Match": {`"deadbeef"`}}, 64)
+	if resp.Code != http.StatusOK || resp.Header().Get("ETag") != `"deadbeef-64"` {
+		t.Errorf("Expected variant to have its own ETag, got %d %s", resp.Code, resp.Header().Get("ETag"))
+	}
+
+	// Диапазон читается из хранилища с нужной позиции, а не целым файлом
+	store.offsets = nil
+	resp = serve(http.Header{"Range": {"bytes=2-4"}}, 0)
+	if resp.Code != http.StatusPartialContent || resp.Body.String() != "234" ||
+		resp.Header().Get("Content-Range") != "bytes 2-4/10" {
+		t.Errorf("Expected partial content, got %d %q %s", resp.Code, resp.Body.String(), resp.Header().Get("Content-Range"))
+	}
+	if len(store.offsets) != 1 || store.offsets[0] != 2 {
+		t.Errorf("Expected one ranged read from offset 2, got %v", store.offsets)
+	}
+}
+
+// countingStore запоминает, с каких позиций читались объекты
+type countingStore struct {
+	storage.BlobStore
+	offsets []int64
+}
+
+func (s *countingStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.GetRange(ctx, key, 0)
+}
+
+func (s *countingStore) GetRange(ctx context.Context, key string, offset int64) (io.ReadCloser, error) {
+	s.offsets = append(s.offsets, offset)
+	return s.BlobStore.GetRange(ctx, key, offset)
+}
+
+func TestUserAvatarURL(t *testing.T) {
+	key := "avatars/avatar_3_abc"
+	if got := userAvatarURL(&entity.User{ID: 3, AvatarURL: &key}); got != "/api/v1/users/3/avatar" {
+		t.Errorf("Unexpected avatar URL %q", got)
+	}
+	if got := userAvatarURL(&entity.User{ID: 3}); got != "" {
+		t.Errorf("Expected empty URL without avatar, got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// maxUserAgentLength - длиннее User-Agent не сохраняем
+const maxUserAgentLength = 512
+
+// clientInfo достает User-Agent и IP клиента. Через gateway User-Agent приходит как
+// grpcgateway-user-agent, а IP - последним адресом в x-forwarded-for: его дописывает сам gateway,
+// остальное прислал клиент и доверять этому нельзя. x-forwarded-for учитывается, только если
+// запрос пришел с loopback, то есть от нашего gateway
+func clientInfo(ctx context.Context) entity.ClientInfo {
+	var info entity.ClientInfo
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("grpcgateway-user-agent"); len(values) > 0 {
+		info.UserAgent = values[0]
+	} else if values := md.Get("user-agent"); len(values) > 0 {
+		info.UserAgent = values[0]
+	}
+	if len(info.UserAgent) > maxUserAgentLength {
+		info.UserAgent = info.UserAgent[:maxUserAgentLength]
+	}
+
+	var peerIP net.IP
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		peerIP = net.ParseIP(host)
+	}
+	if peerIP != nil {
+		info.IPAddress = peerIP.String()
+	}
+
+	if peerIP == nil || peerIP.IsLoopback() {
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			forwarded := strings.Split(values[len(values)-1], ",")
+			if ip := net.ParseIP(strings.TrimSpace(forwarded[len(forwarded)-1])); ip != nil {
+				info.IPAddress = ip.String()
+			}
+		}
+	}
+
+	return info
+}
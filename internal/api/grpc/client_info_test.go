@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientInfo(t *testing.T) {
+	withPeer := func(ip string, pairs ...string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5555}})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs(pairs...))
+	}
+
+	// Через gateway: доверяем только последнему адресу, который дописал сам gateway
+	info := clientInfo(withPeer("127.0.0.1",
+		"grpcgateway-user-agent", "browser", "user-agent", "grpc-go", "x-forwarded-for", "1.1.1.1, 203.0.113.5"))
+	if info.UserAgent != "browser" || info.IPAddress != "203.0.113.5" {
+		t.Errorf("Unexpected client info via gateway: %+v", info)
+	}
+
+	// Прямой gRPC вызов: x-forwarded-for от клиента игнорируется
+	info = clientInfo(withPeer("198.51.100.7", "user-agent", "grpc-go", "x-forwarded-for", "1.1.1.1"))
+	if info.UserAgent != "grpc-go" || info.IPAddress != "198.51.100.7" {
+		t.Errorf("Unexpected client info for direct call: %+v", info)
+	}
+}
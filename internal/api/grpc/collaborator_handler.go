@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AssignTask назначает пользователя исполнителем задачи
+func (s *TaskServiceServer) AssignTask(ctx context.Context, req *pb.TaskAssigneeRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskService.AssignTask(ctx, userID, int(req.TaskId), int(req.UserId))
+	if err != nil {
+		return nil, collaboratorError(err)
+	}
+
+	return convertTask(task), nil
+}
+
+// UnassignTask снимает исполнителя с задачи
+func (s *TaskServiceServer) UnassignTask(ctx context.Context, req *pb.TaskAssigneeRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskService.UnassignTask(ctx, userID, int(req.TaskId), int(req.UserId))
+	if err != nil {
+		return nil, collaboratorError(err)
+	}
+
+	return convertTask(task), nil
+}
+
+// ShareTask выдает пользователю доступ к задаче
+func (s *TaskServiceServer) ShareTask(ctx context.Context, req *pb.ShareTaskRequest) (*pb.TaskShare, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.taskService.ShareTask(ctx, userID, int(req.TaskId), int(req.UserId), entity.ShareRole(req.Role))
+	if err != nil {
+		return nil, collaboratorError(err)
+	}
+
+	return convertTaskShare(share), nil
+}
+
+// UnshareTask отзывает доступ к задаче
+func (s *TaskServiceServer) UnshareTask(ctx context.Context, req *pb.UnshareTaskRequest) (*pb.UnshareTaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskService.UnshareTask(ctx, userID, int(req.TaskId), int(req.UserId)); err != nil {
+		return nil, collaboratorError(err)
+	}
+
+	return &pb.UnshareTaskResponse{Success: true}, nil
+}
+
+// ListTaskShares возвращает, кому выдан доступ к задаче
+func (s *TaskServiceServer) ListTaskShares(ctx context.Context, req *pb.ListTaskSharesRequest) (*pb.ListTaskSharesResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.taskService.ListTaskShares(ctx, userID, int(req.TaskId))
+	if err != nil {
+		return nil, collaboratorError(err)
+	}
+
+	pbShares := make([]*pb.TaskShare, len(shares))
+	for i := range shares {
+		pbShares[i] = convertTaskShare(&shares[i])
+	}
+	return &pb.ListTaskSharesResponse{Shares: pbShares}, nil
+}
+
+// collaboratorError переводит ошибки операций с исполнителями и доступом в gRPC статус
+func collaboratorError(err error) error {
+	switch err {
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrUserNotFound:
+		return status.Error(codes.NotFound, "user not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrTaskArchived:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case entity.ErrInvalidShare:
+		return status.Error(codes.InvalidArgument, "role must be viewer or editor and the user must not be the owner")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertTaskShare конвертирует entity.TaskShare в protobuf
+func convertTaskShare(share *entity.TaskShare) *pb.TaskShare {
+	return &pb.TaskShare{
+		TaskId:    int32(share.TaskID),
+		UserId:    int32(share.UserID),
+		Role:      string(share.Role),
+		CreatedAt: share.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateComment добавляет комментарий к задаче или ответ на комментарий
+func (s *TaskServiceServer) CreateComment(ctx context.Context, req *pb.CreateCommentRequest) (*pb.TaskComment, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	commentReq := &entity.CreateCommentRequest{
+		TaskID: int(req.TaskId),
+		Body:   req.Body,
+	}
+	if req.ParentCommentId != 0 {
+		parentID := int(req.ParentCommentId)
+		commentReq.ParentCommentID = &parentID
+	}
+
+	comment, err := s.taskService.CreateComment(ctx, userID, commentReq)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return convertComment(comment), nil
+}
+
+// ListComments возвращает страницу комментариев задачи или ответов на комментарий
+func (s *TaskServiceServer) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &entity.ListCommentsRequest{
+		TaskID: int(req.TaskId),
+		Page: entity.PageRequest{
+			PageSize:  int(req.PageSize),
+			PageToken: req.PageToken,
+			SortDir:   entity.SortDirection(req.SortDirection),
+			Total:     entity.TotalMode(req.TotalMode),
+		},
+	}
+	if req.ParentCommentId != 0 {
+		parentID := int(req.ParentCommentId)
+		listReq.ParentCommentID = &parentID
+	}
+
+	page, err := s.taskService.ListComments(ctx, userID, listReq)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	pbComments := make([]*pb.TaskComment, len(page.Comments))
+	for i := range page.Comments {
+		pbComments[i] = convertComment(&page.Comments[i])
+	}
+
+	return &pb.ListCommentsResponse{
+		Comments:       pbComments,
+		NextPageToken:  page.NextPageToken,
+		Total:          page.Total,
+		TotalEstimated: page.TotalEstimated,
+	}, nil
+}
+
+// UpdateComment меняет текст комментария
+func (s *TaskServiceServer) UpdateComment(ctx context.Context, req *pb.UpdateCommentRequest) (*pb.TaskComment, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.taskService.UpdateComment(ctx, userID, int(req.TaskId), int(req.CommentId), req.Body)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return convertComment(comment), nil
+}
+
+// DeleteComment удаляет комментарий
+func (s *TaskServiceServer) DeleteComment(ctx context.Context, req *pb.DeleteCommentRequest) (*pb.DeleteCommentResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskService.DeleteComment(ctx, userID, int(req.TaskId), int(req.CommentId)); err != nil {
+		return nil, commentError(err)
+	}
+
+	return &pb.DeleteCommentResponse{Success: true}, nil
+}
+
+// ListCommentRevisions возвращает историю правок комментария
+func (s *TaskServiceServer) ListCommentRevisions(ctx context.Context, req *pb.ListCommentRevisionsRequest) (*pb.ListCommentRevisionsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.taskService.ListCommentRevisions(ctx, userID, int(req.TaskId), int(req.CommentId))
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	pbRevisions := make([]*pb.CommentRevision, len(revisions))
+	for i, revision := range revisions {
+		pbRevisions[i] = &pb.CommentRevision{
+			Id:         int32(revision.ID),
+			Body:       revision.Body,
+			ReplacedAt: revision.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &pb.ListCommentRevisionsResponse{Revisions: pbRevisions}, nil
+}
+
+// commentError переводит ошибки операций с комментариями в gRPC статус
+func commentError(err error) error {
+	switch err {
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrCommentNotFound:
+		return status.Error(codes.NotFound, "comment not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrInvalidComment:
+		return status.Error(codes.InvalidArgument, "comment must be 1-10000 characters and reply to a comment of the same task")
+	case entity.ErrInvalidPageToken, entity.ErrInvalidSort, entity.ErrInvalidTotalMode:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertComment конвертирует entity.TaskComment в protobuf
+func convertComment(comment *entity.TaskComment) *pb.TaskComment {
+	return &pb.TaskComment{
+		Id:              int32(comment.ID),
+		TaskId:          int32(comment.TaskID),
+		ParentCommentId: int32(formatOptionalID(comment.ParentCommentID)),
+		AuthorId:        int32(comment.AuthorID),
+		Body:            comment.Body,
+		CreatedAt:       comment.CreatedAt.Format(time.RFC3339),
+		EditedAt:        formatOptionalTime(comment.EditedAt),
+		Deleted:         comment.DeletedAt != nil,
+		ReplyCount:      int32(comment.ReplyCount),
+	}
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddDependency делает blocker_id блокирующей задачей для task_id
+func (s *TaskServiceServer) AddDependency(ctx context.Context, req *pb.TaskDependencyRequest) (*pb.TaskDependencies, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.taskService.AddDependency(ctx, userID, int(req.TaskId), int(req.BlockerId))
+	if err != nil {
+		return nil, dependencyError(err)
+	}
+
+	return convertDependencies(deps), nil
+}
+
+// RemoveDependency снимает блокировку task_id задачей blocker_id
+func (s *TaskServiceServer) RemoveDependency(ctx context.Context, req *pb.TaskDependencyRequest) (*pb.TaskDependencies, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.taskService.RemoveDependency(ctx, userID, int(req.TaskId), int(req.BlockerId))
+	if err != nil {
+		return nil, dependencyError(err)
+	}
+
+	return convertDependencies(deps), nil
+}
+
+// ListDependencies возвращает блокирующие и блокируемые задачи
+func (s *TaskServiceServer) ListDependencies(ctx context.Context, req *pb.ListDependenciesRequest) (*pb.TaskDependencies, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.taskService.ListDependencies(ctx, userID, int(req.TaskId))
+	if err != nil {
+		return nil, dependencyError(err)
+	}
+
+	return convertDependencies(deps), nil
+}
+
+// dependencyError переводит ошибки операций с зависимостями в gRPC статус
+func dependencyError(err error) error {
+	switch err {
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrDependencyCycle, entity.ErrTaskArchived:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertDependencies конвертирует зависимости задачи в protobuf
+func convertDependencies(deps *entity.TaskDependencies) *pb.TaskDependencies {
+	pbDeps := &pb.TaskDependencies{
+		BlockedBy: make([]*pb.TaskResponse, len(deps.BlockedBy)),
+		Blocks:    make([]*pb.TaskResponse, len(deps.Blocks)),
+	}
+	for i := range deps.BlockedBy {
+		pbDeps.BlockedBy[i] = convertTask(&deps.BlockedBy[i])
+	}
+	for i := range deps.Blocks {
+		pbDeps.Blocks[i] = convertTask(&deps.Blocks[i])
+	}
+	return pbDeps
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Версия задачи передается через HTTP как ETag: gateway переименовывает
+// metadata "etag" в заголовок ответа ETag, а заголовок If-Match - в metadata "if-match"
+const (
+	etagMetadataKey    = "etag"
+	ifMatchMetadataKey = "if-match"
+)
+
+// formatETag превращает версию в значение ETag: 3 -> "3"
+func formatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// setTaskETag отдает версию задачи в заголовке ответа
+func setTaskETag(ctx context.Context, version int) {
+	// Ошибка возможна только вне gRPC вызова (например, в тестах)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(etagMetadataKey, formatETag(version)))
+}
+
+// expectedVersion возвращает ожидаемую версию из поля запроса, а если оно не задано - из If-Match.
+// 0 означает "без проверки"
+func expectedVersion(ctx context.Context, requested int32) (int, error) {
+	if requested != 0 {
+		return int(requested), nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(ifMatchMetadataKey)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	value := strings.TrimSpace(values[0])
+	if value == "*" {
+		return 0, nil
+	}
+
+	// If-Match использует строгое сравнение, поэтому слабые (W/"...") и списки ETag не принимаем
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "If-Match must be a single ETag returned by the server")
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "If-Match must be a single ETag returned by the server")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int32
+		ifMatch   string
+		want      int
+		wantCode  codes.Code
+	}{
+		{name: "no version", want: 0},
+		{name: "request field", requested: 4, want: 4},
+		{name: "request field wins", requested: 4, ifMatch: `"7"`, want: 4},
+		{name: "if-match", ifMatch: `"7"`, want: 7},
+		{name: "if-match any", ifMatch: "*", want: 0},
+		{name: "weak etag", ifMatch: `W/"7"`, wantCode: codes.InvalidArgument},
+		{name: "not a version", ifMatch: `"abc"`, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ifMatch != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ifMatchMetadataKey, tt.ifMatch))
+			}
+
+			got, err := expectedVersion(ctx, tt.requested)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected version %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatETagRoundTrip(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ifMatchMetadataKey, formatETag(12)))
+	got, err := expectedVersion(ctx, 0)
+	if err != nil || got != 12 {
+		t.Errorf("Expected version 12, got %d (%v)", got, err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"net/textproto"
 
+	"github.com/St1cky1/task-service/internal/infrastructure/auth"
 	"github.com/St1cky1/task-service/internal/usecase"
 	pb "github.com/St1cky1/task-service/proto/pb"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -14,19 +16,35 @@ import (
 
 // Server представляет gRPC сервер с поддержкой Gateway
 type Server struct {
-	grpcServer  *grpc.Server
-	taskService *usecase.TaskService
-	userService *usecase.UserService
-	authService *usecase.AuthService
+	grpcServer     *grpc.Server
+	taskService    *usecase.TaskService
+	userService    *usecase.UserService
+	authService    *usecase.AuthService
+	roleService    *usecase.RoleService
+	projectService *usecase.ProjectService
 }
 
 // NewGRPCServer создает новый gRPC сервер
-func NewGRPCServer(taskService *usecase.TaskService, userService *usecase.UserService, authService *usecase.AuthService) *Server {
+func NewGRPCServer(
+	taskService *usecase.TaskService,
+	userService *usecase.UserService,
+	authService *usecase.AuthService,
+	roleService *usecase.RoleService,
+	projectService *usecase.ProjectService,
+	jwtManager *auth.JWTManager,
+) *Server {
+	authInterceptor := NewAuthInterceptor(jwtManager, roleService)
+
 	return &Server{
-		grpcServer:  grpc.NewServer(),
-		taskService: taskService,
-		userService: userService,
-		authService: authService,
+		grpcServer: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(authInterceptor.Unary()),
+			grpc.ChainStreamInterceptor(authInterceptor.Stream()),
+		),
+		taskService:    taskService,
+		userService:    userService,
+		authService:    authService,
+		roleService:    roleService,
+		projectService: projectService,
 	}
 }
 
@@ -42,9 +60,13 @@ func (s *Server) Start(port string) error {
 	pb.RegisterTaskServiceServer(s.grpcServer, taskHandler)
 
 	// Регистрируем UserService
-	userHandler := NewUserServiceServer(s.userService, s.authService)
+	userHandler := NewUserServiceServer(s.userService, s.authService, s.roleService)
 	pb.RegisterUserServiceServer(s.grpcServer, userHandler)
 
+	// Регистрируем ProjectService
+	projectHandler := NewProjectServiceServer(s.projectService)
+	pb.RegisterProjectServiceServer(s.grpcServer, projectHandler)
+
 	return s.grpcServer.Serve(listener)
 }
 
@@ -55,7 +77,10 @@ func (s *Server) Stop() {
 
 // StartGateway запускает gRPC Gateway на указанном порту
 func (s *Server) StartGateway(ctx context.Context, grpcPort, gatewayPort string) error {
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
 
 	// Подключаемся к gRPC серверу
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
@@ -69,6 +94,15 @@ func (s *Server) StartGateway(ctx context.Context, grpcPort, gatewayPort string)
 		return err
 	}
 
+	err = pb.RegisterProjectServiceHandlerFromEndpoint(ctx, mux, "localhost:"+grpcPort, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := s.registerAvatarRoute(mux); err != nil {
+		return err
+	}
+
 	// Запускаем HTTP сервер
 	server := &http.Server{
 		Addr:    ":" + gatewayPort,
@@ -77,3 +111,27 @@ func (s *Server) StartGateway(ctx context.Context, grpcPort, gatewayPort string)
 
 	return server.ListenAndServe()
 }
+
+// incomingHeaderMatcher решает, какие HTTP заголовки пробрасываются в gRPC metadata
+func incomingHeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "Authorization":
+		// runtime сам пробрасывает Authorization как metadata "authorization",
+		// поэтому не дублируем его с префиксом grpcgateway-
+		return "", false
+	case "If-Match":
+		return ifMatchMetadataKey, true
+	default:
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+// outgoingHeaderMatcher решает, какие gRPC metadata ответа становятся HTTP заголовками
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case etagMetadataKey:
+		return "ETag", true
+	default:
+		return runtime.MetadataHeaderPrefix + key, true
+	}
+}
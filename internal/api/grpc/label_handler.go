@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateLabel создает метку вызывающего пользователя
+func (s *TaskServiceServer) CreateLabel(ctx context.Context, req *pb.CreateLabelRequest) (*pb.Label, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := s.taskService.CreateLabel(ctx, userID, &entity.CreateLabelRequest{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		return nil, labelError(err)
+	}
+
+	return convertLabel(label), nil
+}
+
+// ListLabels возвращает метки вызывающего пользователя
+func (s *TaskServiceServer) ListLabels(ctx context.Context, req *pb.ListLabelsRequest) (*pb.ListLabelsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.taskService.ListLabels(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.ListLabelsResponse{Labels: convertLabels(labels)}, nil
+}
+
+// UpdateLabel переименовывает метку и/или меняет ее цвет
+func (s *TaskServiceServer) UpdateLabel(ctx context.Context, req *pb.UpdateLabelRequest) (*pb.Label, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := s.taskService.UpdateLabel(ctx, userID, int(req.Id), &entity.UpdateLabelRequest{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		return nil, labelError(err)
+	}
+
+	return convertLabel(label), nil
+}
+
+// DeleteLabel удаляет метку и снимает ее со всех задач
+func (s *TaskServiceServer) DeleteLabel(ctx context.Context, req *pb.DeleteLabelRequest) (*pb.DeleteLabelResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskService.DeleteLabel(ctx, userID, int(req.Id)); err != nil {
+		return nil, labelError(err)
+	}
+
+	return &pb.DeleteLabelResponse{Success: true}, nil
+}
+
+// AttachLabel привязывает метку к задаче
+func (s *TaskServiceServer) AttachLabel(ctx context.Context, req *pb.TaskLabelRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskService.AttachLabel(ctx, userID, int(req.TaskId), int(req.LabelId))
+	if err != nil {
+		return nil, labelError(err)
+	}
+
+	return convertTask(task), nil
+}
+
+// DetachLabel отвязывает метку от задачи
+func (s *TaskServiceServer) DetachLabel(ctx context.Context, req *pb.TaskLabelRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskService.DetachLabel(ctx, userID, int(req.TaskId), int(req.LabelId))
+	if err != nil {
+		return nil, labelError(err)
+	}
+
+	return convertTask(task), nil
+}
+
+// labelError переводит ошибки операций с метками в gRPC статус
+func labelError(err error) error {
+	switch err {
+	case entity.ErrLabelNotFound:
+		return status.Error(codes.NotFound, "label not found")
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrTaskArchived:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case entity.ErrLabelExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case entity.ErrInvalidLabelData:
+		return status.Error(codes.InvalidArgument, "label name must be 1-100 characters and color must be #rrggbb")
+	case entity.ErrNoFieldsToUpdate:
+		return status.Error(codes.InvalidArgument, "no fields to update")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertLabel конвертирует entity.Label в protobuf
+func convertLabel(label *entity.Label) *pb.Label {
+	return &pb.Label{
+		Id:        int32(label.ID),
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func convertLabels(labels []entity.Label) []*pb.Label {
+	pbLabels := make([]*pb.Label, len(labels))
+	for i := range labels {
+		pbLabels[i] = convertLabel(&labels[i])
+	}
+	return pbLabels
+}
@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/usecase"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProjectServiceServer реализует gRPC ProjectService
+type ProjectServiceServer struct {
+	pb.UnimplementedProjectServiceServer
+	projectService *usecase.ProjectService
+}
+
+// NewProjectServiceServer создает новый ProjectServiceServer
+func NewProjectServiceServer(projectService *usecase.ProjectService) *ProjectServiceServer {
+	return &ProjectServiceServer{
+		projectService: projectService,
+	}
+}
+
+// CreateProject создает проект, вызывающий становится его владельцем
+func (s *ProjectServiceServer) CreateProject(ctx context.Context, req *pb.CreateProjectRequest) (*pb.Project, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectService.CreateProject(ctx, userID, &entity.CreateProjectRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	return convertProject(project), nil
+}
+
+// GetProject возвращает проект участнику
+func (s *ProjectServiceServer) GetProject(ctx context.Context, req *pb.GetProjectRequest) (*pb.Project, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectService.GetProject(ctx, userID, int(req.Id))
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	return convertProject(project), nil
+}
+
+// ListProjects возвращает проекты вызывающего пользователя
+func (s *ProjectServiceServer) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectService.ListProjects(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbProjects := make([]*pb.Project, len(projects))
+	for i := range projects {
+		pbProjects[i] = convertProject(&projects[i])
+	}
+	return &pb.ListProjectsResponse{Projects: pbProjects}, nil
+}
+
+// UpdateProject переименовывает проект и/или меняет описание
+func (s *ProjectServiceServer) UpdateProject(ctx context.Context, req *pb.UpdateProjectRequest) (*pb.Project, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectService.UpdateProject(ctx, userID, int(req.Id), &entity.UpdateProjectRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	return convertProject(project), nil
+}
+
+// DeleteProject удаляет проект и архивирует его задачи
+func (s *ProjectServiceServer) DeleteProject(ctx context.Context, req *pb.DeleteProjectRequest) (*pb.DeleteProjectResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectService.DeleteProject(ctx, userID, int(req.Id)); err != nil {
+		return nil, projectError(err)
+	}
+
+	return &pb.DeleteProjectResponse{Success: true}, nil
+}
+
+// SetProjectMember добавляет участника проекта или меняет его роль
+func (s *ProjectServiceServer) SetProjectMember(ctx context.Context, req *pb.SetProjectMemberRequest) (*pb.ProjectMember, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.projectService.SetProjectMember(ctx, userID, int(req.ProjectId), int(req.UserId), entity.ProjectRole(req.Role))
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	return convertProjectMember(member), nil
+}
+
+// RemoveProjectMember исключает участника из проекта
+func (s *ProjectServiceServer) RemoveProjectMember(ctx context.Context, req *pb.RemoveProjectMemberRequest) (*pb.RemoveProjectMemberResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectService.RemoveProjectMember(ctx, userID, int(req.ProjectId), int(req.UserId)); err != nil {
+		return nil, projectError(err)
+	}
+
+	return &pb.RemoveProjectMemberResponse{Success: true}, nil
+}
+
+// ListProjectMembers возвращает участников проекта
+func (s *ProjectServiceServer) ListProjectMembers(ctx context.Context, req *pb.ListProjectMembersRequest) (*pb.ListProjectMembersResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.projectService.ListProjectMembers(ctx, userID, int(req.ProjectId))
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	pbMembers := make([]*pb.ProjectMember, len(members))
+	for i := range members {
+		pbMembers[i] = convertProjectMember(&members[i])
+	}
+	return &pb.ListProjectMembersResponse{Members: pbMembers}, nil
+}
+
+// projectError переводит ошибки операций с проектами в gRPC статус
+func projectError(err error) error {
+	switch err {
+	case entity.ErrProjectNotFound:
+		return status.Error(codes.NotFound, "project not found")
+	case entity.ErrUserNotFound:
+		return status.Error(codes.NotFound, "user not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrInvalidProject:
+		return status.Error(codes.InvalidArgument, "project name must be 1-255 characters")
+	case entity.ErrInvalidMember:
+		return status.Error(codes.InvalidArgument, "role must be admin, editor or viewer and the user must not be the project owner")
+	case entity.ErrNoFieldsToUpdate:
+		return status.Error(codes.InvalidArgument, "no fields to update")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertProject конвертирует entity.Project в protobuf
+func convertProject(project *entity.Project) *pb.Project {
+	return &pb.Project{
+		Id:          int32(project.ID),
+		Name:        project.Name,
+		Description: project.Description,
+		OwnerId:     int32(project.OwnerID),
+		CreatedAt:   project.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   project.UpdatedAt.Format(time.RFC3339),
+		Role:        string(project.Role),
+	}
+}
+
+// convertProjectMember конвертирует entity.ProjectMember в protobuf
+func convertProjectMember(member *entity.ProjectMember) *pb.ProjectMember {
+	return &pb.ProjectMember{
+		ProjectId: int32(member.ProjectID),
+		UserId:    int32(member.UserID),
+		Role:      string(member.Role),
+		CreatedAt: member.CreatedAt.Format(time.RFC3339),
+	}
+}
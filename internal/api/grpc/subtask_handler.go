@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	pb "github.com/St1cky1/task-service/proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSubtasks возвращает страницу прямых подзадач задачи
+func (s *TaskServiceServer) ListSubtasks(ctx context.Context, req *pb.ListSubtasksRequest) (*pb.ListTasksResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.taskService.ListSubtasks(ctx, int(req.Id), userID, &entity.ListTasksRequest{
+		Status: req.Status,
+		Page: entity.PageRequest{
+			PageSize:  int(req.PageSize),
+			PageToken: req.PageToken,
+			SortBy:    req.SortBy,
+			SortDir:   entity.SortDirection(req.SortDirection),
+			Total:     entity.TotalMode(req.TotalMode),
+		},
+	})
+	if err != nil {
+		return nil, subtaskError(err)
+	}
+
+	pbTasks := make([]*pb.TaskResponse, len(page.Tasks))
+	for i := range page.Tasks {
+		pbTasks[i] = convertTask(&page.Tasks[i])
+	}
+
+	return &pb.ListTasksResponse{
+		Tasks:          pbTasks,
+		NextPageToken:  page.NextPageToken,
+		Total:          page.Total,
+		TotalEstimated: page.TotalEstimated,
+	}, nil
+}
+
+// GetTaskTree возвращает задачу с подзадачами и прогрессом выполнения
+func (s *TaskServiceServer) GetTaskTree(ctx context.Context, req *pb.GetTaskTreeRequest) (*pb.TaskTreeNode, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.taskService.GetTaskTree(ctx, int(req.Id), userID, int(req.Depth))
+	if err != nil {
+		return nil, subtaskError(err)
+	}
+
+	return convertTaskNode(tree), nil
+}
+
+// subtaskError переводит ошибки чтения подзадач в gRPC статус
+func subtaskError(err error) error {
+	switch err {
+	case entity.ErrTaskNotFound:
+		return status.Error(codes.NotFound, "task not found")
+	case entity.ErrForbidden:
+		return status.Error(codes.PermissionDenied, "access denied")
+	case entity.ErrInvalidFilter, entity.ErrInvalidPageToken, entity.ErrInvalidSort, entity.ErrInvalidTotalMode:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertTaskNode конвертирует дерево задач в protobuf
+func convertTaskNode(node *entity.TaskNode) *pb.TaskTreeNode {
+	pbNode := &pb.TaskTreeNode{
+		Task:     convertTask(&node.Task),
+		Progress: int32(node.Progress),
+		Children: make([]*pb.TaskTreeNode, len(node.Children)),
+	}
+	for i, child := range node.Children {
+		pbNode.Children[i] = convertTaskNode(child)
+	}
+	return pbNode
+}
@@ -2,12 +2,15 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/usecase"
 	pb "github.com/St1cky1/task-service/proto/pb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // TaskServiceServer реализует gRPC TaskService
@@ -25,40 +28,64 @@ func NewTaskServiceServer(taskService *usecase.TaskService) *TaskServiceServer {
 
 // CreateTask создает новую задачу
 func (s *TaskServiceServer) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Владелец задачи всегда вызывающий пользователь, owner_id из запроса игнорируем
 	taskReq := &entity.CreateTaskRequest{
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      entity.TaskStatus(req.Status),
-		OwnerId:     int(req.OwnerId),
+		OwnerId:     userID,
+		Priority:    entity.TaskPriority(req.Priority),
+	}
+	if req.ParentId != 0 {
+		parentID := int(req.ParentId)
+		taskReq.ParentID = &parentID
+	}
+	if req.ProjectId != 0 {
+		projectID := int(req.ProjectId)
+		taskReq.ProjectID = &projectID
+	}
+	if taskReq.StartAt, err = parseOptionalTime(req.StartAt); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "start_at must be in RFC3339 format")
+	}
+	if taskReq.DueAt, err = parseOptionalTime(req.DueAt); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "due_at must be in RFC3339 format")
 	}
 
-	task, err := s.taskService.CreateTask(ctx, taskReq, int(req.OwnerId))
+	task, err := s.taskService.CreateTask(ctx, taskReq, userID)
 	if err != nil {
 		switch err {
 		case entity.ErrUserNotFound:
 			return nil, status.Error(codes.NotFound, "user not found")
 		case entity.ErrInvalidTaskData:
 			return nil, status.Error(codes.InvalidArgument, "invalid task data")
+		case entity.ErrInvalidParent:
+			return nil, status.Error(codes.InvalidArgument, "parent task not found")
+		case entity.ErrProjectNotFound:
+			return nil, status.Error(codes.NotFound, "project not found")
+		case entity.ErrForbidden:
+			return nil, status.Error(codes.PermissionDenied, "access denied")
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
-	return &pb.TaskResponse{
-		Id:          int32(task.ID),
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      string(task.Status),
-		OwnerId:     int32(task.OwnerId),
-		CreatedAt:   task.CreatedAt.String(),
-		UpdatedAt:   task.UpdatedAt.String(),
-	}, nil
+	setTaskETag(ctx, task.Version)
+	return convertTask(task), nil
 }
 
 // GetTask получает задачу по ID
 func (s *TaskServiceServer) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.TaskResponse, error) {
-	// Для простоты используем первого пользователя
-	task, err := s.taskService.GetTask(ctx, int(req.Id), 1)
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskService.GetTask(ctx, int(req.Id), userID)
 	if err != nil {
 		switch err {
 		case entity.ErrTaskNotFound:
@@ -70,86 +97,329 @@ func (s *TaskServiceServer) GetTask(ctx context.Context, req *pb.GetTaskRequest)
 		}
 	}
 
-	return &pb.TaskResponse{
-		Id:          int32(task.ID),
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      string(task.Status),
-		OwnerId:     int32(task.OwnerId),
-		CreatedAt:   task.CreatedAt.String(),
-		UpdatedAt:   task.UpdatedAt.String(),
-	}, nil
+	setTaskETag(ctx, task.Version)
+	return convertTask(task), nil
 }
 
 // UpdateTask обновляет задачу
 func (s *TaskServiceServer) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.TaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	updateReq := &entity.UpdateTaskRequest{
-		Title:       req.Title,
-		Status:      entity.TaskStatus(req.Status),
-		Description: req.Description,
+		Title:           req.Title,
+		Status:          entity.TaskStatus(req.Status),
+		Description:     req.Description,
+		ExpectedVersion: version,
+	}
+	if req.StartAt != nil {
+		startAt, err := parseOptionalTime(*req.StartAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "start_at must be in RFC3339 format")
+		}
+		updateReq.StartAt = &entity.OptionalTime{Time: startAt}
+	}
+	if req.DueAt != nil {
+		dueAt, err := parseOptionalTime(*req.DueAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "due_at must be in RFC3339 format")
+		}
+		updateReq.DueAt = &entity.OptionalTime{Time: dueAt}
+	}
+	if req.Priority != nil {
+		priority := entity.TaskPriority(*req.Priority)
+		updateReq.Priority = &priority
+	}
+	if req.ParentId != nil {
+		updateReq.ParentID = &entity.OptionalID{}
+		if *req.ParentId != 0 {
+			parentID := int(*req.ParentId)
+			updateReq.ParentID.ID = &parentID
+		}
 	}
 
-	task, err := s.taskService.UpdateTask(ctx, int(req.Id), 1, updateReq)
+	task, err := s.taskService.UpdateTask(ctx, int(req.Id), userID, updateReq)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTransition) || errors.Is(err, entity.ErrTaskBlocked) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		switch err {
 		case entity.ErrTaskNotFound:
 			return nil, status.Error(codes.NotFound, "task not found")
 		case entity.ErrNoFieldsToUpdate:
 			return nil, status.Error(codes.InvalidArgument, "no fields to update")
+		case entity.ErrInvalidTaskData:
+			return nil, status.Error(codes.InvalidArgument, "invalid task data")
+		case entity.ErrInvalidParent:
+			return nil, status.Error(codes.InvalidArgument, "parent task not found")
+		case entity.ErrTaskCycle, entity.ErrTaskArchived:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case entity.ErrForbidden:
+			return nil, status.Error(codes.PermissionDenied, "access denied")
+		case entity.ErrVersionMismatch:
+			return nil, status.Error(codes.Aborted, "task was modified by another request, reload it and retry")
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	setTaskETag(ctx, task.Version)
+	return convertTask(task), nil
+}
+
+// DeleteTask удаляет задачу
+func (s *TaskServiceServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.taskService.DeleteTask(ctx, int(req.Id), userID, version, entity.SubtaskPolicy(req.Subtasks))
+	if err != nil {
+		switch err {
+		case entity.ErrInvalidTaskData:
+			return nil, status.Error(codes.InvalidArgument, "subtasks must be cascade or reparent")
+		case entity.ErrTaskNotFound:
+			return nil, status.Error(codes.NotFound, "task not found")
 		case entity.ErrForbidden:
 			return nil, status.Error(codes.PermissionDenied, "access denied")
+		case entity.ErrVersionMismatch:
+			return nil, status.Error(codes.Aborted, "task was modified by another request, reload it and retry")
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
+	return &pb.DeleteTaskResponse{Success: true}, nil
+}
+
+// ListTasks получает список задач
+func (s *TaskServiceServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &entity.ListTasksRequest{
+		Mode:       entity.TaskListMode(req.Mode),
+		Status:     req.Status,
+		Priority:   entity.TaskPriority(req.Priority),
+		Overdue:    req.Overdue,
+		LabelIDs:   make([]int, len(req.LabelIds)),
+		LabelMatch: entity.LabelMatch(req.LabelMatch),
+		Archived:   req.Archived,
+		Page: entity.PageRequest{
+			PageSize:  int(req.PageSize),
+			PageToken: req.PageToken,
+			SortBy:    req.SortBy,
+			SortDir:   entity.SortDirection(req.SortDirection),
+			Total:     entity.TotalMode(req.TotalMode),
+		},
+	}
+
+	for i, labelID := range req.LabelIds {
+		listReq.LabelIDs[i] = int(labelID)
+	}
+	if req.ProjectId != 0 {
+		projectID := int(req.ProjectId)
+		listReq.ProjectID = &projectID
+	}
+	if listReq.DueBefore, err = parseOptionalTime(req.DueBefore); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "due_before must be in RFC3339 format")
+	}
+	if listReq.DueAfter, err = parseOptionalTime(req.DueAfter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "due_after must be in RFC3339 format")
+	}
+
+	page, err := s.taskService.ListTasks(ctx, userID, listReq)
+	if err != nil {
+		switch err {
+		case entity.ErrInvalidFilter, entity.ErrInvalidPageToken, entity.ErrInvalidSort, entity.ErrInvalidTotalMode:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case entity.ErrProjectNotFound:
+			return nil, status.Error(codes.NotFound, "project not found")
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	pbTasks := make([]*pb.TaskResponse, len(page.Tasks))
+	for i, task := range page.Tasks {
+		pbTasks[i] = convertTask(&task)
+	}
+
+	return &pb.ListTasksResponse{
+		Tasks:          pbTasks,
+		NextPageToken:  page.NextPageToken,
+		Total:          page.Total,
+		TotalEstimated: page.TotalEstimated,
+	}, nil
+}
+
+// convertTask конвертирует entity.Task в protobuf
+func convertTask(task *entity.Task) *pb.TaskResponse {
 	return &pb.TaskResponse{
 		Id:          int32(task.ID),
 		Title:       task.Title,
 		Description: task.Description,
 		Status:      string(task.Status),
 		OwnerId:     int32(task.OwnerId),
-		CreatedAt:   task.CreatedAt.String(),
-		UpdatedAt:   task.UpdatedAt.String(),
-	}, nil
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
+		Version:     int32(task.Version),
+		StartAt:     formatOptionalTime(task.StartAt),
+		DueAt:       formatOptionalTime(task.DueAt),
+		Priority:    string(task.Priority),
+		CompletedAt: formatOptionalTime(task.CompletedAt),
+		CancelledAt: formatOptionalTime(task.CancelledAt),
+		Labels:      convertLabels(task.Labels),
+		ParentId:    int32(formatOptionalID(task.ParentID)),
+		AssigneeIds: convertIDs(task.AssigneeIDs),
+		ProjectId:   int32(formatOptionalID(task.ProjectID)),
+		ArchivedAt:  formatOptionalTime(task.ArchivedAt),
+	}
 }
 
-// DeleteTask удаляет задачу
-func (s *TaskServiceServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
-	err := s.taskService.DeleteTask(ctx, int(req.Id), 1)
+// convertIDs конвертирует список ID в protobuf
+func convertIDs(ids []int) []int32 {
+	pbIDs := make([]int32, len(ids))
+	for i, id := range ids {
+		pbIDs[i] = int32(id)
+	}
+	return pbIDs
+}
+
+// GetTaskHistory возвращает историю изменений задачи
+func (s *TaskServiceServer) GetTaskHistory(ctx context.Context, req *pb.GetTaskHistoryRequest) (*pb.GetTaskHistoryResponse, error) {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	historyReq := &entity.TaskHistoryRequest{
+		TaskID:  int(req.Id),
+		Action:  entity.ActionType(req.Action),
+		ActorID: int(req.ActorId),
+		Page: entity.PageRequest{
+			PageSize:  int(req.PageSize),
+			PageToken: req.PageToken,
+			SortDir:   entity.SortDirection(req.SortDirection),
+		},
+	}
+	if historyReq.From, err = parseOptionalTime(req.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be in RFC3339 format")
+	}
+	if historyReq.To, err = parseOptionalTime(req.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be in RFC3339 format")
+	}
+
+	page, err := s.taskService.GetTaskHistory(ctx, userID, hasPermission(ctx, entity.PermissionTaskReadAny), historyReq)
 	if err != nil {
 		switch err {
 		case entity.ErrTaskNotFound:
 			return nil, status.Error(codes.NotFound, "task not found")
 		case entity.ErrForbidden:
 			return nil, status.Error(codes.PermissionDenied, "access denied")
+		case entity.ErrInvalidFilter, entity.ErrInvalidPageToken, entity.ErrInvalidSort, entity.ErrInvalidTotalMode:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
-	return &pb.DeleteTaskResponse{Success: true}, nil
+	pbEntries := make([]*pb.TaskHistoryEntry, len(page.Entries))
+	for i, entry := range page.Entries {
+		pbEntry, err := convertTaskHistoryEntry(entry)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		pbEntries[i] = pbEntry
+	}
+
+	return &pb.GetTaskHistoryResponse{
+		Entries:       pbEntries,
+		NextPageToken: page.NextPageToken,
+	}, nil
 }
 
-// ListTasks получает список задач
-func (s *TaskServiceServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
-	tasks, err := s.taskService.ListTasks(ctx, 1, req.Status)
+// parseOptionalTime разбирает необязательную RFC3339 метку времени (пусто -> nil)
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatOptionalID возвращает ID или 0, если ссылка не задана
+func formatOptionalID(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// formatOptionalTime форматирует необязательную дату в RFC3339 (nil -> пустая строка)
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// convertTaskHistoryEntry конвертирует запись истории в protobuf
+func convertTaskHistoryEntry(entry entity.TaskHistoryEntry) (*pb.TaskHistoryEntry, error) {
+	pbEntry := &pb.TaskHistoryEntry{
+		Id:        int32(entry.ID),
+		TaskId:    int32(entry.TaskID),
+		ActorId:   int32(entry.ActorID),
+		Action:    string(entry.Action),
+		Changes:   make([]*pb.FieldChange, len(entry.Changes)),
+		ChangedAt: entry.ChangedAt.Format(time.RFC3339),
+	}
+
+	var err error
+	if entry.OldValues != nil {
+		if pbEntry.OldValues, err = structpb.NewStruct(entry.OldValues); err != nil {
+			return nil, err
+		}
+	}
+	if entry.NewValues != nil {
+		if pbEntry.NewValues, err = structpb.NewStruct(entry.NewValues); err != nil {
+			return nil, err
+		}
 	}
 
-	pbTasks := make([]*pb.TaskResponse, len(tasks))
-	for i, task := range tasks {
-		pbTasks[i] = &pb.TaskResponse{
-			Id:          int32(task.ID),
-			Title:       task.Title,
-			Description: task.Description,
-			Status:      string(task.Status),
-			OwnerId:     int32(task.OwnerId),
-			CreatedAt:   task.CreatedAt.String(),
-			UpdatedAt:   task.UpdatedAt.String(),
+	for i, change := range entry.Changes {
+		oldValue, err := structpb.NewValue(change.Old)
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := structpb.NewValue(change.New)
+		if err != nil {
+			return nil, err
+		}
+		pbEntry.Changes[i] = &pb.FieldChange{
+			Field:    change.Field,
+			OldValue: oldValue,
+			NewValue: newValue,
 		}
 	}
 
-	return &pb.ListTasksResponse{Tasks: pbTasks}, nil
+	return pbEntry, nil
 }
@@ -16,13 +16,15 @@ type UserServiceServer struct {
 	pb.UnimplementedUserServiceServer
 	userService *usecase.UserService
 	authService *usecase.AuthService
+	roleService *usecase.RoleService
 }
 
 // NewUserServiceServer создает новый UserServiceServer
-func NewUserServiceServer(userService *usecase.UserService, authService *usecase.AuthService) *UserServiceServer {
+func NewUserServiceServer(userService *usecase.UserService, authService *usecase.AuthService, roleService *usecase.RoleService) *UserServiceServer {
 	return &UserServiceServer{
 		userService: userService,
 		authService: authService,
+		roleService: roleService,
 	}
 }
 
@@ -37,10 +39,7 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	avatarURL := ""
-	if user.AvatarURL != nil {
-		avatarURL = *user.AvatarURL
-	}
+	avatarURL := userAvatarURL(user)
 
 	return &pb.UserResponse{
 		Id:        int32(user.ID),
@@ -53,6 +52,10 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 
 // GetUser получает пользователя по ID
 func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
+	if err := authorizeUserTarget(ctx, int(req.Id)); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userService.GetUser(ctx, int(req.Id))
 	if err != nil {
 		switch err {
@@ -63,10 +66,7 @@ func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest)
 		}
 	}
 
-	avatarURL := ""
-	if user.AvatarURL != nil {
-		avatarURL = *user.AvatarURL
-	}
+	avatarURL := userAvatarURL(user)
 
 	return &pb.UserResponse{
 		Id:        int32(user.ID),
@@ -79,6 +79,10 @@ func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest)
 
 // UpdateUser обновляет пользователя
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	if err := authorizeUserTarget(ctx, int(req.Id)); err != nil {
+		return nil, err
+	}
+
 	userReq := &entity.UpdateUserRequest{
 		Name: req.Name,
 	}
@@ -93,10 +97,7 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 		}
 	}
 
-	avatarURL := ""
-	if user.AvatarURL != nil {
-		avatarURL = *user.AvatarURL
-	}
+	avatarURL := userAvatarURL(user)
 
 	return &pb.UserResponse{
 		Id:        int32(user.ID),
@@ -109,6 +110,10 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 
 // DeleteUser удаляет пользователя
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := authorizeUserTarget(ctx, int(req.Id)); err != nil {
+		return nil, err
+	}
+
 	err := s.userService.DeleteUser(ctx, int(req.Id))
 	if err != nil {
 		switch err {
@@ -124,17 +129,29 @@ func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRe
 
 // ListUsers получает список пользователей
 func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
-	users, err := s.userService.ListUsers(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	listReq := &entity.ListUsersRequest{
+		Page: entity.PageRequest{
+			PageSize:  int(req.PageSize),
+			PageToken: req.PageToken,
+			SortBy:    req.SortBy,
+			SortDir:   entity.SortDirection(req.SortDirection),
+			Total:     entity.TotalMode(req.TotalMode),
+		},
 	}
 
-	pbUsers := make([]*pb.UserResponse, len(users))
-	for i, user := range users {
-		avatarURL := ""
-		if user.AvatarURL != nil {
-			avatarURL = *user.AvatarURL
+	page, err := s.userService.ListUsers(ctx, listReq)
+	if err != nil {
+		switch err {
+		case entity.ErrInvalidPageToken, entity.ErrInvalidSort, entity.ErrInvalidTotalMode:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
 		}
+	}
+
+	pbUsers := make([]*pb.UserResponse, len(page.Users))
+	for i, user := range page.Users {
+		avatarURL := userAvatarURL(&user)
 
 		pbUsers[i] = &pb.UserResponse{
 			Id:        int32(user.ID),
@@ -146,8 +163,43 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 	}
 
 	return &pb.ListUsersResponse{
-		Users: pbUsers,
-		Total: int32(len(pbUsers)),
+		Users:          pbUsers,
+		Total:          int32(page.Total),
+		NextPageToken:  page.NextPageToken,
+		TotalEstimated: page.TotalEstimated,
+	}, nil
+}
+
+// SetUserRoles заменяет роли пользователя
+func (s *UserServiceServer) SetUserRoles(ctx context.Context, req *pb.SetUserRolesRequest) (*pb.SetUserRolesResponse, error) {
+	rolesReq := &entity.SetUserRolesRequest{
+		UserID: int(req.UserId),
+		Roles:  make([]entity.Role, len(req.Roles)),
+	}
+	for i, role := range req.Roles {
+		rolesReq.Roles[i] = entity.Role(role)
+	}
+
+	roles, err := s.roleService.SetUserRoles(ctx, rolesReq)
+	if err != nil {
+		switch err {
+		case entity.ErrUserNotFound:
+			return nil, status.Error(codes.NotFound, "user not found")
+		case entity.ErrInvalidRole:
+			return nil, status.Error(codes.InvalidArgument, "invalid role")
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	pbRoles := make([]string, len(roles))
+	for i, role := range roles {
+		pbRoles[i] = string(role)
+	}
+
+	return &pb.SetUserRolesResponse{
+		UserId: req.UserId,
+		Roles:  pbRoles,
 	}, nil
 }
 
@@ -162,12 +214,26 @@ func (s *UserServiceServer) UploadAvatar(stream pb.UserService_UploadAvatarServe
 		return status.Error(codes.Internal, err.Error())
 	}
 
-	userID := int(firstMsg.UserId)
-	contentType := firstMsg.ContentType
-	var data []byte
-	data = append(data, firstMsg.Data...)
+	userID, err := callerID(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	// Чужую аватарку может загрузить только пользователь с правом управления профилями
+	if firstMsg.UserId != 0 {
+		userID = int(firstMsg.UserId)
+	}
+	if err := authorizeUserTarget(stream.Context(), userID); err != nil {
+		return err
+	}
+
+	// Чанки сразу уходят во временный объект хранилища, в памяти файл не копится
+	upload := s.userService.NewAvatarUpload(stream.Context())
+	defer upload.Abort()
 
-	// Читаем остальные пакеты
+	if _, err := upload.Write(firstMsg.Data); err != nil {
+		return avatarError(err)
+	}
 	for {
 		msg, err := stream.Recv()
 		if err != nil {
@@ -176,57 +242,68 @@ func (s *UserServiceServer) UploadAvatar(stream pb.UserService_UploadAvatarServe
 			}
 			return status.Error(codes.Internal, err.Error())
 		}
-		data = append(data, msg.Data...)
+		// Превышение лимита обрывает загрузку сразу, не дожидаясь конца потока
+		if _, err := upload.Write(msg.Data); err != nil {
+			return avatarError(err)
+		}
 	}
 
 	// Загружаем аватарку
-	filePath, err := s.userService.UploadAvatar(stream.Context(), userID, data, contentType)
-	if err != nil {
-		switch err {
-		case entity.ErrUserNotFound:
-			return status.Error(codes.NotFound, "user not found")
-		default:
-			return status.Error(codes.Internal, err.Error())
-		}
+	if _, err := s.userService.UploadAvatarStream(stream.Context(), userID, upload); err != nil {
+		return avatarError(err)
 	}
 
 	// Отправляем ответ
 	return stream.SendAndClose(&pb.UploadAvatarResponse{
 		Success:   true,
 		Message:   "Avatar uploaded successfully",
-		AvatarUrl: filePath,
+		AvatarUrl: avatarURL(userID),
 	})
 }
 
 // DownloadAvatar скачивает аватарку пользователя (серверный stream)
 func (s *UserServiceServer) DownloadAvatar(req *pb.DownloadAvatarRequest, stream pb.UserService_DownloadAvatarServer) error {
-	userID := int(req.UserId)
-
-	// Используем stream метод из UserService
-	dataChan, errChan := s.userService.DownloadAvatarStream(stream.Context(), userID, 64*1024) // 64KB chunks
+	avatar, dataChan, errChan, err := s.userService.DownloadAvatarStream(stream.Context(), int(req.UserId), int(req.Size), 64*1024) // 64KB chunks
+	if err != nil {
+		return avatarError(err)
+	}
 
-	// Отправляем чанки
-	for {
-		select {
-		case data, ok := <-dataChan:
-			if !ok {
-				return nil
-			}
-			if err := stream.Send(&pb.DownloadAvatarResponse{
-				Data: data,
-			}); err != nil {
-				return status.Error(codes.Internal, err.Error())
-			}
-		case err := <-errChan:
-			if err != nil {
-				switch err {
-				case entity.ErrUserNotFound:
-					return status.Error(codes.NotFound, "user not found")
-				default:
-					return status.Error(codes.Internal, err.Error())
-				}
-			}
-			return nil
+	// Тип содержимого идет в первом сообщении
+	first := true
+	for data := range dataChan {
+		msg := &pb.DownloadAvatarResponse{Data: data}
+		if first {
+			msg.ContentType = avatar.ContentType
+			first = false
 		}
+		if err := stream.Send(msg); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := <-errChan; err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// avatarError переводит ошибки операций с аватарками в gRPC статус
+func avatarError(err error) error {
+	switch err {
+	case entity.ErrUserNotFound:
+		return status.Error(codes.NotFound, "user not found")
+	case entity.ErrAvatarNotFound:
+		return status.Error(codes.NotFound, "avatar not found")
+	case entity.ErrEmptyFile:
+		return status.Error(codes.InvalidArgument, "file is empty")
+	case entity.ErrInvalidImage:
+		return status.Error(codes.InvalidArgument, "file must be a JPEG, PNG, WebP or GIF image")
+	case entity.ErrImageTooLarge:
+		return status.Error(codes.InvalidArgument, "image must be at most 8192px per side and 25 megapixels")
+	case entity.ErrInvalidAvatarSize:
+		return status.Error(codes.InvalidArgument, "size must be 0, 64, 128 or 512")
+	case entity.ErrFileTooLarge:
+		return status.Error(codes.ResourceExhausted, "file size exceeds 5MB limit")
+	default:
+		return status.Error(codes.Internal, err.Error())
 	}
 }
@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// TaskAccess - уровень доступа пользователя к задаче, уровни упорядочены
+type TaskAccess int
+
+const (
+	TaskAccessNone  TaskAccess = iota
+	TaskAccessRead             // viewer
+	TaskAccessWrite            // editor или исполнитель
+	TaskAccessOwner            // владелец: удаление и управление доступом
+)
+
+// ShareRole - уровень доступа, выданный владельцем другому пользователю
+type ShareRole string
+
+const (
+	ShareRoleViewer ShareRole = "viewer"
+	ShareRoleEditor ShareRole = "editor"
+)
+
+func (r ShareRole) IsValid() bool {
+	return r == ShareRoleViewer || r == ShareRoleEditor
+}
+
+// Access - уровень доступа, который дает роль
+func (r ShareRole) Access() TaskAccess {
+	switch r {
+	case ShareRoleEditor:
+		return TaskAccessWrite
+	case ShareRoleViewer:
+		return TaskAccessRead
+	}
+	return TaskAccessNone
+}
+
+// TaskShare - доступ пользователя к задаче (и ее подзадачам)
+type TaskShare struct {
+	TaskID    int       `json:"task_id"`
+	UserID    int       `json:"user_id"`
+	Role      ShareRole `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskListMode - какие задачи возвращает ListTasks
+type TaskListMode string
+
+const (
+	TaskListOwned    TaskListMode = "owned"    // созданные пользователем (по умолчанию)
+	TaskListAssigned TaskListMode = "assigned" // где пользователь исполнитель
+	TaskListShared   TaskListMode = "shared"   // к которым пользователю выдали доступ
+)
+
+func (m TaskListMode) IsValid() bool {
+	switch m {
+	case TaskListOwned, TaskListAssigned, TaskListShared:
+		return true
+	}
+	return false
+}
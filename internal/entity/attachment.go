@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// TaskAttachment - файл, прикрепленный к задаче
+type TaskAttachment struct {
+	ID         int    `json:"id"`
+	TaskID     int    `json:"task_id"`
+	UploaderID *int   `json:"uploader_id"` // nil, если загрузивший пользователь удален
+	FileName   string `json:"file_name"`
+	Blob
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UploadAttachmentRequest struct {
+	TaskID   int
+	FileName string
+}
+
+// AttachmentLimits - ограничения на вложения в байтах
+type AttachmentLimits struct {
+	MaxFileSize int64 // размер одного файла
+	TaskQuota   int64 // суммарный размер вложений задачи
+	UserQuota   int64 // суммарный размер вложений, загруженных пользователем
+}
+
+// AttachmentUsage - занятое вложениями место в байтах
+type AttachmentUsage struct {
+	TaskBytes int64
+	UserBytes int64
+}
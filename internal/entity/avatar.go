@@ -3,13 +3,11 @@ package entity
 import "time"
 
 type Avatar struct {
-	ID          int       `json:"id"`
-	UserID      int       `json:"user_id"`
-	FilePath    string    `json:"file_path"`
-	FileSize    int       `json:"file_size"`
-	ContentType string    `json:"content_type"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	Blob
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type UploadAvatarRequest struct {
@@ -20,4 +18,5 @@ type UploadAvatarRequest struct {
 
 type DownloadAvatarRequest struct {
 	UserID int
+	Size   int // 0 - полная версия, иначе сторона квадратного превью
 }
@@ -0,0 +1,9 @@
+package entity
+
+// Blob - метаданные сохраненного файла, общие для аватарок и вложений задач
+type Blob struct {
+	StorageKey  string `json:"storage_key"` // ключ в BlobStore, не зависит от бэкенда
+	FileSize    int64  `json:"file_size"`
+	ContentType string `json:"content_type"` // определяется по содержимому, а не со слов клиента
+	SHA256      string `json:"sha256"`       // hex, считается при записи; пусто у файлов, загруженных раньше
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// TaskComment - комментарий к задаче. Ответ ссылается на ParentCommentID.
+// У удаленного комментария пустой Body и заполнен DeletedAt
+type TaskComment struct {
+	ID              int        `json:"id"`
+	TaskID          int        `json:"task_id"`
+	ParentCommentID *int       `json:"parent_comment_id"`
+	AuthorID        int        `json:"author_id"`
+	Body            string     `json:"body"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	EditedAt        *time.Time `json:"edited_at"` // последнее редактирование текста
+	DeletedAt       *time.Time `json:"deleted_at"`
+	ReplyCount      int        `json:"reply_count"` // неудаленные прямые ответы
+}
+
+// CommentRevision - предыдущая версия текста комментария
+type CommentRevision struct {
+	ID        int       `json:"id"`
+	CommentID int       `json:"comment_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"` // когда текст был заменен
+}
+
+type CreateCommentRequest struct {
+	TaskID          int    `json:"task_id"`
+	ParentCommentID *int   `json:"parent_comment_id"`
+	Body            string `json:"body" validate:"required, min=1, max=10000"`
+}
+
+// Поля сортировки комментариев
+const (
+	CommentSortCreatedAt = "created_at"
+)
+
+type ListCommentsRequest struct {
+	TaskID          int  `json:"task_id"`
+	ParentCommentID *int `json:"parent_comment_id"` // nil - корневые комментарии задачи
+	Page            PageRequest
+}
+
+type CommentPage struct {
+	Comments []TaskComment `json:"comments"`
+	PageInfo
+}
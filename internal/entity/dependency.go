@@ -0,0 +1,7 @@
+package entity
+
+// TaskDependencies - связи задачи: кем она заблокирована и кого блокирует сама
+type TaskDependencies struct {
+	BlockedBy []Task `json:"blocked_by"`
+	Blocks    []Task `json:"blocks"`
+}
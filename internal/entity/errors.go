@@ -1,12 +1,116 @@
 package entity
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var (
-	ErrForbidden        = errors.New("forbidden: access denied")
-	ErrNoFieldsToUpdate = errors.New("no fields to update")
-	ErrTaskNotFound     = errors.New("task not found")
-	ErrUserNotFound     = errors.New("user not found")
-	ErrInvalidTaskData  = errors.New("invalid task data")
-	ErrInvalidUserData  = errors.New("invalid user data")
+	ErrForbidden          = errors.New("forbidden: access denied")
+	ErrNoFieldsToUpdate   = errors.New("no fields to update")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidTaskData    = errors.New("invalid task data")
+	ErrInvalidUserData    = errors.New("invalid user data")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidPageToken   = errors.New("invalid page token")
+	ErrInvalidSort        = errors.New("invalid sort field or direction")
+	ErrInvalidTotalMode   = errors.New("invalid total mode")
+	ErrInvalidFilter      = errors.New("invalid filter")
+	ErrVersionMismatch    = errors.New("task version mismatch")
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrLabelExists        = errors.New("label with this name already exists")
+	ErrInvalidLabelData   = errors.New("invalid label data")
+	ErrInvalidParent      = errors.New("invalid parent task")
+	ErrTaskCycle          = errors.New("task cannot be moved under itself or its descendant")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrInvalidShare       = errors.New("invalid share")
+	ErrTaskArchived       = errors.New("task is archived")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrInvalidProject     = errors.New("invalid project data")
+	ErrInvalidMember      = errors.New("invalid project member")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrInvalidComment     = errors.New("invalid comment")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+	ErrEmptyFile          = errors.New("file is empty")
+	ErrFileTooLarge       = errors.New("file exceeds size limit")
+	ErrQuotaExceeded      = errors.New("storage quota exceeded")
+	ErrAvatarNotFound     = errors.New("avatar not found")
+	ErrInvalidImage       = errors.New("file is not a supported image")
+	ErrImageTooLarge      = errors.New("image dimensions exceed limit")
+	ErrInvalidAvatarSize  = errors.New("invalid avatar size")
+	// ErrInvalidRefreshToken - токен не прошел проверку подписи, истек или неизвестен
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused - предъявлен уже отозванный токен, вся сессия завершена
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	ErrSessionNotFound    = errors.New("session not found")
+	// ErrInvalidPassword - пароль короче 8 символов или длиннее 72 байт (ограничение bcrypt)
+	ErrInvalidPassword = errors.New("password must be 8 to 72 bytes long")
+	// ErrInvalidResetToken - токен сброса пароля неизвестен, истек или уже использован
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	// ErrInvalidVerificationToken - токен подтверждения email неизвестен, истек, использован
+	// или выдан для другого адреса
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrTooManyRequests          = errors.New("too many requests, try again later")
+	// ErrInvalidCredentials - единая ошибка входа: неизвестный email, неверный пароль
+	// и неактивный аккаунт неотличимы
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrLoginThrottled - базовая ошибка для LoginThrottledError, проверяется через errors.Is
+	ErrLoginThrottled = errors.New("too many login attempts")
+	// ErrInvalidTransition - базовая ошибка для InvalidTransitionError, проверяется через errors.Is
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrTaskBlocked - базовая ошибка для BlockedTaskError, проверяется через errors.Is
+	ErrTaskBlocked = errors.New("task is blocked")
 )
+
+// InvalidTransitionError - запрещенный переход статуса задачи
+type InvalidTransitionError struct {
+	From TaskStatus
+	To   TaskStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition: %s -> %s", e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// BlockedTaskError - задачу нельзя взять в работу или завершить, пока открыты блокирующие задачи
+type BlockedTaskError struct {
+	TaskID     int
+	BlockerIDs []int
+}
+
+func (e *BlockedTaskError) Error() string {
+	ids := make([]string, len(e.BlockerIDs))
+	for i, id := range e.BlockerIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("task %d is blocked by unfinished tasks: %s", e.TaskID, strings.Join(ids, ", "))
+}
+
+func (e *BlockedTaskError) Is(target error) bool {
+	return target == ErrTaskBlocked
+}
+
+// LoginThrottledError - вход временно закрыт после неудачных попыток
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	// Округляем вверх: повтор через "0 секунд" снова упрется в задержку
+	seconds := (e.RetryAfter + time.Second - 1) / time.Second
+	return fmt.Sprintf("too many login attempts, retry in %d seconds", seconds)
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrLoginThrottled
+}
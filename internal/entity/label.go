@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Label - метка пользователя для группировки задач (например, "backend", "bug")
+type Label struct {
+	ID        int       `json:"id"`
+	OwnerID   int       `json:"owner_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"` // #RRGGBB
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateLabelRequest struct {
+	Name  string `json:"name" validate:"required, min=1, max=100"`
+	Color string `json:"color"` // по умолчанию DefaultLabelColor
+}
+
+type UpdateLabelRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+const DefaultLabelColor = "#808080"
+
+// LabelMatch - как фильтровать задачи по набору меток
+type LabelMatch string
+
+const (
+	LabelMatchAny LabelMatch = "any" // задача с любой из меток
+	LabelMatchAll LabelMatch = "all" // задача со всеми метками
+)
+
+func (m LabelMatch) IsValid() bool {
+	return m == LabelMatchAny || m == LabelMatchAll
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// LoginThrottlePolicy - защита входа от перебора для одного ключа (аккаунта или IP).
+// Первые FreeAttempts неудачных попыток без задержки, дальше перед следующей попыткой
+// нужно выждать BaseDelay, удваиваясь с каждой неудачей до MaxDelay. После LockoutAttempts
+// неудач ключ блокируется на LockoutDuration. Счетчик обнуляется, если неудач не было Window
+type LoginThrottlePolicy struct {
+	FreeAttempts    int
+	LockoutAttempts int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// BlockFor возвращает, на сколько закрыть вход после failures неудач подряд
+func (p LoginThrottlePolicy) BlockFor(failures int) time.Duration {
+	if p.LockoutAttempts > 0 && failures >= p.LockoutAttempts {
+		return p.LockoutDuration
+	}
+	if failures < p.FreeAttempts {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// IsLockout - достигнут ли порог блокировки
+func (p LoginThrottlePolicy) IsLockout(failures int) bool {
+	return p.LockoutAttempts > 0 && failures >= p.LockoutAttempts
+}
+
+type AuthEventType string
+
+const (
+	AuthEventLoginSucceeded AuthEventType = "login_succeeded"
+	AuthEventLoginFailed    AuthEventType = "login_failed"
+	// AuthEventLoginThrottled - попытка отклонена без проверки пароля: идет задержка или блокировка
+	AuthEventLoginThrottled AuthEventType = "login_throttled"
+	AuthEventAccountLocked  AuthEventType = "account_locked"
+	AuthEventIPLocked       AuthEventType = "ip_locked"
+)
+
+// AuthEvent - запись журнала входов
+type AuthEvent struct {
+	ID        int           `json:"id"`
+	Type      AuthEventType `json:"type"`
+	UserID    *int          `json:"user_id"` // nil, если email не принадлежит пользователю
+	Email     string        `json:"email"`
+	IPAddress string        `json:"ip_address"`
+	UserAgent string        `json:"user_agent"`
+	Details   string        `json:"details"`
+	CreatedAt time.Time     `json:"created_at"`
+}
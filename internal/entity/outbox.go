@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage - аудит-сообщение, ожидающее публикации в RabbitMQ.
+// Payload - AuditMessage в JSON, разбирает его relay
+type OutboxMessage struct {
+	ID        int64           `json:"id"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+}
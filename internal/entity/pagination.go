@@ -0,0 +1,33 @@
+package entity
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// TotalMode - нужно ли считать общее количество записей
+type TotalMode string
+
+const (
+	TotalNone      TotalMode = ""
+	TotalExact     TotalMode = "exact"
+	TotalEstimated TotalMode = "estimated"
+)
+
+// PageRequest - параметры keyset-пагинации
+type PageRequest struct {
+	PageSize  int           `json:"page_size"`
+	PageToken string        `json:"page_token"`
+	SortBy    string        `json:"sort_by"`
+	SortDir   SortDirection `json:"sort_direction"`
+	Total     TotalMode     `json:"total_mode"`
+}
+
+// PageInfo - информация о странице в ответе
+type PageInfo struct {
+	NextPageToken  string `json:"next_page_token"`
+	Total          int64  `json:"total"`
+	TotalEstimated bool   `json:"total_estimated"`
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Project - проект, объединяющий задачи нескольких пользователей
+type Project struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	OwnerID     int         `json:"owner_id"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Role        ProjectRole `json:"role"` // роль вызывающего пользователя
+}
+
+type CreateProjectRequest struct {
+	Name        string `json:"name" validate:"required, min=1, max=255"`
+	Description string `json:"description"`
+}
+
+type UpdateProjectRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// ProjectRole - роль участника проекта
+type ProjectRole string
+
+const (
+	ProjectRoleAdmin  ProjectRole = "admin"  // управление проектом, участниками и всеми задачами
+	ProjectRoleEditor ProjectRole = "editor" // создание, чтение и изменение задач
+	ProjectRoleViewer ProjectRole = "viewer" // чтение задач
+)
+
+func (r ProjectRole) IsValid() bool {
+	switch r {
+	case ProjectRoleAdmin, ProjectRoleEditor, ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+// Access - доступ к задачам проекта, который дает роль
+func (r ProjectRole) Access() TaskAccess {
+	switch r {
+	case ProjectRoleAdmin:
+		return TaskAccessOwner
+	case ProjectRoleEditor:
+		return TaskAccessWrite
+	case ProjectRoleViewer:
+		return TaskAccessRead
+	}
+	return TaskAccessNone
+}
+
+// ProjectMember - участник проекта
+type ProjectMember struct {
+	ProjectID int         `json:"project_id"`
+	UserID    int         `json:"user_id"`
+	Role      ProjectRole `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+}
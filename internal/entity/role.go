@@ -0,0 +1,45 @@
+package entity
+
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleAdmin   Role = "admin"
+	RoleService Role = "service"
+)
+
+// IsValid проверяет, что роль известна сервису
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleAdmin, RoleService:
+		return true
+	}
+	return false
+}
+
+type Permission string
+
+const (
+	PermissionTaskRead    Permission = "task:read"
+	PermissionTaskWrite   Permission = "task:write"
+	PermissionTaskReadAny Permission = "task:read_any"
+	PermissionUserRead    Permission = "user:read"
+	PermissionUserWrite   Permission = "user:write"
+	PermissionUserReadAny Permission = "user:read_any"
+	PermissionUserManage  Permission = "user:manage"
+)
+
+// IsValid проверяет, что право известно сервису
+func (p Permission) IsValid() bool {
+	switch p {
+	case PermissionTaskRead, PermissionTaskWrite, PermissionTaskReadAny,
+		PermissionUserRead, PermissionUserWrite, PermissionUserReadAny, PermissionUserManage:
+		return true
+	}
+	return false
+}
+
+type SetUserRolesRequest struct {
+	UserID int    `json:"user_id" validate:"required, min=1"`
+	Roles  []Role `json:"roles" validate:"required, min=1"`
+}
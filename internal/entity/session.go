@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// ClientInfo - откуда пришел запрос: устройство (User-Agent) и IP клиента
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session - сессия пользователя: семейство refresh токенов, начатое одним входом
+// и продолженное ротациями. ID сессии - ID семейства
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"` // с последнего обновления токена
+	IPAddress  string    `json:"ip_address"` // с последнего обновления токена
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -11,26 +11,146 @@ const (
 	StatusCancelled  TaskStatus = "cancelled"
 )
 
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// BlockedByOpenTasks - статусы, в которые нельзя перейти, пока есть открытые блокирующие задачи
+func (s TaskStatus) BlockedByOpenTasks() bool {
+	return s == StatusInProgress || s == StatusCompleted
+}
+
+type TaskPriority string
+
+const (
+	PriorityLow    TaskPriority = "low"
+	PriorityMedium TaskPriority = "medium"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// OptionalTime - новое значение необязательной даты в запросе на обновление:
+// nil *OptionalTime - не менять, OptionalTime{Time: nil} - очистить
+type OptionalTime struct {
+	Time *time.Time
+}
+
+// OptionalID - новое значение необязательной ссылки в запросе на обновление:
+// nil *OptionalID - не менять, OptionalID{ID: nil} - очистить
+type OptionalID struct {
+	ID *int
+}
+
+// SubtaskPolicy - что делать с подзадачами при удалении задачи
+type SubtaskPolicy string
+
+const (
+	SubtaskCascade  SubtaskPolicy = "cascade"  // удалить все поддерево
+	SubtaskReparent SubtaskPolicy = "reparent" // поднять детей к родителю удаляемой задачи
+)
+
+func (p SubtaskPolicy) IsValid() bool {
+	return p == SubtaskCascade || p == SubtaskReparent
+}
+
 type Task struct {
-	ID          int        `json:"id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	OwnerId     int        `json:"owner_id"`
+	ID          int          `json:"id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	OwnerId     int          `json:"owner_id"`
+	Version     int          `json:"version"` // увеличивается при каждом обновлении
+	StartAt     *time.Time   `json:"start_at"`
+	DueAt       *time.Time   `json:"due_at"`
+	Priority    TaskPriority `json:"priority"`
+	CompletedAt *time.Time   `json:"completed_at"` // когда задача перешла в completed
+	CancelledAt *time.Time   `json:"cancelled_at"` // когда задача перешла в cancelled
+	Labels      []Label      `json:"labels"`
+	ParentID    *int         `json:"parent_id"`
+	AssigneeIDs []int        `json:"assignee_ids"`
+	ProjectID   *int         `json:"project_id"`
+	ArchivedAt  *time.Time   `json:"archived_at"` // задачи удаленного проекта только читаются и удаляются
+}
+
+// TaskNode - узел дерева задач. Progress - процент выполнения (0-100),
+// считается по всему поддереву, даже если Children обрезаны по глубине
+type TaskNode struct {
+	Task     Task        `json:"task"`
+	Progress int         `json:"progress"`
+	Children []*TaskNode `json:"children"`
+}
+
+// TaskStatusNode - статус задачи с родителем, для подсчета прогресса поддерева
+type TaskStatusNode struct {
+	ID       int
+	ParentID *int
+	Status   TaskStatus
 }
 
 // валидация
 type CreateTaskRequest struct {
-	Title       string     `json:"title" validate:"required, min=1, max=255"`
-	Description string     `json:"description" validate:"required"`
-	Status      TaskStatus `json:"status" validate:"oneof=pending in_progress completed cancelled"`
-	OwnerId     int        `json:"owner_id" validate:"required, min=1"`
+	Title       string       `json:"title" validate:"required, min=1, max=255"`
+	Description string       `json:"description" validate:"required"`
+	Status      TaskStatus   `json:"status" validate:"oneof=pending in_progress completed cancelled"`
+	OwnerId     int          `json:"owner_id" validate:"required, min=1"`
+	StartAt     *time.Time   `json:"start_at"`
+	DueAt       *time.Time   `json:"due_at"`
+	Priority    TaskPriority `json:"priority"` // по умолчанию medium
+	ParentID    *int         `json:"parent_id"`
+	ProjectID   *int         `json:"project_id"` // подзадача всегда в проекте родителя
 }
 
 type UpdateTaskRequest struct {
-	Title       string     `json:"title"`
-	Description *string    `json:"description"` // опциональное поле для обновления
-	Status      TaskStatus `json:"status"`
+	Title       string        `json:"title"`
+	Description *string       `json:"description"` // опциональное поле для обновления
+	Status      TaskStatus    `json:"status"`
+	StartAt     *OptionalTime `json:"start_at"`
+	DueAt       *OptionalTime `json:"due_at"`
+	Priority    *TaskPriority `json:"priority"`
+	ParentID    *OptionalID   `json:"parent_id"` // ID: nil - сделать задачу корневой
+	// Ожидаемая версия задачи, 0 - без проверки
+	ExpectedVersion int `json:"expected_version"`
+}
+
+// Поля сортировки списка задач
+const (
+	TaskSortCreatedAt = "created_at"
+	TaskSortUpdatedAt = "updated_at"
+	TaskSortTitle     = "title"
+	TaskSortID        = "id"
+)
+
+type ListTasksRequest struct {
+	UserID     int          `json:"user_id"` // вызывающий пользователь, задачи выбираются по Mode
+	Mode       TaskListMode `json:"mode"`
+	Status     string       `json:"status"`
+	Priority   TaskPriority `json:"priority"`
+	Overdue    bool         `json:"overdue"` // срок прошел, а задача не завершена и не отменена
+	DueBefore  *time.Time   `json:"due_before"`
+	DueAfter   *time.Time   `json:"due_after"`
+	LabelIDs   []int        `json:"label_ids"`
+	LabelMatch LabelMatch   `json:"label_match"` // по умолчанию any
+	ParentID   *int         `json:"parent_id"`   // только прямые подзадачи этой задачи
+	ProjectID  *int         `json:"project_id"`  // без Mode - все задачи проекта
+	Archived   bool         `json:"archived"`    // только архивные задачи вместо активных
+	Page       PageRequest
+}
+
+type TaskPage struct {
+	Tasks []Task `json:"tasks"`
+	PageInfo
 }
@@ -11,6 +11,11 @@ const (
 	ActionRead   ActionType = "Read"
 	ActionUpdate ActionType = "Update"
 	ActionDelete ActionType = "Delete"
+
+	// Действия с комментариями пишутся в историю задачи
+	ActionCommentCreate ActionType = "CommentCreate"
+	ActionCommentUpdate ActionType = "CommentUpdate"
+	ActionCommentDelete ActionType = "CommentDelete"
 )
 
 type TaskAudit struct {
@@ -34,3 +39,56 @@ type AuditMessage struct {
 	Changes   map[string]any `json:"changes"`
 	Timestamp time.Time      `json:"timestamp"`
 }
+
+// IsValid проверяет, что действие известно сервису
+func (a ActionType) IsValid() bool {
+	switch a {
+	case ActionCreate, ActionRead, ActionUpdate, ActionDelete,
+		ActionCommentCreate, ActionCommentUpdate, ActionCommentDelete:
+		return true
+	}
+	return false
+}
+
+// Поля сортировки истории задачи
+const (
+	TaskHistorySortChangedAt = "changed_at"
+)
+
+type TaskHistoryRequest struct {
+	TaskID  int        `json:"task_id"`
+	Action  ActionType `json:"action"`
+	ActorID int        `json:"actor_id"`
+	From    *time.Time `json:"from"`
+	To      *time.Time `json:"to"`
+	Page    PageRequest
+}
+
+type TaskAuditPage struct {
+	Audits []TaskAudit `json:"audits"`
+	PageInfo
+}
+
+// FieldChange - изменение одного поля задачи
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskHistoryEntry - запись аудита с разобранными JSON значениями
+type TaskHistoryEntry struct {
+	ID        int            `json:"id"`
+	TaskID    int            `json:"task_id"`
+	ActorID   int            `json:"actor_id"`
+	Action    ActionType     `json:"action"`
+	OldValues map[string]any `json:"old_values"`
+	NewValues map[string]any `json:"new_values"`
+	Changes   []FieldChange  `json:"changes"`
+	ChangedAt time.Time      `json:"changed_at"`
+}
+
+type TaskHistoryPage struct {
+	Entries []TaskHistoryEntry `json:"entries"`
+	PageInfo
+}
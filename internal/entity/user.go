@@ -3,15 +3,21 @@ package entity
 import "time"
 
 type User struct {
-	ID           int        `json:"id"`
-	Name         string     `json:"name"`
-	Email        *string    `json:"email,omitempty"`
-	PasswordHash string     `json:"-"` // Никогда не отправляем пароль
-	AvatarURL    *string    `json:"avatar_url,omitempty"`
-	IsActive     bool       `json:"is_active"`
-	LastLogin    *time.Time `json:"last_login,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	Email           *string    `json:"email,omitempty"`
+	PasswordHash    string     `json:"-"` // Никогда не отправляем пароль
+	AvatarURL       *string    `json:"avatar_url,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	LastLogin       *time.Time `json:"last_login,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// EmailVerified - подтвержден ли текущий email пользователя
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 // валидация
@@ -23,17 +29,35 @@ type UpdateUserRequest struct {
 	Name string `json:"name" validate:"required, min=1, max=255"`
 }
 
+// Поля сортировки списка пользователей
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortName      = "name"
+	UserSortID        = "id"
+)
+
+type ListUsersRequest struct {
+	Page PageRequest
+}
+
+type UserPage struct {
+	Users []User `json:"users"`
+	PageInfo
+}
+
 // Регистрация
 type RegisterRequest struct {
-	Name     string `json:"name" validate:"required, min=1, max=255"`
-	Email    string `json:"email" validate:"required, email"`
-	Password string `json:"password" validate:"required, min=8, max=255"`
+	Name     string     `json:"name" validate:"required, min=1, max=255"`
+	Email    string     `json:"email" validate:"required, email"`
+	Password string     `json:"password" validate:"required, min=8, max=72"`
+	Client   ClientInfo `json:"-"`
 }
 
 // Логин
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required, email"`
-	Password string `json:"password" validate:"required"`
+	Email    string     `json:"email" validate:"required, email"`
+	Password string     `json:"password" validate:"required"`
+	Client   ClientInfo `json:"-"`
 }
 
 type LoginResponse struct {
@@ -54,6 +78,8 @@ type RefreshTokenResponse struct {
 
 // JWT Claims
 type JWTClaims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
+	UserID        int    `json:"user_id"`
+	Email         string `json:"email"`
+	Roles         []Role `json:"roles"`
+	EmailVerified *bool  `json:"email_verified,omitempty"` // nil - токен выпущен до проверки email
 }
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"time"
@@ -24,13 +26,15 @@ func NewJWTManager() *JWTManager {
 }
 
 // GenerateAccessToken генерирует access token на 15 минут
-func (m *JWTManager) GenerateAccessToken(userID int, email string) (string, error) {
+func (m *JWTManager) GenerateAccessToken(userID int, email string, roles []entity.Role, emailVerified bool) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"email":   email,
-		"exp":     time.Now().Add(15 * time.Minute).Unix(),
-		"iat":     time.Now().Unix(),
-		"type":    "access",
+		"user_id":        userID,
+		"email":          email,
+		"roles":          roles,
+		"email_verified": emailVerified,
+		"exp":            time.Now().Add(15 * time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"type":           "access",
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -42,13 +46,20 @@ func (m *JWTManager) GenerateAccessToken(userID int, email string) (string, erro
 	return tokenString, nil
 }
 
-// GenerateRefreshToken генерирует refresh token на 7 дней
+// GenerateRefreshToken генерирует refresh token на 7 дней.
+// Случайный jti делает токены уникальными, даже если выданы в одну секунду
 func (m *JWTManager) GenerateRefreshToken(userID int, email string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"email":   email,
 		"exp":     time.Now().Add(7 * 24 * time.Hour).Unix(),
 		"iat":     time.Now().Unix(),
+		"jti":     hex.EncodeToString(jti),
 		"type":    "refresh",
 	}
 
@@ -95,9 +106,38 @@ func (m *JWTManager) ValidateAccessToken(tokenString string) (*entity.JWTClaims,
 		return nil, fmt.Errorf("invalid email in token")
 	}
 
+	// Токены, выпущенные до появления ролей, не содержат claim roles
+	var roles []entity.Role
+	if rawRoles, exists := claims["roles"]; exists && rawRoles != nil {
+		list, ok := rawRoles.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid roles in token")
+		}
+		for _, rawRole := range list {
+			role, ok := rawRole.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid roles in token")
+			}
+			roles = append(roles, entity.Role(role))
+		}
+	}
+
+	// Токены, выпущенные до проверки email, не содержат claim email_verified:
+	// статус неизвестен, его проверяет интерцептор
+	var emailVerified *bool
+	if rawVerified, exists := claims["email_verified"]; exists {
+		verified, ok := rawVerified.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid email_verified in token")
+		}
+		emailVerified = &verified
+	}
+
 	return &entity.JWTClaims{
-		UserID: int(userID),
-		Email:  email,
+		UserID:        int(userID),
+		Email:         email,
+		Roles:         roles,
+		EmailVerified: emailVerified,
 	}, nil
 }
 
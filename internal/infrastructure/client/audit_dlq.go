@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetter - сообщение аудита из DLQ
+type DeadLetter struct {
+	Body           []byte
+	RetryCount     int
+	LastError      string
+	DeadLetteredAt time.Time
+}
+
+// ListDeadLetters возвращает до limit сообщений из DLQ, не удаляя их из очереди.
+// При отмене контекста прочитанные сообщения тоже возвращаются в очередь
+func (c *RabbitMQClient) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	channel, err := c.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Close()
+
+	var (
+		letters []DeadLetter
+		last    *amqp.Delivery
+	)
+
+	var readErr error
+	for len(letters) < limit {
+		if readErr = ctx.Err(); readErr != nil {
+			break
+		}
+		msg, ok, err := channel.Get(AuditDeadLetterQueue, false)
+		if err != nil {
+			readErr = fmt.Errorf("failed to get dead letter: %w", err)
+			break
+		}
+		if !ok {
+			break
+		}
+		last = &msg
+		letters = append(letters, toDeadLetter(msg))
+	}
+
+	// Возвращаем все прочитанные сообщения обратно в очередь
+	if last != nil {
+		if err := last.Nack(true, true); err != nil {
+			return nil, fmt.Errorf("failed to requeue dead letters: %w", err)
+		}
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	return letters, nil
+}
+
+// ReplayDeadLetters переносит до limit сообщений из DLQ обратно в очередь аудита
+// со сброшенным счетчиком повторов и возвращает количество перенесенных
+func (c *RabbitMQClient) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	channel, err := c.Channel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+
+	replayed := 0
+	for replayed < limit {
+		msg, ok, err := channel.Get(AuditDeadLetterQueue, false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to get dead letter: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		// Публикация с подтверждением: сообщение удаляется из DLQ только после ack брокера
+		err = c.PublishWithContext(
+			ctx,
+			"",             // exchange
+			AuditQueueName, // routing key
+			false,          // mandatory
+			false,          // immediate
+			amqp.Publishing{
+				ContentType:  msg.ContentType,
+				Body:         msg.Body,
+				DeliveryMode: amqp.Persistent,
+			},
+		)
+		if err != nil {
+			msg.Nack(false, true)
+			return replayed, fmt.Errorf("failed to replay dead letter: %w", err)
+		}
+
+		if err := msg.Ack(false); err != nil {
+			return replayed, fmt.Errorf("failed to ack dead letter: %w", err)
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func toDeadLetter(msg amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		Body:           msg.Body,
+		RetryCount:     RetryCount(msg.Headers),
+		DeadLetteredAt: msg.Timestamp,
+	}
+	if lastError, ok := msg.Headers[LastErrorHeader].(string); ok {
+		letter.LastError = lastError
+	}
+	return letter
+}
@@ -3,57 +3,333 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	// AuditQueueName - основная очередь аудита
+	AuditQueueName = "task_audit_logs"
+	// AuditDeadLetterExchange - exchange для сообщений, которые не удалось обработать
+	AuditDeadLetterExchange = "task_audit_logs.dlx"
+	// AuditDeadLetterQueue - очередь "мертвых" сообщений аудита
+	AuditDeadLetterQueue = "task_audit_logs.dlq"
+
+	// RetryCountHeader - сколько раз сообщение уже откладывалось на повтор
+	RetryCountHeader = "x-retry-count"
+	// LastErrorHeader - причина, по которой сообщение попало в DLQ
+	LastErrorHeader = "x-last-error"
+
+	// AuditMaxRetries - сколько раз повторяем обработку перед отправкой в DLQ
+	AuditMaxRetries = 5
+	// AuditInitialRetryDelay - задержка перед первым повтором, дальше удваивается
+	AuditInitialRetryDelay = time.Second
+)
+
+// AuditRetryQueueName возвращает имя очереди задержки для попытки attempt (с 1)
+func AuditRetryQueueName(attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", AuditQueueName, attempt)
+}
+
+// AuditRetryDelay возвращает задержку перед попыткой attempt (с 1): 1s, 2s, 4s...
+func AuditRetryDelay(attempt int) time.Duration {
+	return AuditInitialRetryDelay << (attempt - 1)
+}
+
+// RetryCount читает счетчик повторов из заголовков сообщения
+func RetryCount(headers amqp.Table) int {
+	switch v := headers[RetryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// TopologyDeclarer - методы канала, нужные для объявления топологии (*amqp.Channel)
+type TopologyDeclarer interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// DeclareAuditTopology объявляет очередь аудита, очереди задержки и DLX/DLQ.
+// Основная очередь объявляется без аргументов, чтобы не конфликтовать с уже
+// существующей, поэтому в DLX и очереди задержки сообщения публикуются явно
+func DeclareAuditTopology(channel TopologyDeclarer) (amqp.Queue, error) {
+	queue, err := channel.QueueDeclare(
+		AuditQueueName, // name
+		true,           // durable
+		false,          // delete when unused
+		false,          // exclusive
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+
+	// Очереди задержки: по истечении TTL сообщение возвращается в основную очередь
+	for attempt := 1; attempt <= AuditMaxRetries; attempt++ {
+		_, err := channel.QueueDeclare(
+			AuditRetryQueueName(attempt),
+			true,
+			false,
+			false,
+			false,
+			amqp.Table{
+				"x-message-ttl":             AuditRetryDelay(attempt).Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": AuditQueueName,
+			},
+		)
+		if err != nil {
+			return amqp.Queue{}, fmt.Errorf("failed to declare retry queue %d: %w", attempt, err)
+		}
+	}
+
+	if err := channel.ExchangeDeclare(
+		AuditDeadLetterExchange, // name
+		amqp.ExchangeDirect,     // kind
+		true,                    // durable
+		false,                   // auto-deleted
+		false,                   // internal
+		false,                   // no-wait
+		nil,                     // arguments
+	); err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+
+	if _, err := channel.QueueDeclare(AuditDeadLetterQueue, true, false, false, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := channel.QueueBind(AuditDeadLetterQueue, AuditQueueName, AuditDeadLetterExchange, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to bind dead letter queue: %w", err)
+	}
+
+	return queue, nil
+}
+
+// ErrRabbitMQNotConnected - соединение с брокером потеряно и еще не восстановлено
+var ErrRabbitMQNotConnected = errors.New("rabbitmq is not connected")
+
+const (
+	publishChannelPoolSize  = 8                // сколько confirm-каналов держим для публикации
+	publishConfirmTimeout   = 5 * time.Second  // сколько ждем подтверждения от брокера
+	reconnectInitialBackoff = time.Second      // задержка перед первой попыткой переподключения
+	reconnectMaxBackoff     = 30 * time.Second // максимальная задержка между попытками
+)
+
+// amqpConnection - соединение с брокером, в тестах подменяется фейком
+type amqpConnection interface {
+	Channel() (amqpChannel, error)
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+	IsClosed() bool
+	Close() error
+}
+
+// amqpChannel - методы канала, которыми пользуется клиент
+type amqpChannel interface {
+	TopologyDeclarer
+	Confirm(noWait bool) error
+	PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (publishConfirmation, error)
+	IsClosed() bool
+	Close() error
+}
+
+// publishConfirmation - ожидание подтверждения публикации (*amqp.DeferredConfirmation)
+type publishConfirmation interface {
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+// amqpConn и amqpChan адаптируют amqp091 к интерфейсам выше
+type amqpConn struct{ *amqp.Connection }
+
+func (c amqpConn) Channel() (amqpChannel, error) {
+	channel, err := c.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+	return amqpChan{channel}, nil
+}
+
+type amqpChan struct{ *amqp.Channel }
+
+func (c amqpChan) PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (publishConfirmation, error) {
+	confirmation, err := c.Channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	if err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+func dialAMQP(url string) (amqpConnection, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return amqpConn{conn}, nil
+}
+
+// RabbitMQClient держит соединение с брокером и переподключается при его потере.
+// Публикация идет через пул каналов в confirm-режиме
 type RabbitMQClient struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
-	queue   amqp.Queue
+	url  string
+	dial func(url string) (amqpConnection, error)
+
+	mu     sync.RWMutex
+	conn   amqpConnection
+	closed bool
+
+	// Пул confirm-каналов: nil или закрытый канал переоткрывается при следующем использовании
+	publishChannels chan amqpChannel
+	done            chan struct{}
+
+	confirmTimeout   time.Duration
+	reconnectBackoff time.Duration
+	reconnectMax     time.Duration
 }
 
 func NewRabbitMQClient(url string) (*RabbitMQClient, error) {
-	conn, err := amqp.Dial(url)
+	return newRabbitMQClient(url, dialAMQP)
+}
+
+func newRabbitMQClient(url string, dial func(url string) (amqpConnection, error)) (*RabbitMQClient, error) {
+	c := &RabbitMQClient{
+		url:              url,
+		dial:             dial,
+		publishChannels:  make(chan amqpChannel, publishChannelPoolSize),
+		done:             make(chan struct{}),
+		confirmTimeout:   publishConfirmTimeout,
+		reconnectBackoff: reconnectInitialBackoff,
+		reconnectMax:     reconnectMaxBackoff,
+	}
+	for i := 0; i < publishChannelPoolSize; i++ {
+		c.publishChannels <- nil
+	}
+
+	closeErrs, err := c.connect()
 	if err != nil {
 		return nil, err
 	}
 
-	channel, err := conn.Channel()
+	go c.watch(closeErrs)
+
+	return c, nil
+}
+
+// connect открывает соединение, объявляет топологию аудита
+// и возвращает канал уведомлений о закрытии соединения
+func (c *RabbitMQClient) connect() (chan *amqp.Error, error) {
+	conn, err := c.dial(c.url)
 	if err != nil {
 		return nil, err
 	}
+	// Подписываемся сразу, иначе можно пропустить обрыв, случившийся до подписки
+	closeErrs := conn.NotifyClose(make(chan *amqp.Error, 1))
 
-	// Объявляем очередь для аудита
-	queue, err := channel.QueueDeclare(
-		"task_audit_logs", // name
-		true,              // durable
-		false,             // delete when unused
-		false,             // exclusive
-		false,             // no-wait
-		nil,               // arguments
-	)
+	channel, err := conn.Channel()
 	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	defer channel.Close()
+
+	// Объявляем очередь для аудита вместе с очередями повторов и DLQ
+	if _, err := DeclareAuditTopology(channel); err != nil {
+		conn.Close()
 		return nil, err
 	}
 
-	return &RabbitMQClient{
-		conn:    conn,
-		channel: channel,
-		queue:   queue,
-	}, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		// Close был вызван, пока мы переподключались
+		conn.Close()
+		return nil, ErrRabbitMQNotConnected
+	}
+	c.conn = conn
+
+	return closeErrs, nil
+}
+
+// watch ждет закрытия соединения и переподключается с экспоненциальной задержкой
+func (c *RabbitMQClient) watch(closeErrs chan *amqp.Error) {
+	for {
+		closeErr := <-closeErrs
+		if c.isClosed() {
+			return
+		}
+		log.Printf("⚠️  Соединение с RabbitMQ потеряно: %v", closeErr)
+
+		backoff := c.reconnectBackoff
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-time.After(backoff):
+			}
+
+			var err error
+			if closeErrs, err = c.connect(); err == nil {
+				break
+			}
+			if c.isClosed() {
+				return
+			}
+
+			log.Printf("❌ Ошибка переподключения к RabbitMQ: %v", err)
+			backoff = min(backoff*2, c.reconnectMax)
+		}
+		log.Println("✅ Соединение с RabbitMQ восстановлено")
+	}
+}
+
+func (c *RabbitMQClient) isClosed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed
 }
 
-// GetChannel возвращает AMQP channel для использования в consumer'ах
-func (c *RabbitMQClient) GetChannel() *amqp.Channel {
-	return c.channel
+// Channel открывает новый канал на текущем соединении (например, для consumer'а).
+// Канал закрывается вместе с соединением, вызывающий должен открыть новый после переподключения
+func (c *RabbitMQClient) Channel() (*amqp.Channel, error) {
+	channel, err := c.channel()
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := channel.(amqpChan)
+	if !ok {
+		channel.Close()
+		return nil, errors.New("rabbitmq connection does not provide amqp channels")
+	}
+	return raw.Channel, nil
+}
+
+// channel открывает канал на текущем соединении
+func (c *RabbitMQClient) channel() (amqpChannel, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed || c.conn == nil || c.conn.IsClosed() {
+		return nil, ErrRabbitMQNotConnected
+	}
+	return c.conn.Channel()
 }
 
 // GetQueueName возвращает имя очереди
 func (c *RabbitMQClient) GetQueueName() string {
-	return c.queue.Name
+	return AuditQueueName
 }
 
 func (c *RabbitMQClient) PublishAuditMessage(ctx context.Context, message *entity.AuditMessage) error {
@@ -62,12 +338,12 @@ func (c *RabbitMQClient) PublishAuditMessage(ctx context.Context, message *entit
 		return err
 	}
 
-	err = c.channel.PublishWithContext(
+	err = c.PublishWithContext(
 		ctx,
-		"",           // exchange
-		c.queue.Name, // routing key
-		false,        // mandatory
-		false,        // immediate
+		"",             // exchange
+		AuditQueueName, // routing key
+		false,          // mandatory
+		false,          // immediate
 		amqp.Publishing{
 			ContentType:  "application/json",
 			Body:         body,
@@ -83,12 +359,73 @@ func (c *RabbitMQClient) PublishAuditMessage(ctx context.Context, message *entit
 	return nil
 }
 
+// PublishWithContext публикует сообщение через пул confirm-каналов
+// и возвращает nil только после подтверждения (ack) от брокера
+func (c *RabbitMQClient) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	var channel amqpChannel
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case channel = <-c.publishChannels:
+	}
+	// Канал возвращается в пул в любом случае, чтобы пул не "худел"
+	defer func() { c.publishChannels <- channel }()
+
+	if channel == nil || channel.IsClosed() {
+		var err error
+		if channel, err = c.openConfirmChannel(); err != nil {
+			return err
+		}
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	if err != nil {
+		return err
+	}
+
+	confirmCtx, cancel := context.WithTimeout(ctx, c.confirmTimeout)
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(confirmCtx)
+	if err != nil {
+		// Подтверждение может прийти позже - не переиспользуем такой канал
+		channel.Close()
+		return fmt.Errorf("failed to wait for publish confirmation: %w", err)
+	}
+	if !acked {
+		return errors.New("message was not acknowledged by broker")
+	}
+
+	return nil
+}
+
+// openConfirmChannel открывает канал в confirm-режиме
+func (c *RabbitMQClient) openConfirmChannel() (amqpChannel, error) {
+	channel, err := c.channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		return nil, err
+	}
+	return channel, nil
+}
+
 func (c *RabbitMQClient) Close() error {
-	if c.channel != nil {
-		c.channel.Close()
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
 	}
-	if c.conn != nil {
-		return c.conn.Close()
+	c.closed = true
+	close(c.done)
+	conn := c.conn
+	c.mu.Unlock()
+
+	// Каналы закрываются вместе с соединением
+	if conn != nil && !conn.IsClosed() {
+		return conn.Close()
 	}
 	return nil
 }
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeBroker - in-memory брокер: выдает соединения и решает, что ответить на публикацию
+type fakeBroker struct {
+	mu       sync.Mutex
+	dialErrs []error // ошибки для очередных подключений, дальше подключение успешно
+	conns    []*fakeConnection
+
+	publishErr error // ошибка самой публикации
+	nack       bool  // брокер отвечает nack
+	silent     bool  // брокер не отвечает вовсе
+}
+
+func (b *fakeBroker) dial(url string) (amqpConnection, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.dialErrs) > 0 {
+		err := b.dialErrs[0]
+		b.dialErrs = b.dialErrs[1:]
+		return nil, err
+	}
+	conn := &fakeConnection{broker: b}
+	b.conns = append(b.conns, conn)
+	return conn, nil
+}
+
+func (b *fakeBroker) connections() []*fakeConnection {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*fakeConnection(nil), b.conns...)
+}
+
+// openChannels считает незакрытые каналы на всех соединениях
+func (b *fakeBroker) openChannels() int {
+	open := 0
+	for _, conn := range b.connections() {
+		conn.mu.Lock()
+		for _, channel := range conn.channels {
+			if !channel.IsClosed() {
+				open++
+			}
+		}
+		conn.mu.Unlock()
+	}
+	return open
+}
+
+type fakeConnection struct {
+	broker *fakeBroker
+
+	mu       sync.Mutex
+	closed   bool
+	notify   []chan *amqp.Error
+	channels []*fakeChannel
+}
+
+func (c *fakeConnection) Channel() (amqpChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	channel := &fakeChannel{conn: c}
+	c.channels = append(c.channels, channel)
+	return channel, nil
+}
+
+func (c *fakeConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify = append(c.notify, receiver)
+	return receiver
+}
+
+func (c *fakeConnection) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *fakeConnection) Close() error {
+	c.shutdown(nil)
+	return nil
+}
+
+// drop имитирует обрыв соединения со стороны брокера
+func (c *fakeConnection) drop() {
+	c.shutdown(&amqp.Error{Code: amqp.ConnectionForced, Reason: "connection dropped"})
+}
+
+func (c *fakeConnection) shutdown(reason *amqp.Error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for _, channel := range c.channels {
+		channel.Close()
+	}
+	for _, receiver := range c.notify {
+		if reason != nil {
+			receiver <- reason
+		}
+		close(receiver)
+	}
+}
+
+type fakeChannel struct {
+	conn *fakeConnection
+
+	mu        sync.Mutex
+	closed    bool
+	confirm   bool
+	published int
+}
+
+func (ch *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *fakeChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	return nil
+}
+
+func (ch *fakeChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	return nil
+}
+
+func (ch *fakeChannel) Confirm(noWait bool) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.confirm = true
+	return nil
+}
+
+func (ch *fakeChannel) PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (publishConfirmation, error) {
+	if ch.IsClosed() {
+		return nil, amqp.ErrClosed
+	}
+	broker := ch.conn.broker
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.publishErr != nil {
+		return nil, broker.publishErr
+	}
+
+	ch.mu.Lock()
+	ch.published++
+	ch.mu.Unlock()
+	return fakeConfirmation{ack: !broker.nack, silent: broker.silent}, nil
+}
+
+func (ch *fakeChannel) IsClosed() bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.closed
+}
+
+func (ch *fakeChannel) Close() error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.closed = true
+	return nil
+}
+
+type fakeConfirmation struct {
+	ack    bool
+	silent bool
+}
+
+func (c fakeConfirmation) WaitContext(ctx context.Context) (bool, error) {
+	if c.silent {
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	return c.ack, nil
+}
+
+func newTestRabbitMQClient(t *testing.T, broker *fakeBroker) *RabbitMQClient {
+	t.Helper()
+	c, err := newRabbitMQClient("amqp://test", broker.dial)
+	if err != nil {
+		t.Fatalf("newRabbitMQClient: %v", err)
+	}
+	c.reconnectBackoff = time.Millisecond
+	c.reconnectMax = 5 * time.Millisecond
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func publishTestMessage(c *RabbitMQClient) error {
+	return c.PublishAuditMessage(context.Background(), &entity.AuditMessage{Action: entity.ActionCreate, EntityID: 1})
+}
+
+func TestRabbitMQReconnectsAfterConnectionLoss(t *testing.T) {
+	broker := &fakeBroker{}
+	c := newTestRabbitMQClient(t, broker)
+
+	if err := publishTestMessage(c); err != nil {
+		t.Fatalf("Expected publish to succeed, got %v", err)
+	}
+
+	// Первая попытка переподключения неудачна, вторая - успешна
+	broker.mu.Lock()
+	broker.dialErrs = []error{errors.New("connection refused")}
+	broker.mu.Unlock()
+	broker.connections()[0].drop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(broker.connections()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected client to reconnect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := publishTestMessage(c); err != nil {
+		t.Fatalf("Expected publish after reconnect to succeed, got %v", err)
+	}
+	second := broker.connections()[1]
+	second.mu.Lock()
+	defer second.mu.Unlock()
+	published := 0
+	for _, channel := range second.channels {
+		published += channel.published
+	}
+	if published != 1 {
+		t.Errorf("Expected message to go through the new connection, got %d", published)
+	}
+}
+
+func TestRabbitMQCloseStopsReconnecting(t *testing.T) {
+	broker := &fakeBroker{}
+	c := newTestRabbitMQClient(t, broker)
+
+	c.Close()
+	if err := publishTestMessage(c); !errors.Is(err, ErrRabbitMQNotConnected) {
+		t.Errorf("Expected ErrRabbitMQNotConnected after Close, got %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := len(broker.connections()); n != 1 {
+		t.Errorf("Expected no reconnect after Close, got %d connections", n)
+	}
+}
+
+func TestRabbitMQNackIsAnError(t *testing.T) {
+	broker := &fakeBroker{nack: true}
+	c := newTestRabbitMQClient(t, broker)
+
+	if err := publishTestMessage(c); err == nil {
+		t.Fatal("Expected nack to be reported as an error")
+	}
+
+	// Канал после nack исправен и остается в пуле
+	if open := broker.openChannels(); open != 1 {
+		t.Errorf("Expected the confirm channel to stay open, got %d open channels", open)
+	}
+	broker.mu.Lock()
+	broker.nack = false
+	broker.mu.Unlock()
+	if err := publishTestMessage(c); err != nil {
+		t.Errorf("Expected publish after nack to succeed, got %v", err)
+	}
+}
+
+func TestRabbitMQConfirmTimeout(t *testing.T) {
+	broker := &fakeBroker{silent: true}
+	c := newTestRabbitMQClient(t, broker)
+	if c.confirmTimeout != 5*time.Second {
+		t.Errorf("Expected default confirm timeout 5s, got %v", c.confirmTimeout)
+	}
+	c.confirmTimeout = 20 * time.Millisecond
+
+	start := time.Now()
+	err := publishTestMessage(c)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected confirm timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected publish to give up after the confirm timeout, took %v", elapsed)
+	}
+
+	// Канал без подтверждения закрыт и не возвращается в работу
+	if open := broker.openChannels(); open != 0 {
+		t.Errorf("Expected unconfirmed channel to be closed, got %d open channels", open)
+	}
+	broker.mu.Lock()
+	broker.silent = false
+	broker.mu.Unlock()
+	if err := publishTestMessage(c); err != nil {
+		t.Errorf("Expected publish on a fresh channel to succeed, got %v", err)
+	}
+}
+
+func TestRabbitMQPublishFailureDoesNotLeakChannels(t *testing.T) {
+	broker := &fakeBroker{publishErr: errors.New("channel error")}
+	c := newTestRabbitMQClient(t, broker)
+
+	for i := 0; i < 3*publishChannelPoolSize; i++ {
+		if err := publishTestMessage(c); err == nil {
+			t.Fatal("Expected publish to fail")
+		}
+	}
+	if n := len(c.publishChannels); n != publishChannelPoolSize {
+		t.Errorf("Expected pool to keep %d slots, got %d", publishChannelPoolSize, n)
+	}
+	if open := broker.openChannels(); open > publishChannelPoolSize {
+		t.Errorf("Expected at most %d open channels, got %d", publishChannelPoolSize, open)
+	}
+
+	// Без соединения публикация тоже не должна терять слоты пула
+	broker.mu.Lock()
+	broker.publishErr = nil
+	broker.dialErrs = []error{errors.New("down"), errors.New("down"), errors.New("down")}
+	broker.mu.Unlock()
+	broker.connections()[0].drop()
+	for i := 0; i < 2*publishChannelPoolSize; i++ {
+		publishTestMessage(c)
+	}
+	if n := len(c.publishChannels); n != publishChannelPoolSize {
+		t.Errorf("Expected pool to keep %d slots while disconnected, got %d", publishChannelPoolSize, n)
+	}
+}
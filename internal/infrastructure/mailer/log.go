@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// LogMailer не отправляет письма, а пишет их в лог или файл - для разработки и тестов
+type LogMailer struct {
+	mu  sync.Mutex
+	out io.Writer // nil - стандартный лог
+}
+
+// NewLogMailer создает LogMailer, out nil - письма пишутся в стандартный лог
+func NewLogMailer(out io.Writer) *LogMailer {
+	return &LogMailer{out: out}
+}
+
+// Send записывает письмо целиком
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if m.out == nil {
+		log.Printf("✉️  Письмо для %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := fmt.Fprintf(m.out, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	return err
+}
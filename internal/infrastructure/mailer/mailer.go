@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Message - текстовое письмо одному получателю
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма. Реализации: SMTPMailer и LogMailer для разработки
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config - выбор и настройки отправки почты
+type Config struct {
+	Backend string // log (по умолчанию) или smtp
+	From    string
+	LogFile string // файл для log, пусто - стандартный лог
+	SMTP    SMTPConfig
+}
+
+// ConfigFromEnv читает настройки из MAILER, MAIL_FROM, MAIL_LOG_FILE и SMTP_*
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Backend: os.Getenv("MAILER"),
+		From:    os.Getenv("MAIL_FROM"),
+		LogFile: os.Getenv("MAIL_LOG_FILE"),
+		SMTP: SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		},
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		parsed, err := strconv.Atoi(port)
+		if err != nil {
+			return cfg, fmt.Errorf("SMTP_PORT: %w", err)
+		}
+		cfg.SMTP.Port = parsed
+	}
+	return cfg, nil
+}
+
+// New создает Mailer по конфигурации
+func New(cfg Config) (Mailer, error) {
+	from := cfg.From
+	if from == "" {
+		from = "task-service@localhost"
+	}
+
+	switch cfg.Backend {
+	case "", "log":
+		if cfg.LogFile == "" {
+			return NewLogMailer(nil), nil
+		}
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mail log: %w", err)
+		}
+		return NewLogMailer(file), nil
+	case "smtp":
+		cfg.SMTP.From = from
+		return NewSMTPMailer(cfg.SMTP)
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP - минимальный SMTP сервер в духе MailHog: без TLS и авторизации, запоминает письма
+type fakeSMTP struct {
+	listener net.Listener
+	messages chan fakeMail
+}
+
+type fakeMail struct {
+	from, to string
+	data     string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeSMTP{listener: listener, messages: make(chan fakeMail, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeSMTP) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var current fakeMail
+	reply("220 localhost fake smtp")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current.from = strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			current.to = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			current.data = data.String()
+			f.messages <- current
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	server := newFakeSMTP(t)
+	addr := server.listener.Addr().(*net.TCPAddr)
+
+	m, err := New(Config{Backend: "smtp", From: "Task Service <noreply@example.com>",
+		SMTP: SMTPConfig{Host: addr.IP.String(), Port: addr.Port}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	body := "Ссылка для сброса пароля:\nhttp://localhost/reset?token=abc\n.\n"
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Сброс пароля", Body: body}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	received := <-server.messages
+	if received.from != "noreply@example.com" || received.to != "user@example.com" {
+		t.Errorf("Unexpected envelope %s -> %s", received.from, received.to)
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(received.data))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != "Сброс пароля" {
+		t.Errorf("Unexpected subject %q (%v)", subject, err)
+	}
+	decoded, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := strings.ReplaceAll(string(decoded), "\r\n", "\n"); got != body {
+		t.Errorf("Unexpected body %q", got)
+	}
+
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "a\r\nBcc: x@example.com"}); err == nil {
+		t.Error("Expected header injection to be rejected")
+	}
+}
+
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLogMailer(&buf)
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Body: "token: abc"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "To: user@example.com") || !strings.Contains(out, "token: abc") {
+		t.Errorf("Unexpected log output %q", out)
+	}
+
+	if _, err := New(Config{Backend: "pigeon"}); err == nil {
+		t.Error("Expected unknown mailer to be rejected")
+	}
+	if _, err := New(Config{Backend: "smtp"}); err == nil {
+		t.Error("Expected smtp without host to be rejected")
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound - объекта с таким ключом нет в хранилище
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore - хранилище файлов по ключу вида "avatars/avatar_1_<random>".
+// Ключ не зависит от бэкенда, поэтому его можно хранить в БД
+type BlobStore interface {
+	// Put сохраняет size байт из r под ключом key, size -1 - размер заранее неизвестен
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение, вызывающий закрывает reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// GetRange открывает объект на чтение с байта offset до конца, вызывающий закрывает reader
+	GetRange(ctx context.Context, key string, offset int64) (io.ReadCloser, error)
+	// Delete удаляет объект, отсутствующий объект не считается ошибкой
+	Delete(ctx context.Context, key string) error
+	// Stat возвращает размер и время изменения объекта
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Move переносит объект под новый ключ, существующий объект с этим ключом заменяется
+	Move(ctx context.Context, srcKey, dstKey string) error
+}
+
+// ObjectInfo - метаданные объекта в хранилище
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string // пусто, если бэкенд не хранит тип
+	ModTime     time.Time
+}
+
+// Config - выбор и настройки бэкенда хранилища
+type Config struct {
+	Backend  string // local (по умолчанию) или s3
+	LocalDir string // каталог для local
+	S3       S3Config
+}
+
+// ConfigFromEnv читает настройки из BLOB_STORE, BLOB_LOCAL_DIR и S3_*
+func ConfigFromEnv() Config {
+	return Config{
+		Backend:  os.Getenv("BLOB_STORE"),
+		LocalDir: os.Getenv("BLOB_LOCAL_DIR"),
+		S3: S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		},
+	}
+}
+
+// New создает хранилище по конфигурации
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case "", "local":
+		dir := cfg.LocalDir
+		if dir == "" {
+			dir = "var"
+		}
+		return NewLocalStore(dir), nil
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.Backend)
+	}
+}
+
+// validKey проверяет, что ключ относительный и не выходит за пределы хранилища
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
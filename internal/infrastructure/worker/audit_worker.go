@@ -3,9 +3,10 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/infrastructure/client"
@@ -13,6 +14,11 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	consumeInitialBackoff = time.Second      // задержка перед повторной подпиской
+	consumeMaxBackoff     = 30 * time.Second // максимальная задержка перед повторной подпиской
+)
+
 type AuditWorker struct {
 	rabbitMQ  *client.RabbitMQClient
 	auditRepo repository.ITaskAuditRepository
@@ -25,56 +31,58 @@ func NewAuditWorker(rabbitMQ *client.RabbitMQClient, auditRepo repository.ITaskA
 	}
 }
 
+// Start потребляет сообщения до отмены контекста. При потере соединения
+// ждет, пока RabbitMQClient переподключится, и подписывается заново
 func (w *AuditWorker) Start(ctx context.Context) {
-	// Создаем отдельное соединение и канал для consumer'а
-
-	rabbitMQURL := fmt.Sprintf("amqp://%s:%s@%s:%s/",
-		os.Getenv("RABBITMQ_USER"),
-		os.Getenv("RABBITMQ_PASSWORD"),
-		os.Getenv("RABBITMQ_HOST"),
-		os.Getenv("RABBITMQ_PORT"))
-	conn, err := amqp.Dial(rabbitMQURL)
-	if err != nil {
-		log.Printf("❌ Ошибка подключения к RabbitMQ для воркера: %v", err)
-		return
+	backoff := consumeInitialBackoff
+	for {
+		consumed, err := w.consume(ctx)
+		if ctx.Err() != nil {
+			fmt.Println("🛑 Audit Worker остановлен")
+			return
+		}
+		if consumed {
+			backoff = consumeInitialBackoff
+		}
+		log.Printf("⚠️  Audit Worker отключен от RabbitMQ: %v, повтор через %v", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("🛑 Audit Worker остановлен")
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, consumeMaxBackoff)
 	}
-	defer conn.Close()
+}
 
-	channel, err := conn.Channel()
+// consume подписывается на очередь аудита и обрабатывает сообщения, пока канал жив.
+// consumed - удалось ли подписаться
+func (w *AuditWorker) consume(ctx context.Context) (consumed bool, err error) {
+	// Отдельный канал для consumer'а, публикация идет через confirm-каналы клиента
+	channel, err := w.rabbitMQ.Channel()
 	if err != nil {
-		log.Printf("❌ Ошибка создания канала для воркера: %v", err)
-		return
+		return false, err
 	}
 	defer channel.Close()
 
-	// Убеждаемся, что очередь существует
-	queueName := "task_audit_logs"
-	_, err = channel.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
-		log.Printf("❌ Ошибка объявления очереди: %v", err)
-		return
+	// Убеждаемся, что очередь, очереди повторов и DLQ существуют
+	if _, err := client.DeclareAuditTopology(channel); err != nil {
+		return false, fmt.Errorf("failed to declare audit topology: %w", err)
 	}
 
 	// Создаем consumer для очереди
 	msgs, err := channel.Consume(
-		queueName,      // queue
-		"audit_worker", // consumer tag (уникальный идентификатор)
-		false,          // auto-ack
-		false,          // exclusive
-		false,          // no-local
-		false,          // no-wait
-		nil,            // args
+		client.AuditQueueName, // queue
+		"audit_worker",        // consumer tag (уникальный идентификатор)
+		false,                 // auto-ack
+		false,                 // exclusive
+		false,                 // no-local
+		false,                 // no-wait
+		nil,                   // args
 	)
 	if err != nil {
-		log.Printf("❌ Ошибка создания consumer: %v", err)
-		return
+		return false, fmt.Errorf("failed to consume: %w", err)
 	}
 
 	fmt.Println("✅ Audit Worker запущен. Ожидаем сообщения...")
@@ -83,28 +91,31 @@ func (w *AuditWorker) Start(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Println("🛑 Audit Worker остановлен")
-			return
+			return true, ctx.Err()
 		case msg, ok := <-msgs:
 			if !ok {
-				fmt.Println("Канал сообщений закрыт")
-				return
+				return true, errors.New("канал сообщений закрыт")
 			}
-			w.processMessage(msg, channel)
+			w.processMessage(msg, w.rabbitMQ)
 		}
 	}
 }
 
-func (w *AuditWorker) processMessage(msg amqp.Delivery, channel *amqp.Channel) {
+// amqpPublisher публикует сообщения на повтор и в DLQ (RabbitMQClient с подтверждениями)
+type amqpPublisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+func (w *AuditWorker) processMessage(msg amqp.Delivery, channel amqpPublisher) {
 	ctx := context.Background()
 
 	log.Printf("Получено сообщение: %s", msg.Body)
 
-	// 1. Парсим сообщение
+	// 1. Парсим сообщение. Битое сообщение не починится повтором - сразу в DLQ
 	var auditMsg entity.AuditMessage
 	if err := json.Unmarshal(msg.Body, &auditMsg); err != nil {
 		log.Printf("❌ Ошибка парсинга сообщения: %v", err)
-		msg.Nack(false, false) // Не возвращаем в очередь
+		w.deadLetter(ctx, msg, channel, err)
 		return
 	}
 
@@ -112,14 +123,14 @@ func (w *AuditWorker) processMessage(msg amqp.Delivery, channel *amqp.Channel) {
 	taskAudit, err := w.convertToTaskAudit(&auditMsg)
 	if err != nil {
 		log.Printf("❌ Ошибка конвертации: %v", err)
-		msg.Nack(false, true) // Возвращаем в очередь для повторной обработки
+		w.deadLetter(ctx, msg, channel, err)
 		return
 	}
 
 	// 3. Сохраняем в БД
 	if err := w.auditRepo.Create(ctx, taskAudit); err != nil {
 		log.Printf("❌ Ошибка сохранения аудита: %v", err)
-		msg.Nack(false, true) // Возвращаем в очередь для повторной обработки
+		w.retry(ctx, msg, channel, err)
 		return
 	}
 
@@ -128,6 +139,53 @@ func (w *AuditWorker) processMessage(msg amqp.Delivery, channel *amqp.Channel) {
 	log.Printf("✅ Аудит сохранен: %s задача ID=%d", taskAudit.Action, taskAudit.EntityID)
 }
 
+// retry откладывает сообщение в очередь задержки, а после AuditMaxRetries попыток отправляет в DLQ
+func (w *AuditWorker) retry(ctx context.Context, msg amqp.Delivery, channel amqpPublisher, cause error) {
+	attempt := client.RetryCount(msg.Headers) + 1
+	if attempt > client.AuditMaxRetries {
+		w.deadLetter(ctx, msg, channel, cause)
+		return
+	}
+
+	err := channel.PublishWithContext(ctx, "", client.AuditRetryQueueName(attempt), false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+		DeliveryMode: amqp.Persistent,
+		Headers:      amqp.Table{client.RetryCountHeader: int32(attempt)},
+	})
+	if err != nil {
+		// Не смогли отложить - возвращаем в очередь, чтобы не потерять сообщение
+		log.Printf("❌ Ошибка отправки сообщения на повтор: %v", err)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+	log.Printf("🔁 Сообщение отложено на повтор %d/%d через %v", attempt, client.AuditMaxRetries, client.AuditRetryDelay(attempt))
+}
+
+// deadLetter отправляет сообщение в DLQ вместе с причиной ошибки
+func (w *AuditWorker) deadLetter(ctx context.Context, msg amqp.Delivery, channel amqpPublisher, cause error) {
+	err := channel.PublishWithContext(ctx, client.AuditDeadLetterExchange, client.AuditQueueName, false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Headers: amqp.Table{
+			client.RetryCountHeader: int32(client.RetryCount(msg.Headers)),
+			client.LastErrorHeader:  cause.Error(),
+		},
+	})
+	if err != nil {
+		log.Printf("❌ Ошибка отправки сообщения в DLQ: %v", err)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+	log.Printf("☠️  Сообщение отправлено в DLQ: %v", cause)
+}
+
 func (w *AuditWorker) convertToTaskAudit(msg *entity.AuditMessage) (*entity.TaskAudit, error) {
 	// Конвертируем map[string]any в JSON строки
 	var oldValuesJSON, newValuesJSON, changesJSON *string
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/client"
+	"github.com/St1cky1/task-service/internal/repository"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// failingAuditRepo - ITaskAuditRepository, который может не сохранять аудит
+type failingAuditRepo struct {
+	err     error
+	created []*entity.TaskAudit
+}
+
+var _ repository.ITaskAuditRepository = (*failingAuditRepo)(nil)
+
+func (r *failingAuditRepo) Create(ctx context.Context, audit *entity.TaskAudit) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.created = append(r.created, audit)
+	return nil
+}
+
+func (r *failingAuditRepo) ListByTask(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error) {
+	return &entity.TaskAuditPage{}, nil
+}
+
+func (r *failingAuditRepo) GetTaskOwnerID(ctx context.Context, taskID int) (int, error) {
+	return 0, nil
+}
+
+// recordingChannel запоминает опубликованные сообщения и ack/nack доставки
+type recordingChannel struct {
+	published []recordedPublishing
+	acked     int
+	nacked    int
+	requeued  int
+}
+
+type recordedPublishing struct {
+	exchange string
+	key      string
+	msg      amqp.Publishing
+}
+
+func (c *recordingChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.published = append(c.published, recordedPublishing{exchange: exchange, key: key, msg: msg})
+	return nil
+}
+
+func (c *recordingChannel) Ack(tag uint64, multiple bool) error {
+	c.acked++
+	return nil
+}
+
+func (c *recordingChannel) Nack(tag uint64, multiple, requeue bool) error {
+	c.nacked++
+	if requeue {
+		c.requeued++
+	}
+	return nil
+}
+
+func (c *recordingChannel) Reject(tag uint64, requeue bool) error {
+	return c.Nack(tag, false, requeue)
+}
+
+func newDelivery(channel *recordingChannel, body string, retryCount int) amqp.Delivery {
+	delivery := amqp.Delivery{Acknowledger: channel, Body: []byte(body)}
+	if retryCount > 0 {
+		delivery.Headers = amqp.Table{client.RetryCountHeader: int32(retryCount)}
+	}
+	return delivery
+}
+
+const validAuditBody = `{"user_id": 1, "action": "Create", "entity_id": 1}`
+
+func TestAuditWorkerRetriesOnDBError(t *testing.T) {
+	channel := &recordingChannel{}
+	worker := NewAuditWorker(nil, &failingAuditRepo{err: errors.New("db is down")})
+
+	worker.processMessage(newDelivery(channel, validAuditBody, 2), channel)
+
+	if len(channel.published) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(channel.published))
+	}
+	published := channel.published[0]
+	if published.exchange != "" || published.key != client.AuditRetryQueueName(3) {
+		t.Errorf("Expected retry queue %s, got %q/%q", client.AuditRetryQueueName(3), published.exchange, published.key)
+	}
+	if got := client.RetryCount(published.msg.Headers); got != 3 {
+		t.Errorf("Expected retry count 3, got %d", got)
+	}
+	if channel.acked != 1 || channel.nacked != 0 {
+		t.Errorf("Expected original message to be acked, got acked=%d nacked=%d", channel.acked, channel.nacked)
+	}
+}
+
+func TestAuditWorkerDeadLettersAfterMaxRetries(t *testing.T) {
+	channel := &recordingChannel{}
+	worker := NewAuditWorker(nil, &failingAuditRepo{err: errors.New("db is down")})
+
+	worker.processMessage(newDelivery(channel, validAuditBody, client.AuditMaxRetries), channel)
+
+	if len(channel.published) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(channel.published))
+	}
+	published := channel.published[0]
+	if published.exchange != client.AuditDeadLetterExchange {
+		t.Errorf("Expected dead letter exchange, got %q", published.exchange)
+	}
+	if published.msg.Headers[client.LastErrorHeader] != "db is down" {
+		t.Errorf("Expected last error header, got %v", published.msg.Headers)
+	}
+	if channel.acked != 1 {
+		t.Errorf("Expected original message to be acked, got %d", channel.acked)
+	}
+}
+
+func TestAuditWorkerDeadLettersPoisonMessage(t *testing.T) {
+	channel := &recordingChannel{}
+	repo := &failingAuditRepo{}
+	worker := NewAuditWorker(nil, repo)
+
+	worker.processMessage(newDelivery(channel, "not json", 0), channel)
+
+	if len(channel.published) != 1 || channel.published[0].exchange != client.AuditDeadLetterExchange {
+		t.Fatalf("Expected poison message in dead letter exchange, got %+v", channel.published)
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("Expected nothing saved, got %d audits", len(repo.created))
+	}
+}
+
+func TestAuditWorkerSavesAudit(t *testing.T) {
+	channel := &recordingChannel{}
+	repo := &failingAuditRepo{}
+	worker := NewAuditWorker(nil, repo)
+
+	worker.processMessage(newDelivery(channel, validAuditBody, 1), channel)
+
+	if len(repo.created) != 1 || channel.acked != 1 || len(channel.published) != 0 {
+		t.Errorf("Expected audit saved and acked, got created=%d acked=%d published=%d",
+			len(repo.created), channel.acked, len(channel.published))
+	}
+}
+
+func TestAuditWorkerKeepsEventTime(t *testing.T) {
+	channel := &recordingChannel{}
+	repo := &failingAuditRepo{}
+	worker := NewAuditWorker(nil, repo)
+
+	// Сообщение пришло с опозданием (повтор, DLQ): в историю идет время события
+	body := `{"user_id": 1, "action": "Update", "entity_id": 1, "timestamp": "2026-01-02T03:04:05Z"}`
+	worker.processMessage(newDelivery(channel, body, 0), channel)
+
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if len(repo.created) != 1 || !repo.created[0].ChangesAt.Equal(want) {
+		t.Fatalf("Expected audit with event time %s, got %+v", want, repo.created)
+	}
+}
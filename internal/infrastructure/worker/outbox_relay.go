@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// AuditPublisher публикует аудит-сообщения в брокер
+type AuditPublisher interface {
+	PublishAuditMessage(ctx context.Context, message *entity.AuditMessage) error
+}
+
+// OutboxRelayConfig - настройки OutboxRelay
+type OutboxRelayConfig struct {
+	PollInterval   time.Duration // как часто проверять outbox
+	BatchSize      int           // сколько сообщений забирать за раз
+	Lease          time.Duration // на сколько сообщение блокируется для других реплик
+	InitialBackoff time.Duration // задержка после первой неудачи
+	MaxBackoff     time.Duration // максимальная задержка между попытками
+	Retention      time.Duration // сколько хранить отправленные сообщения
+}
+
+// DefaultOutboxRelayConfig возвращает настройки по умолчанию
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		PollInterval:   time.Second,
+		BatchSize:      100,
+		Lease:          30 * time.Second,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Retention:      24 * time.Hour,
+	}
+}
+
+// OutboxRelay публикует сообщения из audit_outbox в RabbitMQ (at-least-once)
+type OutboxRelay struct {
+	outboxRepo repository.IAuditOutboxRepository
+	publisher  AuditPublisher
+	cfg        OutboxRelayConfig
+	now        func() time.Time
+}
+
+func NewOutboxRelay(outboxRepo repository.IAuditOutboxRepository, publisher AuditPublisher, cfg OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// Start крутит relay до отмены контекста
+func (r *OutboxRelay) Start(ctx context.Context) {
+	pollTicker := time.NewTicker(r.cfg.PollInterval)
+	defer pollTicker.Stop()
+
+	cleanupTicker := time.NewTicker(time.Hour)
+	defer cleanupTicker.Stop()
+
+	fmt.Println("✅ Outbox Relay запущен")
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("🛑 Outbox Relay остановлен")
+			return
+		case <-pollTicker.C:
+			// Разгребаем outbox, пока приходят полные пачки
+			for {
+				processed, err := r.ProcessBatch(ctx)
+				if err != nil {
+					log.Printf("❌ Ошибка обработки outbox: %v", err)
+					break
+				}
+				if processed < r.cfg.BatchSize {
+					break
+				}
+			}
+		case <-cleanupTicker.C:
+			deleted, err := r.outboxRepo.DeleteSentBefore(ctx, r.now().Add(-r.cfg.Retention))
+			if err != nil {
+				log.Printf("❌ Ошибка очистки outbox: %v", err)
+			} else if deleted > 0 {
+				log.Printf("🧹 Удалено %d отправленных сообщений из outbox", deleted)
+			}
+		}
+	}
+}
+
+// ProcessBatch публикует одну пачку сообщений и возвращает их количество
+func (r *OutboxRelay) ProcessBatch(ctx context.Context) (int, error) {
+	messages, err := r.outboxRepo.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	for _, message := range messages {
+		var audit entity.AuditMessage
+		if err := json.Unmarshal(message.Payload, &audit); err != nil {
+			// Повтор не поможет: откладываем сообщение в сторону и публикуем остальные
+			log.Printf("❌ Битое сообщение в outbox ID=%d, больше не публикуется: %v", message.ID, err)
+			if markErr := r.outboxRepo.MarkDead(ctx, message.ID, err.Error()); markErr != nil {
+				return 0, fmt.Errorf("failed to mark outbox message %d as dead: %w", message.ID, markErr)
+			}
+			continue
+		}
+
+		if err := r.publisher.PublishAuditMessage(ctx, &audit); err != nil {
+			nextAttemptAt := r.now().Add(r.backoff(message.Attempts))
+			log.Printf("❌ Ошибка публикации аудита из outbox ID=%d (попытка %d): %v", message.ID, message.Attempts+1, err)
+			if markErr := r.outboxRepo.MarkFailed(ctx, message.ID, nextAttemptAt, err.Error()); markErr != nil {
+				return 0, fmt.Errorf("failed to mark outbox message %d as failed: %w", message.ID, markErr)
+			}
+			continue
+		}
+
+		// Если упадем здесь, сообщение уйдет повторно после lease - это и есть at-least-once
+		if err := r.outboxRepo.MarkSent(ctx, message.ID); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox message %d as sent: %w", message.ID, err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+// backoff - экспоненциальная задержка перед следующей попыткой
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.cfg.InitialBackoff
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
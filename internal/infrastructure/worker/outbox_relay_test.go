@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// memoryOutbox - in-memory IAuditOutboxRepository
+type memoryOutbox struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	nextID   int64
+	messages map[int64]*memoryOutboxRow
+}
+
+type memoryOutboxRow struct {
+	message       entity.OutboxMessage
+	nextAttemptAt time.Time
+	sent          bool
+	dead          bool
+	lastError     string
+}
+
+var _ repository.IAuditOutboxRepository = (*memoryOutbox)(nil)
+
+func newMemoryOutbox(now func() time.Time) *memoryOutbox {
+	return &memoryOutbox{now: now, messages: make(map[int64]*memoryOutboxRow)}
+}
+
+func (o *memoryOutbox) Add(ctx context.Context, message *entity.AuditMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	o.addPayload(payload)
+	return nil
+}
+
+// addPayload кладет в outbox сырой payload, в том числе битый
+func (o *memoryOutbox) addPayload(payload []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nextID++
+	o.messages[o.nextID] = &memoryOutboxRow{
+		message:       entity.OutboxMessage{ID: o.nextID, Payload: payload},
+		nextAttemptAt: o.now(),
+	}
+}
+
+func (o *memoryOutbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var claimed []entity.OutboxMessage
+	for id := int64(1); id <= o.nextID && len(claimed) < limit; id++ {
+		row, ok := o.messages[id]
+		if !ok || row.sent || row.dead || row.nextAttemptAt.After(o.now()) {
+			continue
+		}
+		row.nextAttemptAt = o.now().Add(lease)
+		claimed = append(claimed, row.message)
+	}
+	return claimed, nil
+}
+
+func (o *memoryOutbox) MarkSent(ctx context.Context, id int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[id].sent = true
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	row := o.messages[id]
+	row.message.Attempts++
+	row.nextAttemptAt = nextAttemptAt
+	row.lastError = lastError
+	return nil
+}
+
+func (o *memoryOutbox) MarkDead(ctx context.Context, id int64, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	row := o.messages[id]
+	row.message.Attempts++
+	row.dead = true
+	row.lastError = lastError
+	return nil
+}
+
+func (o *memoryOutbox) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryPublisher - in-memory AuditPublisher, который может "лежать"
+type memoryPublisher struct {
+	down      bool
+	published []entity.AuditMessage
+}
+
+func (p *memoryPublisher) PublishAuditMessage(ctx context.Context, message *entity.AuditMessage) error {
+	if p.down {
+		return errors.New("broker is down")
+	}
+	p.published = append(p.published, *message)
+	return nil
+}
+
+func TestOutboxRelayPublishesPending(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	outbox := newMemoryOutbox(clock)
+	publisher := &memoryPublisher{}
+	relay := NewOutboxRelay(outbox, publisher, DefaultOutboxRelayConfig())
+	relay.now = clock
+
+	for i := 1; i <= 3; i++ {
+		outbox.Add(ctx, &entity.AuditMessage{Action: entity.ActionCreate, EntityID: i})
+	}
+
+	processed, err := relay.ProcessBatch(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if processed != 3 || len(publisher.published) != 3 {
+		t.Fatalf("Expected 3 published messages, got %d (processed %d)", len(publisher.published), processed)
+	}
+	for i, message := range publisher.published {
+		if message.EntityID != i+1 {
+			t.Errorf("Expected messages in outbox order, got entity %d at %d", message.EntityID, i)
+		}
+	}
+
+	// Повторный проход ничего не публикует
+	processed, _ = relay.ProcessBatch(ctx)
+	if processed != 0 {
+		t.Errorf("Expected no pending messages, got %d", processed)
+	}
+}
+
+func TestOutboxRelayRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	outbox := newMemoryOutbox(clock)
+	publisher := &memoryPublisher{down: true}
+	cfg := DefaultOutboxRelayConfig()
+	relay := NewOutboxRelay(outbox, publisher, cfg)
+	relay.now = clock
+
+	outbox.Add(ctx, &entity.AuditMessage{Action: entity.ActionUpdate, EntityID: 1})
+
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	row := outbox.messages[1]
+	if row.sent || row.message.Attempts != 1 || row.lastError == "" {
+		t.Fatalf("Expected failed attempt to be recorded, got %+v", row)
+	}
+	if !row.nextAttemptAt.Equal(now.Add(cfg.InitialBackoff)) {
+		t.Errorf("Expected next attempt after %v, got %v", cfg.InitialBackoff, row.nextAttemptAt.Sub(now))
+	}
+
+	// До истечения backoff сообщение не забирается
+	if processed, _ := relay.ProcessBatch(ctx); processed != 0 {
+		t.Errorf("Expected message to wait for backoff, got %d processed", processed)
+	}
+
+	// Брокер поднялся и backoff истек - сообщение доставлено
+	publisher.down = false
+	now = now.Add(cfg.InitialBackoff)
+	if _, err := relay.ProcessBatch(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !outbox.messages[1].sent || len(publisher.published) != 1 {
+		t.Errorf("Expected message to be delivered after retry")
+	}
+}
+
+func TestOutboxRelaySkipsPoisonMessage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	outbox := newMemoryOutbox(clock)
+	publisher := &memoryPublisher{}
+	relay := NewOutboxRelay(outbox, publisher, DefaultOutboxRelayConfig())
+	relay.now = clock
+
+	outbox.Add(ctx, &entity.AuditMessage{Action: entity.ActionCreate, EntityID: 1})
+	outbox.addPayload([]byte(`{"action":`))
+	outbox.Add(ctx, &entity.AuditMessage{Action: entity.ActionCreate, EntityID: 3})
+
+	processed, err := relay.ProcessBatch(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if processed != 3 || len(publisher.published) != 2 {
+		t.Fatalf("Expected 2 of 3 messages published, got %d (processed %d)", len(publisher.published), processed)
+	}
+	if row := outbox.messages[2]; !row.dead || row.sent || row.lastError == "" {
+		t.Errorf("Expected poison message to be marked dead, got %+v", row)
+	}
+	if !outbox.messages[1].sent || !outbox.messages[3].sent {
+		t.Error("Expected valid messages to be marked sent")
+	}
+
+	// Битое сообщение больше не забирается
+	now = now.Add(time.Hour)
+	if processed, _ := relay.ProcessBatch(ctx); processed != 0 {
+		t.Errorf("Expected dead message not to be reclaimed, got %d processed", processed)
+	}
+}
+
+func TestOutboxRelayBackoffIsCapped(t *testing.T) {
+	relay := NewOutboxRelay(nil, nil, DefaultOutboxRelayConfig())
+
+	if got := relay.backoff(0); got != time.Second {
+		t.Errorf("Expected 1s, got %v", got)
+	}
+	if got := relay.backoff(3); got != 8*time.Second {
+		t.Errorf("Expected 8s, got %v", got)
+	}
+	if got := relay.backoff(100); got != 5*time.Minute {
+		t.Errorf("Expected cap 5m, got %v", got)
+	}
+}
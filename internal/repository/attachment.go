@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ключи advisory-блокировок квот вложений задачи и пользователя
+const (
+	taskAttachmentLockKey = 1015
+	userAttachmentLockKey = 1016
+)
+
+const attachmentColumns = `id, task_id, uploader_id, file_name, storage_key, file_size, content_type, sha256, created_at`
+
+// attachmentFields возвращает указатели на поля вложения для Scan в порядке attachmentColumns
+func attachmentFields(attachment *entity.TaskAttachment) []interface{} {
+	return []interface{}{
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.UploaderID,
+		&attachment.FileName,
+		&attachment.StorageKey,
+		&attachment.FileSize,
+		&attachment.ContentType,
+		&attachment.SHA256,
+		&attachment.CreatedAt,
+	}
+}
+
+type AttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *entity.TaskAttachment) (*entity.TaskAttachment, error) {
+	query := `
+	INSERT INTO "task_attachment" (task_id, uploader_id, file_name, storage_key, file_size, content_type, sha256)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING ` + attachmentColumns + `
+	`
+
+	var created entity.TaskAttachment
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		attachment.TaskID,
+		attachment.UploaderID,
+		attachment.FileName,
+		attachment.StorageKey,
+		attachment.FileSize,
+		attachment.ContentType,
+		attachment.SHA256,
+	).Scan(attachmentFields(&created)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id int) (*entity.TaskAttachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM "task_attachment" WHERE id = $1`
+
+	var attachment entity.TaskAttachment
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(attachmentFields(&attachment)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// ListByTasks - вложения задач в порядке загрузки
+func (r *AttachmentRepository) ListByTasks(ctx context.Context, taskIDs []int) ([]entity.TaskAttachment, error) {
+	query := `
+	SELECT ` + attachmentColumns + `
+	FROM "task_attachment"
+	WHERE task_id = ANY($1)
+	ORDER BY task_id, id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []entity.TaskAttachment{}
+	for rows.Next() {
+		var attachment entity.TaskAttachment
+		if err := rows.Scan(attachmentFields(&attachment)...); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id int) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "task_attachment" WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// LockUsage блокирует квоты задачи и пользователя до конца транзакции,
+// чтобы параллельные загрузки не превысили их вместе
+func (r *AttachmentRepository) LockUsage(ctx context.Context, taskID int, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock($1, $2), pg_advisory_xact_lock($3, $4)`,
+		taskAttachmentLockKey, taskID, userAttachmentLockKey, userID)
+	return err
+}
+
+// GetUsage - суммарный размер вложений задачи и вложений, загруженных пользователем
+func (r *AttachmentRepository) GetUsage(ctx context.Context, taskID int, userID int) (*entity.AttachmentUsage, error) {
+	query := `
+	SELECT
+		(SELECT COALESCE(SUM(file_size), 0) FROM task_attachment WHERE task_id = $1),
+		(SELECT COALESCE(SUM(file_size), 0) FROM task_attachment WHERE uploader_id = $2)
+	`
+
+	var usage entity.AttachmentUsage
+	if err := conn(ctx, r.db).QueryRow(ctx, query, taskID, userID).Scan(&usage.TaskBytes, &usage.UserBytes); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditOutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditOutboxRepository(db *pgxpool.Pool) *AuditOutboxRepository {
+	return &AuditOutboxRepository{
+		db: db,
+	}
+}
+
+// Add - кладем аудит-сообщение в outbox (в транзакции из контекста, если она есть)
+func (r *AuditOutboxRepository) Add(ctx context.Context, message *entity.AuditMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO "audit_outbox" (payload) VALUES ($1)`
+	_, err = conn(ctx, r.db).Exec(ctx, query, payload)
+	return err
+}
+
+// ClaimPending - забираем пачку неотправленных сообщений и откладываем их на lease,
+// чтобы другие реплики relay не взяли их одновременно
+func (r *AuditOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	query := `
+	UPDATE "audit_outbox"
+	SET next_attempt_at = NOW() + make_interval(secs => $2)
+	WHERE id IN (
+	    SELECT id FROM "audit_outbox"
+	    WHERE sent_at IS NULL AND dead_at IS NULL AND next_attempt_at <= NOW()
+	    ORDER BY id
+	    LIMIT $1
+	    FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, payload, attempts, created_at
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []entity.OutboxMessage
+	for rows.Next() {
+		var message entity.OutboxMessage
+		if err := rows.Scan(&message.ID, &message.Payload, &message.Attempts, &message.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING не гарантирует порядок
+	slices.SortFunc(messages, func(a, b entity.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+// MarkSent - отмечаем сообщение отправленным
+func (r *AuditOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	query := `UPDATE "audit_outbox" SET sent_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := conn(ctx, r.db).Exec(ctx, query, id)
+	return err
+}
+
+// MarkFailed - фиксируем неудачную попытку и время следующей
+func (r *AuditOutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	query := `
+	UPDATE "audit_outbox"
+	SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+	WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, id, nextAttemptAt, lastError)
+	return err
+}
+
+// MarkDead - сообщение не может быть опубликовано, больше его не забираем.
+// Такие строки остаются в таблице для разбора
+func (r *AuditOutboxRepository) MarkDead(ctx context.Context, id int64, lastError string) error {
+	query := `
+	UPDATE "audit_outbox"
+	SET attempts = attempts + 1, dead_at = NOW(), last_error = $2
+	WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, id, lastError)
+	return err
+}
+
+// DeleteSentBefore - удаляем давно отправленные сообщения
+func (r *AuditOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM "audit_outbox" WHERE sent_at IS NOT NULL AND sent_at < $1`
+	result, err := conn(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
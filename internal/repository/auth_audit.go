@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuthAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuthAuditRepository(db *pgxpool.Pool) *AuthAuditRepository {
+	return &AuthAuditRepository{
+		db: db,
+	}
+}
+
+// Create - пишем событие в журнал входов
+func (r *AuthAuditRepository) Create(ctx context.Context, event *entity.AuthEvent) error {
+	query := `
+	INSERT INTO auth_audit_log (event, user_id, email, ip_address, user_agent, details)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		event.Type,
+		event.UserID,
+		event.Email,
+		event.IPAddress,
+		event.UserAgent,
+		event.Details,
+	).Scan(&event.ID, &event.CreatedAt)
+}
@@ -21,24 +21,26 @@ func NewAvatarRepository(db *pgxpool.Pool) *AvatarRepository {
 // Save - создает или обновляет аватарку
 func (r *AvatarRepository) Save(ctx context.Context, avatar *entity.Avatar) (*entity.Avatar, error) {
 	query := `
-	INSERT INTO "avatar" (user_id, file_path, file_size, content_type)
-	VALUES ($1, $2, $3, $4)
+	INSERT INTO "avatar" (user_id, storage_key, file_size, content_type, sha256)
+	VALUES ($1, $2, $3, $4, $5)
 	ON CONFLICT (user_id) DO UPDATE SET
-	    file_path = $2,
+	    storage_key = $2,
 	    file_size = $3,
 	    content_type = $4,
+	    sha256 = $5,
 	    updated_at = CURRENT_TIMESTAMP
-	RETURNING id, user_id, file_path, file_size, content_type, created_at, updated_at
+	RETURNING id, user_id, storage_key, file_size, content_type, sha256, created_at, updated_at
 	`
 
 	var savedAvatar entity.Avatar
 
-	err := r.db.QueryRow(ctx, query, avatar.UserID, avatar.FilePath, avatar.FileSize, avatar.ContentType).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, avatar.UserID, avatar.StorageKey, avatar.FileSize, avatar.ContentType, avatar.SHA256).Scan(
 		&savedAvatar.ID,
 		&savedAvatar.UserID,
-		&savedAvatar.FilePath,
+		&savedAvatar.StorageKey,
 		&savedAvatar.FileSize,
 		&savedAvatar.ContentType,
+		&savedAvatar.SHA256,
 		&savedAvatar.CreatedAt,
 		&savedAvatar.UpdatedAt,
 	)
@@ -53,19 +55,20 @@ func (r *AvatarRepository) Save(ctx context.Context, avatar *entity.Avatar) (*en
 // GetByUserId - получает аватарку по user_id
 func (r *AvatarRepository) GetByUserId(ctx context.Context, userId int) (*entity.Avatar, error) {
 	query := `
-	SELECT id, user_id, file_path, file_size, content_type, created_at, updated_at
+	SELECT id, user_id, storage_key, file_size, content_type, sha256, created_at, updated_at
 	FROM "avatar"
 	WHERE user_id = $1
 	`
 
 	var avatar entity.Avatar
 
-	err := r.db.QueryRow(ctx, query, userId).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, userId).Scan(
 		&avatar.ID,
 		&avatar.UserID,
-		&avatar.FilePath,
+		&avatar.StorageKey,
 		&avatar.FileSize,
 		&avatar.ContentType,
+		&avatar.SHA256,
 		&avatar.CreatedAt,
 		&avatar.UpdatedAt,
 	)
@@ -83,7 +86,7 @@ func (r *AvatarRepository) GetByUserId(ctx context.Context, userId int) (*entity
 // DeleteByUserId - удаляет аватарку по user_id
 func (r *AvatarRepository) DeleteByUserId(ctx context.Context, userId int) error {
 	query := `DELETE FROM "avatar" WHERE user_id = $1`
-	result, err := r.db.Exec(ctx, query, userId)
+	result, err := conn(ctx, r.db).Exec(ctx, query, userId)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CollaboratorRepository - исполнители задач и выданный доступ
+type CollaboratorRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCollaboratorRepository(db *pgxpool.Pool) *CollaboratorRepository {
+	return &CollaboratorRepository{
+		db: db,
+	}
+}
+
+// GetAccess - доступ пользователя к задаче по назначениям и выданному доступу.
+// Учитываются и предки задачи: доступ к задаче дает доступ ко всем ее подзадачам.
+// Владельца не проверяет
+func (r *CollaboratorRepository) GetAccess(ctx context.Context, taskID, userID int) (entity.TaskAccess, error) {
+	query := `
+	WITH RECURSIVE chain AS (
+		SELECT id, parent_id, ARRAY[id] AS path
+		FROM task
+		WHERE id = $1
+		UNION ALL
+		SELECT t.id, t.parent_id, c.path || t.id
+		FROM task t
+		JOIN chain c ON t.id = c.parent_id
+		WHERE t.id <> ALL(c.path)
+	)
+	SELECT
+		EXISTS (SELECT 1 FROM task_assignee WHERE user_id = $2 AND task_id IN (SELECT id FROM chain)),
+		EXISTS (SELECT 1 FROM task_share WHERE user_id = $2 AND role = 'editor' AND task_id IN (SELECT id FROM chain)),
+		EXISTS (SELECT 1 FROM task_share WHERE user_id = $2 AND task_id IN (SELECT id FROM chain))
+	`
+
+	var assigned, editor, shared bool
+	if err := conn(ctx, r.db).QueryRow(ctx, query, taskID, userID).Scan(&assigned, &editor, &shared); err != nil {
+		return entity.TaskAccessNone, err
+	}
+
+	switch {
+	case assigned || editor:
+		return entity.TaskAccessWrite, nil
+	case shared:
+		return entity.TaskAccessRead, nil
+	}
+	return entity.TaskAccessNone, nil
+}
+
+// AddAssignee - назначение исполнителя, повторное назначение ничего не меняет
+func (r *CollaboratorRepository) AddAssignee(ctx context.Context, taskID, userID int) error {
+	query := `
+	INSERT INTO "task_assignee" (task_id, user_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, taskID, userID)
+	return err
+}
+
+// RemoveAssignee - снятие исполнителя
+func (r *CollaboratorRepository) RemoveAssignee(ctx context.Context, taskID, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "task_assignee" WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+	return err
+}
+
+// ListAssigneeIDs - исполнители задач, сгруппированные по ID задачи
+func (r *CollaboratorRepository) ListAssigneeIDs(ctx context.Context, taskIDs []int) (map[int][]int, error) {
+	result := make(map[int][]int, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+	SELECT task_id, user_id
+	FROM "task_assignee"
+	WHERE task_id = ANY($1)
+	ORDER BY task_id, user_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, userID int
+		if err := rows.Scan(&taskID, &userID); err != nil {
+			return nil, err
+		}
+		result[taskID] = append(result[taskID], userID)
+	}
+
+	return result, rows.Err()
+}
+
+// SetShare - выдача доступа или смена роли
+func (r *CollaboratorRepository) SetShare(ctx context.Context, taskID, userID int, role entity.ShareRole) (*entity.TaskShare, error) {
+	query := `
+	INSERT INTO "task_share" (task_id, user_id, role)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (task_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	RETURNING task_id, user_id, role, created_at
+	`
+
+	var share entity.TaskShare
+	err := conn(ctx, r.db).QueryRow(ctx, query, taskID, userID, role).Scan(
+		&share.TaskID,
+		&share.UserID,
+		&share.Role,
+		&share.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+// RemoveShare - отзыв доступа
+func (r *CollaboratorRepository) RemoveShare(ctx context.Context, taskID, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "task_share" WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+	return err
+}
+
+// ListShares - кому выдан доступ к задаче
+func (r *CollaboratorRepository) ListShares(ctx context.Context, taskID int) ([]entity.TaskShare, error) {
+	query := `
+	SELECT task_id, user_id, role, created_at
+	FROM "task_share"
+	WHERE task_id = $1
+	ORDER BY created_at, user_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []entity.TaskShare{}
+	for rows.Next() {
+		var share entity.TaskShare
+		if err := rows.Scan(&share.TaskID, &share.UserID, &share.Role, &share.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// commentColumns - колонки комментария в порядке commentFields, reply_count считается подзапросом
+const commentColumns = `id, task_id, parent_comment_id, author_id, body, created_at, updated_at, edited_at, deleted_at,
+	(SELECT COUNT(*) FROM task_comment r WHERE r.parent_comment_id = task_comment.id AND r.deleted_at IS NULL)`
+
+// commentFields возвращает указатели на поля комментария для Scan в порядке commentColumns
+func commentFields(comment *entity.TaskComment) []interface{} {
+	return []interface{}{
+		&comment.ID,
+		&comment.TaskID,
+		&comment.ParentCommentID,
+		&comment.AuthorID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+		&comment.DeletedAt,
+		&comment.ReplyCount,
+	}
+}
+
+type CommentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentRepository(db *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, authorID int, req *entity.CreateCommentRequest) (*entity.TaskComment, error) {
+	query := `
+	INSERT INTO "task_comment" (task_id, parent_comment_id, author_id, body)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + commentColumns + `
+	`
+
+	var comment entity.TaskComment
+	err := conn(ctx, r.db).QueryRow(ctx, query, req.TaskID, req.ParentCommentID, authorID, req.Body).Scan(commentFields(&comment)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*entity.TaskComment, error) {
+	query := `
+	SELECT ` + commentColumns + `
+	FROM "task_comment"
+	WHERE id = $1
+	`
+
+	var comment entity.TaskComment
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(commentFields(&comment)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Update - замена текста комментария. Предыдущий текст сохраняется в task_comment_revision.
+// Удаленный комментарий не меняется, тогда возвращает ErrCommentNotFound
+func (r *CommentRepository) Update(ctx context.Context, id int, body string) (*entity.TaskComment, error) {
+	query := `
+	WITH old AS (
+		SELECT id, body FROM task_comment WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	), revision AS (
+		INSERT INTO task_comment_revision (comment_id, body)
+		SELECT id, body FROM old
+	)
+	UPDATE task_comment
+	SET body = $2, edited_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = (SELECT id FROM old)
+	RETURNING ` + commentColumns + `
+	`
+
+	var comment entity.TaskComment
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, body).Scan(commentFields(&comment)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Delete - удаление комментария: текст и его история стираются, сам комментарий
+// остается, чтобы не разрывать ветку ответов
+func (r *CommentRepository) Delete(ctx context.Context, id int) error {
+	query := `
+	WITH revisions AS (
+		DELETE FROM task_comment_revision WHERE comment_id = $1
+	)
+	UPDATE task_comment
+	SET body = '', deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCommentNotFound
+	}
+	return nil
+}
+
+// commentSortColumns - поля, по которым можно сортировать комментарии
+var commentSortColumns = map[string]sortColumn{
+	entity.CommentSortCreatedAt: {column: "created_at", cast: "timestamptz"},
+}
+
+// List - страница корневых комментариев задачи или ответов на комментарий
+func (r *CommentRepository) List(ctx context.Context, req *entity.ListCommentsRequest) (*entity.CommentPage, error) {
+	col, ok := commentSortColumns[req.Page.SortBy]
+	if !ok {
+		return nil, entity.ErrInvalidSort
+	}
+
+	scope := fmt.Sprintf("task=%d;parent=%s", req.TaskID, formatScopeID(req.ParentCommentID))
+	cursor, err := decodeCursor(req.Page, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	fromWhere := `FROM "task_comment" WHERE task_id = $1`
+	args := []interface{}{req.TaskID}
+	if req.ParentCommentID != nil {
+		args = append(args, *req.ParentCommentID)
+		fromWhere += " AND parent_comment_id = $" + strconv.Itoa(len(args))
+	} else {
+		fromWhere += " AND parent_comment_id IS NULL"
+	}
+
+	page := &entity.CommentPage{}
+	if req.Page.Total != entity.TotalNone {
+		total, err := countRows(ctx, r.db, fromWhere, args, req.Page.Total)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.TotalEstimated = req.Page.Total == entity.TotalEstimated
+	}
+
+	query := `SELECT ` + commentColumns + ` ` + fromWhere
+	if cond, condArgs := keysetCondition(col, req.Page.SortDir, cursor, len(args)+1); cond != "" {
+		query += " AND " + cond
+		args = append(args, condArgs...)
+	}
+	query += keysetOrder(col, req.Page.SortDir)
+
+	// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+	args = append(args, req.Page.PageSize+1)
+	query += " LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []entity.TaskComment{}
+	for rows.Next() {
+		var comment entity.TaskComment
+		if err := rows.Scan(commentFields(&comment)...); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(comments) > req.Page.PageSize {
+		comments = comments[:req.Page.PageSize]
+		last := comments[len(comments)-1]
+		page.NextPageToken = encodeCursor(pageCursor{
+			SortBy:  req.Page.SortBy,
+			SortDir: string(req.Page.SortDir),
+			Scope:   scope,
+			Value:   last.CreatedAt.Format(time.RFC3339Nano),
+			ID:      last.ID,
+		})
+	}
+
+	page.Comments = comments
+	return page, nil
+}
+
+// ListRevisions - предыдущие версии текста комментария, от старых к новым
+func (r *CommentRepository) ListRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error) {
+	query := `
+	SELECT id, comment_id, body, created_at
+	FROM "task_comment_revision"
+	WHERE comment_id = $1
+	ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []entity.CommentRevision{}
+	for rows.Next() {
+		var revision entity.CommentRevision
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Body, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dependencyGraphLockKey - advisory-блокировка графа зависимостей задач
+const dependencyGraphLockKey = 1013
+
+type DependencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDependencyRepository(db *pgxpool.Pool) *DependencyRepository {
+	return &DependencyRepository{
+		db: db,
+	}
+}
+
+// Add - blockerID начинает блокировать blockedID, повторное добавление ничего не меняет
+func (r *DependencyRepository) Add(ctx context.Context, blockerID, blockedID int) error {
+	query := `
+	INSERT INTO "task_dependency" (blocker_id, blocked_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, blockerID, blockedID)
+	return err
+}
+
+// Remove - удаление зависимости
+func (r *DependencyRepository) Remove(ctx context.Context, blockerID, blockedID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "task_dependency" WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	return err
+}
+
+// ListBlockers - задачи, которые блокируют taskID
+func (r *DependencyRepository) ListBlockers(ctx context.Context, taskID int) ([]entity.Task, error) {
+	query := `
+	SELECT ` + prefixedTaskColumns("t") + `
+	FROM "task_dependency" d
+	JOIN task t ON t.id = d.blocker_id
+	WHERE d.blocked_id = $1
+	ORDER BY t.id
+	`
+	return r.listTasks(ctx, query, taskID)
+}
+
+// ListBlocked - задачи, которые блокирует taskID
+func (r *DependencyRepository) ListBlocked(ctx context.Context, taskID int) ([]entity.Task, error) {
+	query := `
+	SELECT ` + prefixedTaskColumns("t") + `
+	FROM "task_dependency" d
+	JOIN task t ON t.id = d.blocked_id
+	WHERE d.blocker_id = $1
+	ORDER BY t.id
+	`
+	return r.listTasks(ctx, query, taskID)
+}
+
+// ListOpenBlockerIDs - ID блокирующих задач, которые еще не завершены и не отменены
+func (r *DependencyRepository) ListOpenBlockerIDs(ctx context.Context, taskID int) ([]int, error) {
+	query := `
+	SELECT t.id
+	FROM "task_dependency" d
+	JOIN task t ON t.id = d.blocker_id
+	WHERE d.blocked_id = $1 AND t.status NOT IN ('completed', 'cancelled')
+	ORDER BY t.id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Reaches - блокирует ли fromID задачу toID напрямую или через цепочку зависимостей
+func (r *DependencyRepository) Reaches(ctx context.Context, fromID, toID int) (bool, error) {
+	query := `
+	WITH RECURSIVE reach AS (
+		SELECT blocked_id AS id FROM task_dependency WHERE blocker_id = $1
+		UNION
+		SELECT d.blocked_id
+		FROM task_dependency d
+		JOIN reach r ON d.blocker_id = r.id
+	)
+	SELECT EXISTS (SELECT 1 FROM reach WHERE id = $2)
+	`
+
+	var reaches bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, fromID, toID).Scan(&reaches)
+	return reaches, err
+}
+
+// LockGraph блокирует граф зависимостей до конца транзакции,
+// чтобы два встречных добавления не создали цикл
+func (r *DependencyRepository) LockGraph(ctx context.Context) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, dependencyGraphLockKey)
+	return err
+}
+
+func (r *DependencyRepository) listTasks(ctx context.Context, query string, args ...interface{}) ([]entity.Task, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []entity.Task{}
+	for rows.Next() {
+		var task entity.Task
+		if err := rows.Scan(taskFields(&task)...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
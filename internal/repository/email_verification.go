@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EmailVerificationToken - использованный токен подтверждения: чей и какой адрес подтверждает
+type EmailVerificationToken struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+type EmailVerificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailVerificationRepository(db *pgxpool.Pool) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		db: db,
+	}
+}
+
+// Create - сохраняем хеш токена подтверждения email
+func (r *EmailVerificationRepository) Create(ctx context.Context, userID int, email, tokenHash string, expiresAt time.Time) error {
+	query := `
+	INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, email, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Consume - помечаем токен использованным одним UPDATE, как и токен сброса пароля.
+// nil - токена нет, он истек или уже использован
+func (r *EmailVerificationRepository) Consume(ctx context.Context, tokenHash string) (*EmailVerificationToken, error) {
+	query := `
+	UPDATE email_verification_tokens
+	SET used_at = NOW()
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING user_id, email
+	`
+
+	var token EmailVerificationToken
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(&token.UserID, &token.Email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// InvalidateForUser - гасим все неиспользованные токены пользователя
+func (r *EmailVerificationRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	query := `
+	UPDATE email_verification_tokens
+	SET used_at = NOW()
+	WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CountSince - сколько токенов выдано пользователю начиная с since, для лимита повторных отправок
+func (r *EmailVerificationRepository) CountSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM email_verification_tokens
+	WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -11,9 +11,14 @@ import (
 type ITaskRepository interface {
 	Create(ctx context.Context, task *entity.CreateTaskRequest) (*entity.Task, error)
 	GetByTaskId(ctx context.Context, taskId int) (*entity.Task, error)
-	Update(ctx context.Context, id int, updates map[string]interface{}) (*entity.Task, error)
-	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, ownerID int, status string) ([]entity.Task, error)
+	Update(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error)
+	Delete(ctx context.Context, id int, version int) error
+	List(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error)
+	GetSubtree(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error)
+	ListSubtreeStatuses(ctx context.Context, rootID int) ([]entity.TaskStatusNode, error)
+	GetAncestorIDs(ctx context.Context, taskID int) ([]int, error)
+	LockHierarchy(ctx context.Context, ownerID int, projectID *int) error
+	ArchiveByProject(ctx context.Context, projectID int) ([]entity.Task, error)
 }
 
 // IUserRepository - интерфейс для UserRepository
@@ -24,6 +29,7 @@ type IUserRepository interface {
 	CreateWithAuth(ctx context.Context, name, email, passwordHash string) (*entity.User, error)
 	Update(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error)
 	List(ctx context.Context) ([]entity.User, error)
+	ListPage(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserPage, error)
 	Delete(ctx context.Context, id int) error
 }
 
@@ -37,15 +43,137 @@ type IAvatarRepository interface {
 // ITaskAuditRepository - интерфейс для TaskAuditRepository
 type ITaskAuditRepository interface {
 	Create(ctx context.Context, audit *entity.TaskAudit) error
-	GetByTaskAuditId(ctx context.Context, taskAuditId int) ([]entity.TaskAudit, error)
+	ListByTask(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error)
+	GetTaskOwnerID(ctx context.Context, taskID int) (int, error)
 }
 
 // IRefreshTokenRepository - интерфейс для RefreshTokenRepository
 type IRefreshTokenRepository interface {
-	Save(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	Save(ctx context.Context, token *RefreshToken) error
 	GetByUserID(ctx context.Context, userID int) ([]RefreshToken, error)
 	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
 	RevokeAll(ctx context.Context, userID int) error
-	Revoke(ctx context.Context, tokenHash string) error
+	Revoke(ctx context.Context, tokenHash string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
 	CleanupExpired(ctx context.Context) error
 }
+
+// IPasswordResetRepository - интерфейс для PasswordResetRepository
+type IPasswordResetRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	Consume(ctx context.Context, tokenHash string) (int, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+	RecordRequest(ctx context.Context, key string) error
+	CountRequestsSince(ctx context.Context, key string, since time.Time) (int, error)
+}
+
+// IEmailVerificationRepository - интерфейс для EmailVerificationRepository
+type IEmailVerificationRepository interface {
+	Create(ctx context.Context, userID int, email, tokenHash string, expiresAt time.Time) error
+	Consume(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+	CountSince(ctx context.Context, userID int, since time.Time) (int, error)
+}
+
+// ILoginAttemptRepository - интерфейс для LoginAttemptRepository
+type ILoginAttemptRepository interface {
+	Reserve(ctx context.Context, key string, windowStart time.Time) (*LoginAttempts, error)
+	Release(ctx context.Context, key string, blockedUntil *time.Time) error
+	Block(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// IAuthAuditRepository - интерфейс для AuthAuditRepository
+type IAuthAuditRepository interface {
+	Create(ctx context.Context, event *entity.AuthEvent) error
+}
+
+// IRoleRepository - интерфейс для RoleRepository
+type IRoleRepository interface {
+	GetByUserID(ctx context.Context, userID int) ([]entity.Role, error)
+	SetForUser(ctx context.Context, userID int, roles []entity.Role) error
+	GetPermissions(ctx context.Context) (map[entity.Role][]entity.Permission, error)
+}
+
+// ITransactor - интерфейс для Transactor
+type ITransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// IAuditOutboxRepository - интерфейс для AuditOutboxRepository
+type IAuditOutboxRepository interface {
+	Add(ctx context.Context, message *entity.AuditMessage) error
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id int64, lastError string) error
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ILabelRepository - интерфейс для LabelRepository
+type ILabelRepository interface {
+	Create(ctx context.Context, ownerID int, req *entity.CreateLabelRequest) (*entity.Label, error)
+	GetByID(ctx context.Context, id int) (*entity.Label, error)
+	ListByOwner(ctx context.Context, ownerID int) ([]entity.Label, error)
+	Update(ctx context.Context, id int, req *entity.UpdateLabelRequest) (*entity.Label, error)
+	Delete(ctx context.Context, id int) error
+	Attach(ctx context.Context, taskID, labelID int) error
+	Detach(ctx context.Context, taskID, labelID int) error
+	ListByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]entity.Label, error)
+	ListTaskIDsByLabel(ctx context.Context, labelID int) ([]int, error)
+}
+
+// IDependencyRepository - интерфейс для DependencyRepository
+type IDependencyRepository interface {
+	Add(ctx context.Context, blockerID, blockedID int) error
+	Remove(ctx context.Context, blockerID, blockedID int) error
+	ListBlockers(ctx context.Context, taskID int) ([]entity.Task, error)
+	ListBlocked(ctx context.Context, taskID int) ([]entity.Task, error)
+	ListOpenBlockerIDs(ctx context.Context, taskID int) ([]int, error)
+	Reaches(ctx context.Context, fromID, toID int) (bool, error)
+	LockGraph(ctx context.Context) error
+}
+
+// ICollaboratorRepository - интерфейс для CollaboratorRepository
+type ICollaboratorRepository interface {
+	GetAccess(ctx context.Context, taskID, userID int) (entity.TaskAccess, error)
+	AddAssignee(ctx context.Context, taskID, userID int) error
+	RemoveAssignee(ctx context.Context, taskID, userID int) error
+	ListAssigneeIDs(ctx context.Context, taskIDs []int) (map[int][]int, error)
+	SetShare(ctx context.Context, taskID, userID int, role entity.ShareRole) (*entity.TaskShare, error)
+	RemoveShare(ctx context.Context, taskID, userID int) error
+	ListShares(ctx context.Context, taskID int) ([]entity.TaskShare, error)
+}
+
+// IProjectRepository - интерфейс для ProjectRepository
+type IProjectRepository interface {
+	Create(ctx context.Context, ownerID int, req *entity.CreateProjectRequest) (*entity.Project, error)
+	GetByID(ctx context.Context, id int) (*entity.Project, error)
+	ListByMember(ctx context.Context, userID int) ([]entity.Project, error)
+	Update(ctx context.Context, id int, req *entity.UpdateProjectRequest) (*entity.Project, error)
+	Delete(ctx context.Context, id int) error
+	GetMemberRole(ctx context.Context, projectID, userID int) (entity.ProjectRole, error)
+	SetMember(ctx context.Context, projectID, userID int, role entity.ProjectRole) (*entity.ProjectMember, error)
+	RemoveMember(ctx context.Context, projectID, userID int) error
+	ListMembers(ctx context.Context, projectID int) ([]entity.ProjectMember, error)
+}
+
+// IAttachmentRepository - интерфейс для AttachmentRepository
+type IAttachmentRepository interface {
+	Create(ctx context.Context, attachment *entity.TaskAttachment) (*entity.TaskAttachment, error)
+	GetByID(ctx context.Context, id int) (*entity.TaskAttachment, error)
+	ListByTasks(ctx context.Context, taskIDs []int) ([]entity.TaskAttachment, error)
+	Delete(ctx context.Context, id int) error
+	LockUsage(ctx context.Context, taskID int, userID int) error
+	GetUsage(ctx context.Context, taskID int, userID int) (*entity.AttachmentUsage, error)
+}
+
+// ICommentRepository - интерфейс для CommentRepository
+type ICommentRepository interface {
+	Create(ctx context.Context, authorID int, req *entity.CreateCommentRequest) (*entity.TaskComment, error)
+	GetByID(ctx context.Context, id int) (*entity.TaskComment, error)
+	Update(ctx context.Context, id int, body string) (*entity.TaskComment, error)
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, req *entity.ListCommentsRequest) (*entity.CommentPage, error)
+	ListRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error)
+}
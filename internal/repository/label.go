@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+type LabelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLabelRepository(db *pgxpool.Pool) *LabelRepository {
+	return &LabelRepository{
+		db: db,
+	}
+}
+
+// Create - создание метки, ErrLabelExists если имя уже занято
+func (r *LabelRepository) Create(ctx context.Context, ownerID int, req *entity.CreateLabelRequest) (*entity.Label, error) {
+	query := `
+	INSERT INTO "label" (owner_id, name, color)
+	VALUES ($1, $2, $3)
+	RETURNING id, owner_id, name, color, created_at
+	`
+
+	var label entity.Label
+	err := conn(ctx, r.db).QueryRow(ctx, query, ownerID, req.Name, req.Color).Scan(
+		&label.ID,
+		&label.OwnerID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, entity.ErrLabelExists
+		}
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func (r *LabelRepository) GetByID(ctx context.Context, id int) (*entity.Label, error) {
+	query := `
+	SELECT id, owner_id, name, color, created_at
+	FROM "label"
+	WHERE id = $1
+	`
+
+	var label entity.Label
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&label.ID,
+		&label.OwnerID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+// ListByOwner - все метки пользователя по имени
+func (r *LabelRepository) ListByOwner(ctx context.Context, ownerID int) ([]entity.Label, error) {
+	query := `
+	SELECT id, owner_id, name, color, created_at
+	FROM "label"
+	WHERE owner_id = $1
+	ORDER BY LOWER(name)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+// Update - переименование и смена цвета, ErrLabelExists если имя уже занято
+func (r *LabelRepository) Update(ctx context.Context, id int, req *entity.UpdateLabelRequest) (*entity.Label, error) {
+	query := `
+	UPDATE "label"
+	SET name = COALESCE($2, name), color = COALESCE($3, color)
+	WHERE id = $1
+	RETURNING id, owner_id, name, color, created_at
+	`
+
+	var label entity.Label
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, req.Name, req.Color).Scan(
+		&label.ID,
+		&label.OwnerID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrLabelNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, entity.ErrLabelExists
+		}
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+// Delete - удаление метки, связи с задачами удаляются каскадно
+func (r *LabelRepository) Delete(ctx context.Context, id int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "label" WHERE id = $1`, id)
+	return err
+}
+
+// Attach - привязка метки к задаче, повторная привязка ничего не меняет
+func (r *LabelRepository) Attach(ctx context.Context, taskID, labelID int) error {
+	query := `
+	INSERT INTO "task_label" (task_id, label_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, taskID, labelID)
+	return err
+}
+
+// Detach - отвязка метки от задачи
+func (r *LabelRepository) Detach(ctx context.Context, taskID, labelID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "task_label" WHERE task_id = $1 AND label_id = $2`, taskID, labelID)
+	return err
+}
+
+// ListByTaskIDs - метки задач, сгруппированные по ID задачи
+func (r *LabelRepository) ListByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]entity.Label, error) {
+	result := make(map[int][]entity.Label, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+	SELECT tl.task_id, l.id, l.owner_id, l.name, l.color, l.created_at
+	FROM "task_label" tl
+	JOIN "label" l ON l.id = tl.label_id
+	WHERE tl.task_id = ANY($1)
+	ORDER BY tl.task_id, LOWER(l.name)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID int
+			label  entity.Label
+		)
+		if err := rows.Scan(&taskID, &label.ID, &label.OwnerID, &label.Name, &label.Color, &label.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[taskID] = append(result[taskID], label)
+	}
+
+	return result, rows.Err()
+}
+
+// ListTaskIDsByLabel - задачи, к которым привязана метка
+func (r *LabelRepository) ListTaskIDsByLabel(ctx context.Context, labelID int) ([]int, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT task_id FROM "task_label" WHERE label_id = $1 ORDER BY task_id`, labelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taskIDs []int
+	for rows.Next() {
+		var taskID int
+		if err := rows.Scan(&taskID); err != nil {
+			return nil, err
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	return taskIDs, rows.Err()
+}
+
+func scanLabels(rows pgx.Rows) ([]entity.Label, error) {
+	var labels []entity.Label
+	for rows.Next() {
+		var label entity.Label
+		if err := rows.Scan(&label.ID, &label.OwnerID, &label.Name, &label.Color, &label.CreatedAt); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// labelFilter строит условие ListTasks по меткам: any - хотя бы одна, all - все сразу
+func labelFilter(labelIDs []int, match entity.LabelMatch, argIndex int) (string, []interface{}) {
+	if len(labelIDs) == 0 {
+		return "", nil
+	}
+
+	if match == entity.LabelMatchAll {
+		cond := `id IN (SELECT task_id FROM task_label WHERE label_id = ANY($` + strconv.Itoa(argIndex) + `)
+			GROUP BY task_id HAVING COUNT(*) = $` + strconv.Itoa(argIndex+1) + `)`
+		return cond, []interface{}{labelIDs, len(labelIDs)}
+	}
+
+	cond := `id IN (SELECT task_id FROM task_label WHERE label_id = ANY($` + strconv.Itoa(argIndex) + `))`
+	return cond, []interface{}{labelIDs}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LoginAttempts - неудачные попытки входа по ключу (аккаунт или IP)
+type LoginAttempts struct {
+	Key           string     `json:"key"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	BlockedUntil  *time.Time `json:"blocked_until"`
+}
+
+type LoginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// Reserve - заранее учитываем попытку входа как неудачную и возвращаем счетчик. Если вход
+// по ключу закрыт (blocked_until в будущем), счетчик не меняется и возвращается как есть.
+// Если последняя неудача была раньше windowStart, счет начинается заново. Upsert блокирует
+// строку до конца транзакции: параллельная попытка по тому же ключу ждет и видит ее итог
+func (r *LoginAttemptRepository) Reserve(ctx context.Context, key string, windowStart time.Time) (*LoginAttempts, error) {
+	query := `
+	INSERT INTO login_attempts (key, failures, last_failure_at)
+	VALUES ($1, 1, NOW())
+	ON CONFLICT (key) DO UPDATE
+	SET failures = CASE
+	        WHEN login_attempts.blocked_until > NOW() THEN login_attempts.failures
+	        WHEN login_attempts.last_failure_at < $2 THEN 1
+	        ELSE login_attempts.failures + 1
+	    END,
+	    last_failure_at = CASE
+	        WHEN login_attempts.blocked_until > NOW() THEN login_attempts.last_failure_at
+	        ELSE NOW()
+	    END
+	RETURNING key, failures, last_failure_at, blocked_until
+	`
+
+	var attempts LoginAttempts
+	err := conn(ctx, r.db).QueryRow(ctx, query, key, windowStart).Scan(
+		&attempts.Key,
+		&attempts.Failures,
+		&attempts.LastFailureAt,
+		&attempts.BlockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempts, nil
+}
+
+// Release - снимаем с ключа попытку, учтенную через Reserve, если она оказалась успешной.
+// Блокировка снимается, только если ее выставила эта попытка (blockedUntil) и ее никто
+// не перезаписал
+func (r *LoginAttemptRepository) Release(ctx context.Context, key string, blockedUntil *time.Time) error {
+	query := `
+	UPDATE login_attempts
+	SET failures = GREATEST(failures - 1, 0),
+	    blocked_until = CASE WHEN blocked_until = $2 THEN NULL ELSE blocked_until END
+	WHERE key = $1
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, key, blockedUntil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Block - закрываем вход по ключу до until
+func (r *LoginAttemptRepository) Block(ctx context.Context, key string, until time.Time) error {
+	query := `
+	UPDATE login_attempts
+	SET blocked_until = $2
+	WHERE key = $1
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, key, until)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reset - сбрасываем счетчик после успешного входа
+func (r *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	query := `
+	DELETE FROM login_attempts
+	WHERE key = $1
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sortColumn - колонка, по которой возможна keyset-пагинация
+type sortColumn struct {
+	column string // колонка в SQL
+	cast   string // тип значения курсора в SQL
+}
+
+// pageCursor - содержимое непрозрачного page_token
+type pageCursor struct {
+	SortBy  string `json:"s"`
+	SortDir string `json:"d"`
+	Scope   string `json:"f"`  // фильтры, для которых выдан курсор
+	Value   string `json:"v"`  // значение колонки сортировки у последней записи
+	ID      int    `json:"id"` // id последней записи (tie-breaker)
+}
+
+// encodeCursor кодирует курсор в page_token
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor декодирует page_token и проверяет, что он выдан для той же сортировки и фильтров
+func decodeCursor(page entity.PageRequest, scope string) (*pageCursor, error) {
+	if page.PageToken == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(page.PageToken)
+	if err != nil {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	if cursor.SortBy != page.SortBy || cursor.SortDir != string(page.SortDir) || cursor.Scope != scope {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	return &cursor, nil
+}
+
+// keysetCondition строит условие "после курсора" для WHERE
+func keysetCondition(col sortColumn, dir entity.SortDirection, cursor *pageCursor, argIndex int) (string, []interface{}) {
+	if cursor == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if dir == entity.SortDesc {
+		op = "<"
+	}
+
+	if col.column == "id" {
+		return fmt.Sprintf("id %s $%d", op, argIndex), []interface{}{cursor.ID}
+	}
+
+	cond := fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)", col.column, op, argIndex, col.cast, argIndex+1)
+	return cond, []interface{}{cursor.Value, cursor.ID}
+}
+
+// keysetOrder строит ORDER BY с id в качестве tie-breaker
+func keysetOrder(col sortColumn, dir entity.SortDirection) string {
+	direction := "ASC"
+	if dir == entity.SortDesc {
+		direction = "DESC"
+	}
+
+	if col.column == "id" {
+		return " ORDER BY id " + direction
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", col.column, direction, direction)
+}
+
+// countRows считает записи для fromWhere ("FROM ... WHERE ...") точно или по оценке планировщика
+func countRows(ctx context.Context, db *pgxpool.Pool, fromWhere string, args []interface{}, mode entity.TotalMode) (int64, error) {
+	switch mode {
+	case entity.TotalExact:
+		var total int64
+		err := db.QueryRow(ctx, "SELECT COUNT(*) "+fromWhere, args...).Scan(&total)
+		return total, err
+
+	case entity.TotalEstimated:
+		// Оценка из плана запроса: дешево на больших таблицах, но может расходиться с реальностью
+		var plan []struct {
+			Plan struct {
+				PlanRows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		var raw []byte
+		if err := db.QueryRow(ctx, "EXPLAIN (FORMAT JSON) SELECT 1 "+fromWhere, args...).Scan(&raw); err != nil {
+			return 0, err
+		}
+		if err := json.Unmarshal(raw, &plan); err != nil {
+			return 0, err
+		}
+		if len(plan) == 0 {
+			return 0, nil
+		}
+		return int64(plan[0].Plan.PlanRows), nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	page := entity.PageRequest{SortBy: entity.TaskSortCreatedAt, SortDir: entity.SortDesc}
+	token := encodeCursor(pageCursor{
+		SortBy:  page.SortBy,
+		SortDir: string(page.SortDir),
+		Scope:   "owner=1;status=",
+		Value:   "2025-01-01T00:00:00.123456Z",
+		ID:      42,
+	})
+
+	page.PageToken = token
+	cursor, err := decodeCursor(page, "owner=1;status=")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cursor.ID != 42 || cursor.Value != "2025-01-01T00:00:00.123456Z" {
+		t.Errorf("Unexpected cursor %+v", cursor)
+	}
+}
+
+func TestCursorRejectsOtherQuery(t *testing.T) {
+	token := encodeCursor(pageCursor{SortBy: entity.TaskSortTitle, SortDir: string(entity.SortAsc), Scope: "owner=1;status=", ID: 1})
+
+	tests := []struct {
+		name  string
+		page  entity.PageRequest
+		scope string
+	}{
+		{"other sort field", entity.PageRequest{PageToken: token, SortBy: entity.TaskSortCreatedAt, SortDir: entity.SortAsc}, "owner=1;status="},
+		{"other direction", entity.PageRequest{PageToken: token, SortBy: entity.TaskSortTitle, SortDir: entity.SortDesc}, "owner=1;status="},
+		{"other owner", entity.PageRequest{PageToken: token, SortBy: entity.TaskSortTitle, SortDir: entity.SortAsc}, "owner=2;status="},
+		{"garbage", entity.PageRequest{PageToken: "!!!", SortBy: entity.TaskSortTitle, SortDir: entity.SortAsc}, "owner=1;status="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.page, tt.scope); err != entity.ErrInvalidPageToken {
+				t.Errorf("Expected ErrInvalidPageToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	cursor := &pageCursor{Value: "abc", ID: 5}
+
+	cond, args := keysetCondition(taskSortColumns[entity.TaskSortTitle], entity.SortDesc, cursor, 3)
+	if cond != "(title, id) < ($3::text, $4)" {
+		t.Errorf("Unexpected condition %q", cond)
+	}
+	if len(args) != 2 || args[0] != "abc" || args[1] != 5 {
+		t.Errorf("Unexpected args %v", args)
+	}
+
+	cond, args = keysetCondition(taskSortColumns[entity.TaskSortID], entity.SortAsc, cursor, 1)
+	if cond != "id > $1" || len(args) != 1 {
+		t.Errorf("Unexpected condition %q %v", cond, args)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+// Create - сохраняем хеш токена сброса пароля
+func (r *PasswordResetRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `
+	INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+	VALUES ($1, $2, $3)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Consume - помечаем токен использованным и возвращаем его пользователя. 0 - токена нет,
+// он истек или уже использован. Проверка и пометка - один UPDATE, поэтому токен срабатывает
+// ровно один раз даже при параллельных запросах
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (int, error) {
+	query := `
+	UPDATE password_reset_tokens
+	SET used_at = NOW()
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING user_id
+	`
+
+	var userID int
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// InvalidateForUser - гасим все неиспользованные токены пользователя
+func (r *PasswordResetRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	query := `
+	UPDATE password_reset_tokens
+	SET used_at = NOW()
+	WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordRequest - запоминаем запрос сброса по ключу лимита. Записи старше суток
+// для этого ключа больше не нужны лимиту и удаляются тем же запросом
+func (r *PasswordResetRepository) RecordRequest(ctx context.Context, key string) error {
+	query := `
+	WITH expired AS (
+		DELETE FROM password_reset_requests
+		WHERE key = $1 AND created_at < NOW() - INTERVAL '1 day'
+	)
+	INSERT INTO password_reset_requests (key)
+	VALUES ($1)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CountRequestsSince - сколько запросов сброса по ключу было начиная с since
+func (r *PasswordResetRepository) CountRequestsSince(ctx context.Context, key string, since time.Time) (int, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM password_reset_requests
+	WHERE key = $1 AND created_at >= $2
+	`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRow(ctx, query, key, since).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// projectColumns - колонки проекта в порядке projectFields
+const projectColumns = `id, name, description, owner_id, created_at, updated_at`
+
+// projectFields возвращает указатели на поля проекта для Scan в порядке projectColumns
+func projectFields(project *entity.Project) []interface{} {
+	return []interface{}{
+		&project.ID,
+		&project.Name,
+		&project.Description,
+		&project.OwnerID,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	}
+}
+
+// ProjectRepository - проекты и их участники. Удаленные проекты не возвращаются
+type ProjectRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProjectRepository(db *pgxpool.Pool) *ProjectRepository {
+	return &ProjectRepository{
+		db: db,
+	}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, ownerID int, req *entity.CreateProjectRequest) (*entity.Project, error) {
+	query := `
+	INSERT INTO "project" (name, description, owner_id)
+	VALUES ($1, $2, $3)
+	RETURNING ` + projectColumns + `
+	`
+
+	var project entity.Project
+	if err := conn(ctx, r.db).QueryRow(ctx, query, req.Name, req.Description, ownerID).Scan(projectFields(&project)...); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *ProjectRepository) GetByID(ctx context.Context, id int) (*entity.Project, error) {
+	query := `
+	SELECT ` + projectColumns + `
+	FROM "project"
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var project entity.Project
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(projectFields(&project)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// ListByMember - проекты, где пользователь участник, с его ролью
+func (r *ProjectRepository) ListByMember(ctx context.Context, userID int) ([]entity.Project, error) {
+	query := `
+	SELECT p.id, p.name, p.description, p.owner_id, p.created_at, p.updated_at, m.role
+	FROM "project_member" m
+	JOIN "project" p ON p.id = m.project_id
+	WHERE m.user_id = $1 AND p.deleted_at IS NULL
+	ORDER BY LOWER(p.name), p.id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []entity.Project{}
+	for rows.Next() {
+		var project entity.Project
+		if err := rows.Scan(append(projectFields(&project), &project.Role)...); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// Update - переименование и смена описания, ErrProjectNotFound если проекта нет
+func (r *ProjectRepository) Update(ctx context.Context, id int, req *entity.UpdateProjectRequest) (*entity.Project, error) {
+	query := `
+	UPDATE "project"
+	SET name = COALESCE($2, name), description = COALESCE($3, description), updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL
+	RETURNING ` + projectColumns + `
+	`
+
+	var project entity.Project
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, req.Name, req.Description).Scan(projectFields(&project)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// Delete - пометка проекта удаленным. Задачи проекта остаются, их архивирует usecase
+func (r *ProjectRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE "project" SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrProjectNotFound
+	}
+	return nil
+}
+
+// GetMemberRole - роль пользователя в проекте, пустая если он не участник или проект удален
+func (r *ProjectRepository) GetMemberRole(ctx context.Context, projectID, userID int) (entity.ProjectRole, error) {
+	query := `
+	SELECT m.role
+	FROM "project_member" m
+	JOIN "project" p ON p.id = m.project_id
+	WHERE m.project_id = $1 AND m.user_id = $2 AND p.deleted_at IS NULL
+	`
+
+	var role entity.ProjectRole
+	err := conn(ctx, r.db).QueryRow(ctx, query, projectID, userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// SetMember - добавление участника или смена его роли
+func (r *ProjectRepository) SetMember(ctx context.Context, projectID, userID int, role entity.ProjectRole) (*entity.ProjectMember, error) {
+	query := `
+	INSERT INTO "project_member" (project_id, user_id, role)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	RETURNING project_id, user_id, role, created_at
+	`
+
+	var member entity.ProjectMember
+	err := conn(ctx, r.db).QueryRow(ctx, query, projectID, userID, role).Scan(
+		&member.ProjectID,
+		&member.UserID,
+		&member.Role,
+		&member.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// RemoveMember - исключение участника
+func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM "project_member" WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	return err
+}
+
+// ListMembers - участники проекта в порядке добавления
+func (r *ProjectRepository) ListMembers(ctx context.Context, projectID int) ([]entity.ProjectMember, error) {
+	query := `
+	SELECT project_id, user_id, role, created_at
+	FROM "project_member"
+	WHERE project_id = $1
+	ORDER BY created_at, user_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []entity.ProjectMember{}
+	for rows.Next() {
+		var member entity.ProjectMember
+		if err := rows.Scan(&member.ProjectID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
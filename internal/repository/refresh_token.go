@@ -15,6 +15,29 @@ type RefreshToken struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	Revoked   bool      `json:"revoked"`
+	// FamilyID - сессия: все токены, выданные одним входом и его ротациями
+	FamilyID         string    `json:"family_id"`
+	SessionStartedAt time.Time `json:"session_started_at"`
+	UserAgent        string    `json:"user_agent"`
+	IPAddress        string    `json:"ip_address"`
+}
+
+const refreshTokenColumns = `id, user_id, token_hash, expires_at, created_at, revoked, family_id, session_started_at, user_agent, ip_address`
+
+// refreshTokenFields возвращает указатели на поля токена для Scan в порядке refreshTokenColumns
+func refreshTokenFields(token *RefreshToken) []interface{} {
+	return []interface{}{
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.Revoked,
+		&token.FamilyID,
+		&token.SessionStartedAt,
+		&token.UserAgent,
+		&token.IPAddress,
+	}
 }
 
 type RefreshTokenRepository struct {
@@ -28,13 +51,21 @@ func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
 }
 
 // Save - сохраняем refresh token
-func (r *RefreshTokenRepository) Save(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+func (r *RefreshTokenRepository) Save(ctx context.Context, token *RefreshToken) error {
 	query := `
-	INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-	VALUES ($1, $2, $3)
+	INSERT INTO refresh_tokens (user_id, token_hash, expires_at, family_id, session_started_at, user_agent, ip_address)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := r.db.Exec(ctx, query, userID, tokenHash, expiresAt)
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.FamilyID,
+		token.SessionStartedAt,
+		token.UserAgent,
+		token.IPAddress,
+	)
 	if err != nil {
 		return err
 	}
@@ -42,15 +73,16 @@ func (r *RefreshTokenRepository) Save(ctx context.Context, userID int, tokenHash
 	return nil
 }
 
-// GetByUserID - получаем невозвращенные токены пользователя
+// GetByUserID - получаем невозвращенные токены пользователя, новые первыми
 func (r *RefreshTokenRepository) GetByUserID(ctx context.Context, userID int) ([]RefreshToken, error) {
 	query := `
-	SELECT id, user_id, token_hash, expires_at, created_at, revoked
+	SELECT ` + refreshTokenColumns + `
 	FROM refresh_tokens
 	WHERE user_id = $1 AND revoked = false AND expires_at > NOW()
+	ORDER BY created_at DESC, id DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -59,15 +91,7 @@ func (r *RefreshTokenRepository) GetByUserID(ctx context.Context, userID int) ([
 	var tokens []RefreshToken
 	for rows.Next() {
 		var token RefreshToken
-		err := rows.Scan(
-			&token.ID,
-			&token.UserID,
-			&token.TokenHash,
-			&token.ExpiresAt,
-			&token.CreatedAt,
-			&token.Revoked,
-		)
-		if err != nil {
+		if err := rows.Scan(refreshTokenFields(&token)...); err != nil {
 			return nil, err
 		}
 		tokens = append(tokens, token)
@@ -84,7 +108,7 @@ func (r *RefreshTokenRepository) RevokeAll(ctx context.Context, userID int) erro
 	WHERE user_id = $1
 	`
 
-	_, err := r.db.Exec(ctx, query, userID)
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID)
 	if err != nil {
 		return err
 	}
@@ -92,15 +116,32 @@ func (r *RefreshTokenRepository) RevokeAll(ctx context.Context, userID int) erro
 	return nil
 }
 
-// Revoke - откатываем конкретный токен
-func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenHash string) error {
+// Revoke - откатываем конкретный токен. false - токен уже был отозван (или его нет):
+// так две параллельные ротации одного токена не пройдут обе
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenHash string) (bool, error) {
+	query := `
+	UPDATE refresh_tokens
+	SET revoked = true
+	WHERE token_hash = $1 AND revoked = false
+	`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeFamily - откатываем все токены сессии
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
 	query := `
 	UPDATE refresh_tokens
 	SET revoked = true
-	WHERE token_hash = $1
+	WHERE family_id = $1 AND revoked = false
 	`
 
-	_, err := r.db.Exec(ctx, query, tokenHash)
+	_, err := conn(ctx, r.db).Exec(ctx, query, familyID)
 	if err != nil {
 		return err
 	}
@@ -108,23 +149,19 @@ func (r *RefreshTokenRepository) Revoke(ctx context.Context, tokenHash string) e
 	return nil
 }
 
-// GetByHash - получаем токен по хешу
+// GetByHash - получаем неистекший токен по хешу, в том числе отозванный:
+// повторное предъявление отозванного токена нужно распознать
 func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
 	query := `
-	SELECT id, user_id, token_hash, expires_at, created_at, revoked
+	SELECT ` + refreshTokenColumns + `
 	FROM refresh_tokens
-	WHERE token_hash = $1 AND revoked = false AND expires_at > NOW()
+	WHERE token_hash = $1 AND expires_at > NOW()
+	ORDER BY id DESC
+	LIMIT 1
 	`
 
 	var token RefreshToken
-	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
-		&token.ID,
-		&token.UserID,
-		&token.TokenHash,
-		&token.ExpiresAt,
-		&token.CreatedAt,
-		&token.Revoked,
-	)
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash).Scan(refreshTokenFields(&token)...)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -143,7 +180,7 @@ func (r *RefreshTokenRepository) CleanupExpired(ctx context.Context) error {
 	WHERE expires_at < NOW()
 	`
 
-	_, err := r.db.Exec(ctx, query)
+	_, err := conn(ctx, r.db).Exec(ctx, query)
 	if err != nil {
 		return err
 	}
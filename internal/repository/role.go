@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRoleRepository(db *pgxpool.Pool) *RoleRepository {
+	return &RoleRepository{
+		db: db,
+	}
+}
+
+// GetByUserID - получаем роли пользователя
+func (r *RoleRepository) GetByUserID(ctx context.Context, userID int) ([]entity.Role, error) {
+	query := `
+	SELECT role
+	FROM "user_role"
+	WHERE user_id = $1
+	ORDER BY role
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []entity.Role
+	for rows.Next() {
+		var role entity.Role
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// SetForUser - заменяем роли пользователя в одной транзакции
+func (r *RoleRepository) SetForUser(ctx context.Context, userID int, roles []entity.Role) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM "user_role" WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		_, err := tx.Exec(ctx, `INSERT INTO "user_role" (user_id, role) VALUES ($1, $2)`, userID, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPermissions - получаем права всех ролей
+func (r *RoleRepository) GetPermissions(ctx context.Context) (map[entity.Role][]entity.Permission, error) {
+	query := `
+	SELECT role, permission
+	FROM "role_permission"
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make(map[entity.Role][]entity.Permission)
+	for rows.Next() {
+		var role entity.Role
+		var permission entity.Permission
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, err
+		}
+		permissions[role] = append(permissions[role], permission)
+	}
+
+	return permissions, rows.Err()
+}
@@ -2,13 +2,46 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// taskColumns - колонки задачи в порядке taskFields
+const taskColumns = `id, title, description, status, owner_id, created_at, updated_at, version, start_at, due_at, priority, completed_at, cancelled_at, parent_id, project_id, archived_at`
+
+// prefixedTaskColumns - taskColumns с алиасом таблицы для запросов с JOIN
+func prefixedTaskColumns(alias string) string {
+	return alias + "." + strings.ReplaceAll(taskColumns, ", ", ", "+alias+".")
+}
+
+// taskFields возвращает указатели на поля задачи для Scan в порядке taskColumns
+func taskFields(task *entity.Task) []interface{} {
+	return []interface{}{
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.OwnerId,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Version,
+		&task.StartAt,
+		&task.DueAt,
+		&task.Priority,
+		&task.CompletedAt,
+		&task.CancelledAt,
+		&task.ParentID,
+		&task.ProjectID,
+		&task.ArchivedAt,
+	}
+}
+
 type TaskRepository struct {
 	db *pgxpool.Pool
 }
@@ -22,26 +55,35 @@ func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
 func (r *TaskRepository) Create(ctx context.Context, task *entity.CreateTaskRequest) (*entity.Task, error) {
 
 	query := `
-	INSERT INTO "task" (title, description, status, owner_id)
-	VALUES ($1, $2, $3, $4)
-	RETURNING id, title, description, status, owner_id, created_at, updated_at
+	INSERT INTO "task" (title, description, status, owner_id, start_at, due_at, priority, completed_at, cancelled_at, parent_id, project_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	RETURNING ` + taskColumns + `
 	`
 
+	// Задача может сразу создаваться завершенной или отмененной
+	var completedAt, cancelledAt *time.Time
+	now := time.Now()
+	switch task.Status {
+	case entity.StatusCompleted:
+		completedAt = &now
+	case entity.StatusCancelled:
+		cancelledAt = &now
+	}
+
 	var createdTask entity.Task
-	err := r.db.QueryRow(ctx, query,
+	err := conn(ctx, r.db).QueryRow(ctx, query,
 		task.Title,
 		task.Description,
 		task.Status,
 		task.OwnerId,
-	).Scan(
-		&createdTask.ID,
-		&createdTask.Title,
-		&createdTask.Description,
-		&createdTask.Status,
-		&createdTask.OwnerId,
-		&createdTask.CreatedAt,
-		&createdTask.UpdatedAt,
-	)
+		task.StartAt,
+		task.DueAt,
+		task.Priority,
+		completedAt,
+		cancelledAt,
+		task.ParentID,
+		task.ProjectID,
+	).Scan(taskFields(&createdTask)...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,21 +94,13 @@ func (r *TaskRepository) Create(ctx context.Context, task *entity.CreateTaskRequ
 func (r *TaskRepository) GetByTaskId(ctx context.Context, taskId int) (*entity.Task, error) {
 
 	query := `
-	SELECT id, title, description, status, owner_id, created_at, updated_at
+	SELECT ` + taskColumns + `
 	FROM "task"
 	WHERE id = $1
 	`
 	var task entity.Task
 
-	err := r.db.QueryRow(ctx, query, taskId).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.OwnerId,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	err := conn(ctx, r.db).QueryRow(ctx, query, taskId).Scan(taskFields(&task)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -77,8 +111,9 @@ func (r *TaskRepository) GetByTaskId(ctx context.Context, taskId int) (*entity.T
 	return &task, nil
 }
 
-// Update - обновление задачи
-func (r *TaskRepository) Update(ctx context.Context, id int, updates map[string]interface{}) (*entity.Task, error) {
+// Update - обновление задачи, если ее версия все еще равна version.
+// Иначе возвращает ErrVersionMismatch
+func (r *TaskRepository) Update(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
 	// Динамически строим SET часть запроса
 	setClause := ""
 	args := []interface{}{}
@@ -96,61 +131,149 @@ func (r *TaskRepository) Update(ctx context.Context, id int, updates map[string]
 		argIndex++
 	}
 
-	// Добавляем обновление updated_at
+	// Добавляем обновление updated_at и версии
 	if argIndex > 1 {
-		setClause += ", updated_at = CURRENT_TIMESTAMP"
+		setClause += ", updated_at = CURRENT_TIMESTAMP, version = version + 1"
 	}
 
 	query := `
         UPDATE task 
         SET ` + setClause + `
-        WHERE id = $` + strconv.Itoa(argIndex) + `
-        RETURNING id, title, description, status, owner_id, created_at, updated_at
+        WHERE id = $` + strconv.Itoa(argIndex) + ` AND version = $` + strconv.Itoa(argIndex+1) + `
+        RETURNING ` + taskColumns + `
     `
-	args = append(args, id)
+	args = append(args, id, version)
 
 	var task entity.Task
-	err := r.db.QueryRow(ctx, query, args...).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.OwnerId,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(taskFields(&task)...)
 
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Задачу успели изменить или удалить после чтения
+			return nil, entity.ErrVersionMismatch
+		}
 		return nil, err
 	}
 
 	return &task, nil
 }
 
-// Delete - удаление задачи
-func (r *TaskRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM task WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
-	return err
+// Delete - удаление задачи, если ее версия все еще равна version.
+// Иначе возвращает ErrVersionMismatch
+func (r *TaskRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `DELETE FROM task WHERE id = $1 AND version = $2`
+	tag, err := conn(ctx, r.db).Exec(ctx, query, id, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrVersionMismatch
+	}
+	return nil
 }
 
-// List - список задач с фильтрацией
-func (r *TaskRepository) List(ctx context.Context, ownerID int, status string) ([]entity.Task, error) {
-	query := `
-        SELECT id, title, description, status, owner_id, created_at, updated_at 
-        FROM task 
-        WHERE owner_id = $1
-    `
-	args := []interface{}{ownerID}
+// taskSortColumns - поля, по которым можно сортировать задачи
+var taskSortColumns = map[string]sortColumn{
+	entity.TaskSortCreatedAt: {column: "created_at", cast: "timestamptz"},
+	entity.TaskSortUpdatedAt: {column: "updated_at", cast: "timestamptz"},
+	entity.TaskSortTitle:     {column: "title", cast: "text"},
+	entity.TaskSortID:        {column: "id", cast: "integer"},
+}
+
+// List - страница задач с фильтрацией и keyset-пагинацией
+func (r *TaskRepository) List(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+	col, ok := taskSortColumns[req.Page.SortBy]
+	if !ok {
+		return nil, entity.ErrInvalidSort
+	}
+
+	scope := fmt.Sprintf("user=%d;mode=%s;status=%s;priority=%s;overdue=%t;due_before=%s;due_after=%s;labels=%v;match=%s;parent=%s;project=%s;archived=%t",
+		req.UserID, req.Mode, req.Status, req.Priority, req.Overdue, formatScopeTime(req.DueBefore), formatScopeTime(req.DueAfter),
+		req.LabelIDs, req.LabelMatch, formatScopeID(req.ParentID), formatScopeID(req.ProjectID), req.Archived)
+	cursor, err := decodeCursor(req.Page, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromWhere string
+	args := []interface{}{req.UserID}
+	switch req.Mode {
+	case entity.TaskListOwned:
+		fromWhere = `FROM task WHERE owner_id = $1`
+	case entity.TaskListAssigned:
+		fromWhere = `FROM task WHERE id IN (SELECT task_id FROM task_assignee WHERE user_id = $1)`
+	case entity.TaskListShared:
+		fromWhere = `FROM task WHERE id IN (SELECT task_id FROM task_share WHERE user_id = $1)`
+	default:
+		// Без режима выбираются подзадачи или задачи проекта: доступ к родителю
+		// или проекту уже проверен в usecase
+		if req.ParentID == nil && req.ProjectID == nil {
+			return nil, entity.ErrInvalidFilter
+		}
+		fromWhere = `FROM task WHERE TRUE`
+		args = nil
+	}
+
+	if req.Archived {
+		fromWhere += " AND archived_at IS NOT NULL"
+	} else {
+		fromWhere += " AND archived_at IS NULL"
+	}
+
+	if req.Status != "" {
+		args = append(args, req.Status)
+		fromWhere += " AND status = $" + strconv.Itoa(len(args))
+	}
+	if req.Priority != "" {
+		args = append(args, req.Priority)
+		fromWhere += " AND priority = $" + strconv.Itoa(len(args))
+	}
+	if req.Overdue {
+		fromWhere += " AND due_at < CURRENT_TIMESTAMP AND status NOT IN ('completed', 'cancelled')"
+	}
+	if req.DueBefore != nil {
+		args = append(args, *req.DueBefore)
+		fromWhere += " AND due_at < $" + strconv.Itoa(len(args))
+	}
+	if req.DueAfter != nil {
+		args = append(args, *req.DueAfter)
+		fromWhere += " AND due_at >= $" + strconv.Itoa(len(args))
+	}
+	if req.ParentID != nil {
+		args = append(args, *req.ParentID)
+		fromWhere += " AND parent_id = $" + strconv.Itoa(len(args))
+	}
+	if req.ProjectID != nil {
+		args = append(args, *req.ProjectID)
+		fromWhere += " AND project_id = $" + strconv.Itoa(len(args))
+	}
+	if cond, condArgs := labelFilter(req.LabelIDs, req.LabelMatch, len(args)+1); cond != "" {
+		fromWhere += " AND " + cond
+		args = append(args, condArgs...)
+	}
+
+	page := &entity.TaskPage{}
+	if req.Page.Total != entity.TotalNone {
+		total, err := countRows(ctx, r.db, fromWhere, args, req.Page.Total)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.TotalEstimated = req.Page.Total == entity.TotalEstimated
+	}
 
-	if status != "" {
-		query += " AND status = $2"
-		args = append(args, status)
+	query := `SELECT ` + taskColumns + ` ` + fromWhere
+	if cond, condArgs := keysetCondition(col, req.Page.SortDir, cursor, len(args)+1); cond != "" {
+		query += " AND " + cond
+		args = append(args, condArgs...)
 	}
+	query += keysetOrder(col, req.Page.SortDir)
 
-	query += " ORDER BY created_at DESC"
+	// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+	args = append(args, req.Page.PageSize+1)
+	query += " LIMIT $" + strconv.Itoa(len(args))
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -159,20 +282,207 @@ func (r *TaskRepository) List(ctx context.Context, ownerID int, status string) (
 	var tasks []entity.Task
 	for rows.Next() {
 		var task entity.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.OwnerId,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		err := rows.Scan(taskFields(&task)...)
 		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) > req.Page.PageSize {
+		tasks = tasks[:req.Page.PageSize]
+		last := tasks[len(tasks)-1]
+		page.NextPageToken = encodeCursor(pageCursor{
+			SortBy:  req.Page.SortBy,
+			SortDir: string(req.Page.SortDir),
+			Scope:   scope,
+			Value:   taskSortValue(&last, req.Page.SortBy),
+			ID:      last.ID,
+		})
+	}
+
+	page.Tasks = tasks
+	return page, nil
+}
+
+// GetSubtree - задача rootID и ее потомки не глубже maxDepth уровней (0 - без ограничения),
+// упорядоченные по уровню. Путь обхода защищает от зацикливания на битых данных
+func (r *TaskRepository) GetSubtree(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error) {
+	query := `
+	WITH RECURSIVE tree AS (
+		SELECT t.*, 0 AS depth, ARRAY[t.id] AS path
+		FROM task t
+		WHERE t.id = $1
+		UNION ALL
+		SELECT c.*, tree.depth + 1, tree.path || c.id
+		FROM task c
+		JOIN tree ON c.parent_id = tree.id
+		WHERE ($2 = 0 OR tree.depth < $2) AND c.id <> ALL(tree.path)
+	)
+	SELECT ` + taskColumns + `
+	FROM tree
+	ORDER BY depth, created_at, id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, rootID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []entity.Task
+	for rows.Next() {
+		var task entity.Task
+		if err := rows.Scan(taskFields(&task)...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// ListSubtreeStatuses - статусы всего поддерева rootID (включая сам корень) для подсчета прогресса
+func (r *TaskRepository) ListSubtreeStatuses(ctx context.Context, rootID int) ([]entity.TaskStatusNode, error) {
+	query := `
+	WITH RECURSIVE tree AS (
+		SELECT id, parent_id, status, ARRAY[id] AS path
+		FROM task
+		WHERE id = $1
+		UNION ALL
+		SELECT c.id, c.parent_id, c.status, tree.path || c.id
+		FROM task c
+		JOIN tree ON c.parent_id = tree.id
+		WHERE c.id <> ALL(tree.path)
+	)
+	SELECT id, parent_id, status FROM tree
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []entity.TaskStatusNode
+	for rows.Next() {
+		var node entity.TaskStatusNode
+		if err := rows.Scan(&node.ID, &node.ParentID, &node.Status); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, rows.Err()
+}
+
+// GetAncestorIDs - ID предков задачи от родителя до корня
+func (r *TaskRepository) GetAncestorIDs(ctx context.Context, taskID int) ([]int, error) {
+	query := `
+	WITH RECURSIVE ancestors AS (
+		SELECT parent_id AS id, 1 AS depth, ARRAY[id] AS path
+		FROM task
+		WHERE id = $1 AND parent_id IS NOT NULL
+		UNION ALL
+		SELECT t.parent_id, a.depth + 1, a.path || t.id
+		FROM task t
+		JOIN ancestors a ON t.id = a.id
+		WHERE t.parent_id IS NOT NULL AND t.id <> ALL(a.path)
+	)
+	SELECT id FROM ancestors ORDER BY depth
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return tasks, nil
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Пространства advisory-блокировок иерархии задач: деревья вне проектов
+// принадлежат одному владельцу, деревья в проекте - одному проекту
+const (
+	taskHierarchyLockKey    = 1012
+	projectHierarchyLockKey = 1014
+)
+
+// LockHierarchy блокирует иерархию задач владельца (или проекта, если задан projectID)
+// до конца транзакции, чтобы параллельные перемещения не создали цикл
+func (r *TaskRepository) LockHierarchy(ctx context.Context, ownerID int, projectID *int) error {
+	key, id := taskHierarchyLockKey, ownerID
+	if projectID != nil {
+		key, id = projectHierarchyLockKey, *projectID
+	}
+	_, err := conn(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, key, id)
+	return err
+}
+
+// ArchiveByProject архивирует все активные задачи проекта и возвращает их новое состояние
+func (r *TaskRepository) ArchiveByProject(ctx context.Context, projectID int) ([]entity.Task, error) {
+	query := `
+	UPDATE task
+	SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP, version = version + 1
+	WHERE project_id = $1 AND archived_at IS NULL
+	RETURNING ` + taskColumns + `
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []entity.Task
+	for rows.Next() {
+		var task entity.Task
+		if err := rows.Scan(taskFields(&task)...); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// taskSortValue возвращает значение поля сортировки для курсора
+func taskSortValue(task *entity.Task, sortBy string) string {
+	switch sortBy {
+	case entity.TaskSortCreatedAt:
+		return task.CreatedAt.Format(time.RFC3339Nano)
+	case entity.TaskSortUpdatedAt:
+		return task.UpdatedAt.Format(time.RFC3339Nano)
+	case entity.TaskSortTitle:
+		return task.Title
+	}
+	return ""
+}
+
+// formatScopeID форматирует необязательный ID для scope курсора
+func formatScopeID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
+
+// formatScopeTime форматирует необязательный фильтр по времени для scope курсора
+func formatScopeTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
 }
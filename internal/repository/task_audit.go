@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,13 +21,21 @@ func NewTaskAuditRepository(db *pgxpool.Pool) *TaskAuditRepository {
 	}
 }
 
+// Create - сохраняем запись аудита. changed_at - время события из сообщения (ChangesAt):
+// после outbox, повторов и DLQ запись может дойти с опозданием и не по порядку.
+// Без времени события (старые сообщения) берется время вставки
 func (r *TaskAuditRepository) Create(ctx context.Context, audit *entity.TaskAudit) error {
 	query := `
-	INSERT INTO "task_audit" (user_id, action, entity_type, entity_id, old_values, new_values, changes)
-	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	INSERT INTO "task_audit" (user_id, action, entity_type, entity_id, old_values, new_values, changes, changed_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,COALESCE($8, CURRENT_TIMESTAMP))
 	RETURNING id, changed_at
 	`
 
+	var changedAt *time.Time
+	if !audit.ChangesAt.IsZero() {
+		changedAt = &audit.ChangesAt
+	}
+
 	err := r.db.QueryRow(
 		ctx,
 		query,
@@ -34,19 +46,76 @@ func (r *TaskAuditRepository) Create(ctx context.Context, audit *entity.TaskAudi
 		audit.OldValues,
 		audit.NewValues,
 		audit.Changes,
+		changedAt,
 	).Scan(&audit.ID, &audit.ChangesAt)
 
 	return err
 }
 
-func (r *TaskAuditRepository) GetByTaskAuditId(ctx context.Context, taskAuditId int) ([]entity.TaskAudit, error) {
-	query := `
-	SELECT id, user_id, action, entity_type, entity_id, old_values, new_values, changes, changed_at
-	FROM "task_audit"
-	WHERE entity_id = $1 and entity_type = 'task'
-	ORDER BY changed_at DESC
-	`
-	rows, err := r.db.Query(ctx, query, taskAuditId)
+// taskHistorySortColumns - поля, по которым можно сортировать историю задачи
+var taskHistorySortColumns = map[string]sortColumn{
+	entity.TaskHistorySortChangedAt: {column: "changed_at", cast: "timestamptz"},
+}
+
+// ListByTask - страница истории задачи с фильтрами по действию, автору и времени
+func (r *TaskAuditRepository) ListByTask(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error) {
+	col, ok := taskHistorySortColumns[req.Page.SortBy]
+	if !ok {
+		return nil, entity.ErrInvalidSort
+	}
+
+	fromWhere := `FROM "task_audit" WHERE entity_type = 'task' AND entity_id = $1`
+	args := []interface{}{req.TaskID}
+	scope := fmt.Sprintf("task=%d", req.TaskID)
+
+	if req.Action != "" {
+		args = append(args, req.Action)
+		fromWhere += " AND action = $" + strconv.Itoa(len(args))
+		scope += fmt.Sprintf(";action=%s", req.Action)
+	}
+	if req.ActorID != 0 {
+		args = append(args, req.ActorID)
+		fromWhere += " AND user_id = $" + strconv.Itoa(len(args))
+		scope += fmt.Sprintf(";actor=%d", req.ActorID)
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		fromWhere += " AND changed_at >= $" + strconv.Itoa(len(args))
+		scope += fmt.Sprintf(";from=%d", req.From.UnixNano())
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		fromWhere += " AND changed_at < $" + strconv.Itoa(len(args))
+		scope += fmt.Sprintf(";to=%d", req.To.UnixNano())
+	}
+
+	cursor, err := decodeCursor(req.Page, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.TaskAuditPage{}
+	if req.Page.Total != entity.TotalNone {
+		total, err := countRows(ctx, r.db, fromWhere, args, req.Page.Total)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.TotalEstimated = req.Page.Total == entity.TotalEstimated
+	}
+
+	query := `SELECT id, user_id, action, entity_type, entity_id, old_values, new_values, changes, changed_at ` + fromWhere
+	if cond, condArgs := keysetCondition(col, req.Page.SortDir, cursor, len(args)+1); cond != "" {
+		query += " AND " + cond
+		args = append(args, condArgs...)
+	}
+	query += keysetOrder(col, req.Page.SortDir)
+
+	// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+	args = append(args, req.Page.PageSize+1)
+	query += " LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -71,5 +140,48 @@ func (r *TaskAuditRepository) GetByTaskAuditId(ctx context.Context, taskAuditId
 		}
 		audits = append(audits, audit)
 	}
-	return audits, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(audits) > req.Page.PageSize {
+		audits = audits[:req.Page.PageSize]
+		last := audits[len(audits)-1]
+		page.NextPageToken = encodeCursor(pageCursor{
+			SortBy:  req.Page.SortBy,
+			SortDir: string(req.Page.SortDir),
+			Scope:   scope,
+			Value:   last.ChangesAt.Format(time.RFC3339Nano),
+			ID:      last.ID,
+		})
+	}
+
+	page.Audits = audits
+	return page, nil
+}
+
+// GetTaskOwnerID - владелец задачи по аудиту (нужен, когда задача уже удалена).
+// Возвращает 0, если в аудите нет записей о создании или удалении задачи
+func (r *TaskAuditRepository) GetTaskOwnerID(ctx context.Context, taskID int) (int, error) {
+	query := `
+	SELECT COALESCE(new_values->>'owner_id', old_values->>'owner_id')::int
+	FROM "task_audit"
+	WHERE entity_type = 'task' AND entity_id = $1 AND action IN ('Create', 'Delete')
+	ORDER BY changed_at DESC, id DESC
+	LIMIT 1
+	`
+
+	var ownerID *int
+	err := r.db.QueryRow(ctx, query, taskID).Scan(&ownerID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if ownerID == nil {
+		return 0, nil
+	}
+
+	return *ownerID, nil
 }
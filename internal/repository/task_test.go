@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestPrefixedTaskColumns(t *testing.T) {
+	columns := strings.Split(prefixedTaskColumns("t"), ", ")
+	if len(columns) != len(taskFields(&entity.Task{})) {
+		t.Fatalf("Expected %d columns, got %d", len(taskFields(&entity.Task{})), len(columns))
+	}
+	for _, column := range columns {
+		if !strings.HasPrefix(column, "t.") || strings.Count(column, ".") != 1 {
+			t.Errorf("Unexpected column %q", column)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/jackc/pgx/v5"
@@ -24,12 +26,12 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.CreateUserRequ
 	query := `
 	INSERT INTO "user" (name)
 	VALUES ($1)
-	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at
+	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at
 	`
 
 	var createdUser entity.User
 
-	err := r.db.QueryRow(ctx, query, user.Name).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, user.Name).Scan(
 		&createdUser.ID,
 		&createdUser.Name,
 		&createdUser.Email,
@@ -37,6 +39,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.CreateUserRequ
 		&createdUser.AvatarURL,
 		&createdUser.IsActive,
 		&createdUser.LastLogin,
+		&createdUser.EmailVerifiedAt,
 		&createdUser.CreatedAt,
 		&createdUser.UpdatedAt,
 	)
@@ -51,13 +54,13 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.CreateUserRequ
 // получаем данные по id
 func (r *UserRepository) GetById(ctx context.Context, id int) (*entity.User, error) {
 	query := `
-	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at 
+	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at 
 	FROM "user"
 	WHERE  id = ($1)
 	`
 	var user entity.User
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
@@ -65,6 +68,7 @@ func (r *UserRepository) GetById(ctx context.Context, id int) (*entity.User, err
 		&user.AvatarURL,
 		&user.IsActive,
 		&user.LastLogin,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -86,9 +90,11 @@ func (r *UserRepository) Update(ctx context.Context, id int, updates map[string]
 	    email = COALESCE($2, email),
 	    avatar_url = COALESCE($3, avatar_url),
 	    last_login = COALESCE($4, last_login),
+	    password_hash = COALESCE($5, password_hash),
+	    email_verified_at = COALESCE($6, email_verified_at),
 	    updated_at = CURRENT_TIMESTAMP
-	WHERE id = $5
-	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at
+	WHERE id = $7
+	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at
 	`
 
 	var user entity.User
@@ -97,8 +103,10 @@ func (r *UserRepository) Update(ctx context.Context, id int, updates map[string]
 	var email interface{} = updates["email"]
 	var avatarURL interface{} = updates["avatar_url"]
 	var lastLogin interface{} = updates["last_login"]
+	var passwordHash interface{} = updates["password_hash"]
+	var emailVerifiedAt interface{} = updates["email_verified_at"]
 
-	err := r.db.QueryRow(ctx, query, name, email, avatarURL, lastLogin, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, name, email, avatarURL, lastLogin, passwordHash, emailVerifiedAt, id).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
@@ -106,6 +114,7 @@ func (r *UserRepository) Update(ctx context.Context, id int, updates map[string]
 		&user.AvatarURL,
 		&user.IsActive,
 		&user.LastLogin,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -123,12 +132,12 @@ func (r *UserRepository) Update(ctx context.Context, id int, updates map[string]
 // List - получаем всех пользователей
 func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
 	query := `
-	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at 
+	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at 
 	FROM "user"
 	ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +154,7 @@ func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
 			&user.AvatarURL,
 			&user.IsActive,
 			&user.LastLogin,
+			&user.EmailVerifiedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -157,10 +167,109 @@ func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
 	return users, rows.Err()
 }
 
+// userSortColumns - поля, по которым можно сортировать пользователей
+var userSortColumns = map[string]sortColumn{
+	entity.UserSortCreatedAt: {column: "created_at", cast: "timestamptz"},
+	entity.UserSortName:      {column: "name", cast: "text"},
+	entity.UserSortID:        {column: "id", cast: "integer"},
+}
+
+// ListPage - страница пользователей с keyset-пагинацией
+func (r *UserRepository) ListPage(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserPage, error) {
+	col, ok := userSortColumns[req.Page.SortBy]
+	if !ok {
+		return nil, entity.ErrInvalidSort
+	}
+
+	cursor, err := decodeCursor(req.Page, "")
+	if err != nil {
+		return nil, err
+	}
+
+	fromWhere := `FROM "user" WHERE TRUE`
+	var args []interface{}
+
+	page := &entity.UserPage{}
+	if req.Page.Total != entity.TotalNone {
+		total, err := countRows(ctx, r.db, fromWhere, args, req.Page.Total)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.TotalEstimated = req.Page.Total == entity.TotalEstimated
+	}
+
+	query := `SELECT id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at ` + fromWhere
+	if cond, condArgs := keysetCondition(col, req.Page.SortDir, cursor, len(args)+1); cond != "" {
+		query += " AND " + cond
+		args = append(args, condArgs...)
+	}
+	query += keysetOrder(col, req.Page.SortDir)
+
+	// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+	args = append(args, req.Page.PageSize+1)
+	query += " LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.PasswordHash,
+			&user.AvatarURL,
+			&user.IsActive,
+			&user.LastLogin,
+			&user.EmailVerifiedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(users) > req.Page.PageSize {
+		users = users[:req.Page.PageSize]
+		last := users[len(users)-1]
+		page.NextPageToken = encodeCursor(pageCursor{
+			SortBy:  req.Page.SortBy,
+			SortDir: string(req.Page.SortDir),
+			Value:   userSortValue(&last, req.Page.SortBy),
+			ID:      last.ID,
+		})
+	}
+
+	page.Users = users
+	return page, nil
+}
+
+// userSortValue возвращает значение поля сортировки для курсора
+func userSortValue(user *entity.User, sortBy string) string {
+	switch sortBy {
+	case entity.UserSortCreatedAt:
+		return user.CreatedAt.Format(time.RFC3339Nano)
+	case entity.UserSortName:
+		return user.Name
+	}
+	return ""
+}
+
 // Delete - удаляем пользователя
 func (r *UserRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM "user" WHERE id = $1`
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -173,13 +282,13 @@ func (r *UserRepository) Delete(ctx context.Context, id int) error {
 // GetByEmail - получаем пользователя по email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at 
+	SELECT id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at 
 	FROM "user"
 	WHERE email = $1
 	`
 	var user entity.User
 
-	err := r.db.QueryRow(ctx, query, email).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, email).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
@@ -187,6 +296,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.
 		&user.AvatarURL,
 		&user.IsActive,
 		&user.LastLogin,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -205,12 +315,12 @@ func (r *UserRepository) CreateWithAuth(ctx context.Context, name, email, passwo
 	query := `
 	INSERT INTO "user" (name, email, password_hash, is_active)
 	VALUES ($1, $2, $3, true)
-	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, created_at, updated_at
+	RETURNING id, name, email, password_hash, avatar_url, is_active, last_login, email_verified_at, created_at, updated_at
 	`
 
 	var user entity.User
 
-	err := r.db.QueryRow(ctx, query, name, email, passwordHash).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, name, email, passwordHash).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
@@ -218,6 +328,7 @@ func (r *UserRepository) CreateWithAuth(ctx context.Context, name, email, passwo
 		&user.AvatarURL,
 		&user.IsActive,
 		&user.LastLogin,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
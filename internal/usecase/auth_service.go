@@ -2,39 +2,88 @@ package usecase
 
 import (
 	"context"
-	"crypto/sha256"
+	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
 	"github.com/St1cky1/task-service/internal/repository"
 )
 
+const (
+	minPasswordLength = 8
+	// maxPasswordLength - ограничение bcrypt, более длинный пароль не хешируется
+	maxPasswordLength = 72
+)
+
 type AuthService struct {
-	userRepo         repository.IUserRepository
-	refreshTokenRepo repository.IRefreshTokenRepository
-	passwordManager  *auth.PasswordManager
-	jwtManager       *auth.JWTManager
+	userRepo              repository.IUserRepository
+	refreshTokenRepo      repository.IRefreshTokenRepository
+	passwordResetRepo     repository.IPasswordResetRepository
+	emailVerificationRepo repository.IEmailVerificationRepository
+	loginAttemptRepo      repository.ILoginAttemptRepository
+	authAuditRepo         repository.IAuthAuditRepository
+	roleService           *RoleService
+	passwordManager       *auth.PasswordManager
+	jwtManager            *auth.JWTManager
+	mailer                mailer.Mailer
+	transactor            repository.ITransactor
+	passwordResetURL      string
+	emailVerificationURL  string
+	accountLoginPolicy    entity.LoginThrottlePolicy
+	ipLoginPolicy         entity.LoginThrottlePolicy
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+
+	// mailSending - письма, которые еще отправляются в фоне
+	mailSending sync.WaitGroup
 }
 
 func NewAuthService(
 	userRepo repository.IUserRepository,
 	refreshTokenRepo repository.IRefreshTokenRepository,
+	passwordResetRepo repository.IPasswordResetRepository,
+	emailVerificationRepo repository.IEmailVerificationRepository,
+	loginAttemptRepo repository.ILoginAttemptRepository,
+	authAuditRepo repository.IAuthAuditRepository,
+	roleService *RoleService,
 	passwordManager *auth.PasswordManager,
 	jwtManager *auth.JWTManager,
+	mailer mailer.Mailer,
+	transactor repository.ITransactor,
 ) *AuthService {
 	return &AuthService{
-		userRepo:         userRepo,
-		refreshTokenRepo: refreshTokenRepo,
-		passwordManager:  passwordManager,
-		jwtManager:       jwtManager,
+		userRepo:              userRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		passwordResetRepo:     passwordResetRepo,
+		emailVerificationRepo: emailVerificationRepo,
+		loginAttemptRepo:      loginAttemptRepo,
+		authAuditRepo:         authAuditRepo,
+		roleService:           roleService,
+		passwordManager:       passwordManager,
+		jwtManager:            jwtManager,
+		mailer:                mailer,
+		transactor:            transactor,
+		accountLoginPolicy:    DefaultAccountLoginPolicy,
+		ipLoginPolicy:         DefaultIPLoginPolicy,
 	}
 }
 
 // Register регистрирует нового пользователя
 func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest) (*entity.LoginResponse, error) {
+	if !isValidPassword(req.Password) {
+		return nil, entity.ErrInvalidPassword
+	}
+
 	// Проверяем, что пользователь с таким email не существует
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
@@ -56,88 +105,82 @@ func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Генерируем токены
-	email := ""
-	if user.Email != nil {
-		email = *user.Email
+	// Письмо можно запросить повторно, поэтому сбой отправки не мешает регистрации
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Printf("⚠️  Не удалось отправить письмо подтверждения пользователю %d: %v", user.ID, err)
 	}
 
-	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
-	}
+	return s.startSession(ctx, user, req.Client)
+}
 
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+// Login логинит пользователя. Неудачные попытки считаются по email и по IP: после нескольких
+// неудач вход закрывается на растущую задержку, затем на время блокировки (LoginThrottledError).
+// Любая неудача - ErrInvalidCredentials, причина пишется только в журнал входов
+func (s *AuthService) Login(ctx context.Context, req *entity.LoginRequest) (*entity.LoginResponse, error) {
+	// Такого email нет ни у кого (колонка - VARCHAR(255)), счетчик для него не заводим
+	if len(req.Email) > maxEmailLength {
+		return nil, entity.ErrInvalidCredentials
 	}
-
-	// Сохраняем хеш refresh token в БД
-	refreshTokenHash := s.hashToken(refreshToken)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	err = s.refreshTokenRepo.Save(ctx, user.ID, refreshTokenHash, expiresAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	reservations, err := s.reserveLogin(ctx, s.loginKeys(req))
+	var throttled *entity.LoginThrottledError
+	if errors.As(err, &throttled) {
+		s.auditLogin(ctx, entity.AuthEventLoginThrottled, nil, req, fmt.Sprintf("retry in %s", throttled.RetryAfter.Round(time.Second)))
+		return nil, err
 	}
-
-	// Обновляем last_login
-	updates := make(map[string]interface{})
-	updates["last_login"] = time.Now()
-	_, err = s.userRepo.Update(ctx, user.ID, updates)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update last_login: %w", err)
+		return nil, err
 	}
 
-	return &entity.LoginResponse{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
-}
-
-// Login логинит пользователя
-func (s *AuthService) Login(ctx context.Context, req *entity.LoginRequest) (*entity.LoginResponse, error) {
 	// Ищем пользователя по email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	if user == nil {
-		return nil, fmt.Errorf("invalid email or password")
+
+	// Пароль проверяем и для неизвестного email: по времени ответа нельзя узнать, есть ли аккаунт
+	passwordHash := s.dummyPasswordHash()
+	if user != nil {
+		passwordHash = user.PasswordHash
 	}
+	passwordValid := s.passwordManager.VerifyPassword(passwordHash, req.Password)
 
-	// Проверяем активность пользователя
-	if !user.IsActive {
-		return nil, fmt.Errorf("user is not active")
+	switch {
+	case user == nil:
+		return nil, s.loginFailed(ctx, reservations, nil, req, "unknown email")
+	case !passwordValid:
+		return nil, s.loginFailed(ctx, reservations, &user.ID, req, "wrong password")
+	case !user.IsActive:
+		return nil, s.loginFailed(ctx, reservations, &user.ID, req, "inactive account")
 	}
 
-	// Проверяем пароль
-	if !s.passwordManager.VerifyPassword(user.PasswordHash, req.Password) {
-		return nil, fmt.Errorf("invalid email or password")
+	if err := s.releaseLogin(ctx, reservations); err != nil {
+		return nil, err
 	}
+	s.auditLogin(ctx, entity.AuthEventLoginSucceeded, &user.ID, req, "")
 
-	// Генерируем токены
+	return s.startSession(ctx, user, req.Client)
+}
+
+// startSession выдает токены новой сессии и обновляет last_login
+func (s *AuthService) startSession(ctx context.Context, user *entity.User, client entity.ClientInfo) (*entity.LoginResponse, error) {
 	email := ""
 	if user.Email != nil {
 		email = *user.Email
 	}
 
-	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, email)
+	roles, err := s.roleService.GetUserRoles(ctx, user.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, err
 	}
 
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, email)
+	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, email, roles, user.EmailVerified())
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	// Сохраняем хеш refresh token в БД
-	refreshTokenHash := s.hashToken(refreshToken)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	err = s.refreshTokenRepo.Save(ctx, user.ID, refreshTokenHash, expiresAt)
+	refreshToken, err := issueRefreshToken(ctx, s.jwtManager, s.refreshTokenRepo, user.ID, email, client, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+		return nil, err
 	}
 
 	// Обновляем last_login
@@ -155,48 +198,62 @@ func (s *AuthService) Login(ctx context.Context, req *entity.LoginRequest) (*ent
 	}, nil
 }
 
-// RefreshToken обновляет access token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string) (*entity.RefreshTokenResponse, error) {
+// RefreshToken обновляет access token и ротирует refresh token внутри его сессии.
+// Повторное предъявление уже отозванного токена завершает всю сессию: токен мог быть украден,
+// и неизвестно, у кого из двоих сейчас актуальная цепочка
+func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string, client entity.ClientInfo) (*entity.RefreshTokenResponse, error) {
 	// Проверяем refresh token
 	claims, err := s.jwtManager.ValidateRefreshToken(refreshTokenStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid refresh token: %w", err)
+		return nil, entity.ErrInvalidRefreshToken
 	}
 
 	// Проверяем, есть ли этот токен в БД
-	refreshTokenHash := s.hashToken(refreshTokenStr)
+	refreshTokenHash := hashToken(refreshTokenStr)
 	storedToken, err := s.refreshTokenRepo.GetByHash(ctx, refreshTokenHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get refresh token: %w", err)
 	}
-	if storedToken == nil {
-		return nil, fmt.Errorf("refresh token not found or expired")
+	if storedToken == nil || storedToken.UserID != claims.UserID {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+	if storedToken.Revoked {
+		return nil, s.revokeReusedSession(ctx, storedToken, client)
 	}
 
-	// Генерируем новый access token
-	newAccessToken, err := s.jwtManager.GenerateAccessToken(claims.UserID, claims.Email)
+	// Роли и подтверждение email берем из БД, чтобы изменения применялись при следующем обновлении токена
+	user, err := s.userRepo.GetById(ctx, claims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate new access token: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !user.IsActive {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+	roles, err := s.roleService.GetUserRoles(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Генерируем новый refresh token
-	newRefreshToken, err := s.jwtManager.GenerateRefreshToken(claims.UserID, claims.Email)
+	// Генерируем новый access token
+	newAccessToken, err := s.jwtManager.GenerateAccessToken(claims.UserID, claims.Email, roles, user.EmailVerified())
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate new refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate new access token: %w", err)
 	}
 
-	// Откатываем старый refresh token
-	err = s.refreshTokenRepo.Revoke(ctx, refreshTokenHash)
+	// Откатываем старый refresh token. Если его успел отозвать параллельный запрос,
+	// это такое же повторное использование
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, refreshTokenHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to revoke old refresh token: %w", err)
 	}
+	if !revoked {
+		return nil, s.revokeReusedSession(ctx, storedToken, client)
+	}
 
-	// Сохраняем новый refresh token
-	newRefreshTokenHash := s.hashToken(newRefreshToken)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	err = s.refreshTokenRepo.Save(ctx, claims.UserID, newRefreshTokenHash, expiresAt)
+	// Новый refresh token продолжает ту же сессию
+	newRefreshToken, err := issueRefreshToken(ctx, s.jwtManager, s.refreshTokenRepo, claims.UserID, claims.Email, client, storedToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save new refresh token: %w", err)
+		return nil, err
 	}
 
 	return &entity.RefreshTokenResponse{
@@ -205,6 +262,71 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 	}, nil
 }
 
+// revokeReusedSession завершает сессию, в которой предъявили отозванный токен
+func (s *AuthService) revokeReusedSession(ctx context.Context, token *repository.RefreshToken, client entity.ClientInfo) error {
+	log.Printf("⚠️  Повторное использование refresh token: пользователь %d, сессия %s, IP %s - сессия завершена",
+		token.UserID, token.FamilyID, client.IPAddress)
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return entity.ErrRefreshTokenReused
+}
+
+// ListSessions возвращает активные сессии пользователя, недавно использованные первыми
+func (s *AuthService) ListSessions(ctx context.Context, userID int) ([]entity.Session, error) {
+	tokens, err := s.refreshTokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh tokens: %w", err)
+	}
+
+	// Активный токен в сессии один, но после гонок их может оказаться больше -
+	// сессию описывает самый новый
+	sessions := make([]entity.Session, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if seen[token.FamilyID] {
+			continue
+		}
+		seen[token.FamilyID] = true
+		sessions = append(sessions, entity.Session{
+			ID:         token.FamilyID,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  token.SessionStartedAt,
+			LastUsedAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession завершает сессию пользователя: отзывает ее активные refresh токены.
+// Уже выданный access token доживает свои 15 минут
+func (s *AuthService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	tokens, err := s.refreshTokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get refresh tokens: %w", err)
+	}
+
+	found := false
+	for _, token := range tokens {
+		if token.FamilyID != sessionID {
+			continue
+		}
+		found = true
+		if _, err := s.refreshTokenRepo.Revoke(ctx, token.TokenHash); err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+	}
+	if !found {
+		return entity.ErrSessionNotFound
+	}
+
+	return nil
+}
+
 // Logout откатывает все refresh токены пользователя
 func (s *AuthService) Logout(ctx context.Context, userID int) error {
 	err := s.refreshTokenRepo.RevokeAll(ctx, userID)
@@ -214,8 +336,102 @@ func (s *AuthService) Logout(ctx context.Context, userID int) error {
 	return nil
 }
 
-// hashToken генерирует хеш токена для хранения в БД
-func (s *AuthService) hashToken(token string) string {
-	hash := sha256.Sum256([]byte(token))
-	return hex.EncodeToString(hash[:])
+// refreshTokenTTL - срок жизни refresh token, каждая ротация продлевает сессию на этот срок
+const refreshTokenTTL = 7 * 24 * time.Hour
+
+// issueRefreshToken выдает refresh token и сохраняет его хеш. parent - предыдущий токен сессии,
+// nil - вход, начинающий новую сессию
+func issueRefreshToken(
+	ctx context.Context,
+	jwtManager *auth.JWTManager,
+	refreshTokenRepo repository.IRefreshTokenRepository,
+	userID int,
+	email string,
+	client entity.ClientInfo,
+	parent *repository.RefreshToken,
+) (string, error) {
+	refreshToken, err := jwtManager.GenerateRefreshToken(userID, email)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	record := &repository.RefreshToken{
+		UserID:           userID,
+		TokenHash:        hashToken(refreshToken),
+		ExpiresAt:        now.Add(refreshTokenTTL),
+		FamilyID:         newSessionID(),
+		SessionStartedAt: now,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+	}
+	if parent != nil {
+		record.FamilyID = parent.FamilyID
+		record.SessionStartedAt = parent.SessionStartedAt
+	}
+
+	// Сохраняем хеш refresh token в БД
+	if err := refreshTokenRepo.Save(ctx, record); err != nil {
+		return "", fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return refreshToken, nil
+}
+
+// newMailToken генерирует случайный токен для ссылки из письма, в БД хранится его hashToken
+func newMailToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(random), nil
+}
+
+// mailLink добавляет токен параметром token к странице base. Без base в письмо идет сам токен
+func mailLink(base, token string) string {
+	if base == "" {
+		return token
+	}
+	link, err := url.Parse(base)
+	if err != nil {
+		return token
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// newSessionID генерирует случайный ID семейства refresh токенов
+func newSessionID() string {
+	random := make([]byte, 16)
+	rand.Read(random)
+	return hex.EncodeToString(random)
+}
+
+// isValidPassword проверяет длину пароля: от minPasswordLength символов до maxPasswordLength байт
+func isValidPassword(password string) bool {
+	return utf8.RuneCountInString(password) >= minPasswordLength && len(password) <= maxPasswordLength
+}
+
+// mailSendTimeout - сколько ждать почтовый сервер при отправке в фоне
+const mailSendTimeout = 30 * time.Second
+
+// WaitMail ждет письма, которые еще отправляются в фоне, - при остановке сервера
+func (s *AuthService) WaitMail() {
+	s.mailSending.Wait()
+}
+
+// sendMailAsync отправляет письмо в фоне: ответ не ждет почтовый сервер, и время ответа
+// не зависит от того, ушло ли письмо. Отправка не отменяется вместе с запросом,
+// сбой только логируется
+func (s *AuthService) sendMailAsync(ctx context.Context, userID int, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailSendTimeout)
+	s.mailSending.Add(1)
+	go func() {
+		defer s.mailSending.Done()
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("⚠️  Не удалось отправить письмо %q пользователю %d: %v", msg.Subject, userID, err)
+		}
+	}()
 }
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockRefreshTokenRepository - мок для IRefreshTokenRepository, хранит токены в памяти
+type MockRefreshTokenRepository struct {
+	tokens []*repository.RefreshToken
+}
+
+var _ repository.IRefreshTokenRepository = (*MockRefreshTokenRepository)(nil)
+
+func (m *MockRefreshTokenRepository) Save(ctx context.Context, token *repository.RefreshToken) error {
+	saved := *token
+	saved.ID = len(m.tokens) + 1
+	saved.CreatedAt = time.Now()
+	m.tokens = append(m.tokens, &saved)
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByUserID(ctx context.Context, userID int) ([]repository.RefreshToken, error) {
+	var tokens []repository.RefreshToken
+	for i := len(m.tokens) - 1; i >= 0; i-- {
+		if token := m.tokens[i]; token.UserID == userID && !token.Revoked {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, tokenHash string) (bool, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash && !token.Revoked {
+			token.Revoked = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID {
+			token.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeAll(ctx context.Context, userID int) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			token.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) CleanupExpired(ctx context.Context) error {
+	return nil
+}
+
+// MockMailer - мок для mailer.Mailer, запоминает отправленные письма
+type MockMailer struct {
+	mu   sync.Mutex
+	Sent []mailer.Message
+	Err  error // ошибка отправки, письмо при этом не запоминается
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Sent = append(m.Sent, msg)
+	return nil
+}
+
+// MockRoleRepository - мок для IRoleRepository, без ролей у пользователей
+type MockRoleRepository struct{}
+
+var _ repository.IRoleRepository = (*MockRoleRepository)(nil)
+
+func (m *MockRoleRepository) GetByUserID(ctx context.Context, userID int) ([]entity.Role, error) {
+	return nil, nil
+}
+
+func (m *MockRoleRepository) SetForUser(ctx context.Context, userID int, roles []entity.Role) error {
+	return nil
+}
+
+func (m *MockRoleRepository) GetPermissions(ctx context.Context) (map[entity.Role][]entity.Permission, error) {
+	return nil, nil
+}
+
+// testAuthService - AuthService на in-memory зависимостях, которые тесты могут проверять
+type testAuthService struct {
+	*AuthService
+	users         *MockUserRepository
+	refreshTokens *MockRefreshTokenRepository
+	resets        *MockPasswordResetRepository
+	verifications *MockEmailVerificationRepository
+	attempts      *MockLoginAttemptRepository
+	audit         *MockAuthAuditRepository
+	mails         *MockMailer
+}
+
+// newTestAuthService собирает AuthService для тестов. Без users любой пользователь
+// по ID существует и активен
+func newTestAuthService(users *MockUserRepository) *testAuthService {
+	if users == nil {
+		users = &MockUserRepository{
+			GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+				return &entity.User{ID: id, IsActive: true}, nil
+			},
+		}
+	}
+	s := &testAuthService{
+		users:         users,
+		refreshTokens: &MockRefreshTokenRepository{},
+		resets:        &MockPasswordResetRepository{},
+		verifications: &MockEmailVerificationRepository{},
+		attempts:      &MockLoginAttemptRepository{},
+		audit:         &MockAuthAuditRepository{},
+		mails:         &MockMailer{},
+	}
+	s.AuthService = NewAuthService(users, s.refreshTokens, s.resets, s.verifications, s.attempts, s.audit,
+		NewRoleService(&MockRoleRepository{}, users), auth.NewPasswordManager(), auth.NewJWTManager(), s.mails, &MockTransactor{})
+	return s
+}
+
+func TestRefreshTokenRotationAndReuse(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(nil)
+	refreshTokens := service.refreshTokens
+	client := entity.ClientInfo{UserAgent: "test", IPAddress: "10.0.0.1"}
+
+	first, err := issueRefreshToken(ctx, service.jwtManager, refreshTokens, 1, "a@example.com", client, nil)
+	if err != nil {
+		t.Fatalf("issueRefreshToken: %v", err)
+	}
+	other, err := issueRefreshToken(ctx, service.jwtManager, refreshTokens, 1, "a@example.com", client, nil)
+	if err != nil {
+		t.Fatalf("issueRefreshToken: %v", err)
+	}
+
+	// Ротация выдает новый токен той же сессии
+	resp, err := service.RefreshToken(ctx, first, client)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if resp.RefreshToken == first {
+		t.Fatal("Expected a new refresh token")
+	}
+	sessions, _ := service.ListSessions(ctx, 1)
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions after rotation, got %d", len(sessions))
+	}
+
+	// Повторное предъявление старого токена завершает сессию целиком
+	if _, err := service.RefreshToken(ctx, first, client); err != entity.ErrRefreshTokenReused {
+		t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := service.RefreshToken(ctx, resp.RefreshToken, client); err != entity.ErrRefreshTokenReused {
+		t.Errorf("Expected rotated token to be revoked with its session, got %v", err)
+	}
+
+	// Другая сессия не затронута
+	if _, err := service.RefreshToken(ctx, other, client); err != nil {
+		t.Errorf("Expected other session to survive, got %v", err)
+	}
+	if _, err := service.RefreshToken(ctx, "garbage", client); err != entity.ErrInvalidRefreshToken {
+		t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(nil)
+	refreshTokens := service.refreshTokens
+
+	token, err := issueRefreshToken(ctx, service.jwtManager, refreshTokens, 1, "a@example.com", entity.ClientInfo{}, nil)
+	if err != nil {
+		t.Fatalf("issueRefreshToken: %v", err)
+	}
+	sessions, _ := service.ListSessions(ctx, 1)
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessions))
+	}
+
+	// Чужую сессию завершить нельзя
+	if err := service.RevokeSession(ctx, 2, sessions[0].ID); err != entity.ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound for another user, got %v", err)
+	}
+	if err := service.RevokeSession(ctx, 1, sessions[0].ID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if sessions, _ := service.ListSessions(ctx, 1); len(sessions) != 0 {
+		t.Errorf("Expected no sessions after revoke, got %d", len(sessions))
+	}
+	if _, err := service.RefreshToken(ctx, token, entity.ClientInfo{}); err == nil {
+		t.Error("Expected revoked session token to be rejected")
+	}
+}
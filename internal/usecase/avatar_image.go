@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// AvatarSizes - стороны квадратных превью аватарки в пикселях, которые можно запросить в DownloadAvatar
+var AvatarSizes = []int{64, 128, 512}
+
+const (
+	// Ограничения на исходное изображение: проверяются по заголовку до декодирования,
+	// чтобы маленький файл не развернулся в гигабайты пикселей
+	maxAvatarDimension = 8192
+	maxAvatarPixels    = 25_000_000
+
+	// Полная версия аватарки вписывается в квадрат этого размера
+	maxAvatarFullSize = 1024
+	avatarJPEGQuality = 90
+)
+
+// avatarDecoders - поддерживаемые форматы аватарок, определяются по сигнатуре файла
+var avatarDecoders = map[string]struct {
+	decode       func(data []byte) (image.Image, error)
+	decodeConfig func(data []byte) (image.Config, error)
+}{
+	"jpeg": {
+		decode:       func(data []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return jpeg.DecodeConfig(bytes.NewReader(data)) },
+	},
+	"png": {
+		decode:       func(data []byte) (image.Image, error) { return png.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return png.DecodeConfig(bytes.NewReader(data)) },
+	},
+	"gif": {
+		decode:       func(data []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return gif.DecodeConfig(bytes.NewReader(data)) },
+	},
+	"webp": {
+		decode:       func(data []byte) (image.Image, error) { return webp.Decode(bytes.NewReader(data)) },
+		decodeConfig: func(data []byte) (image.Config, error) { return webp.DecodeConfig(bytes.NewReader(data)) },
+	},
+}
+
+// avatarImages - перекодированная аватарка и ее превью. Перекодирование
+// отбрасывает EXIF и прочие метаданные исходного файла
+type avatarImages struct {
+	ContentType string
+	Full        []byte
+	Variants    map[int][]byte // сторона превью -> изображение
+}
+
+// processAvatar проверяет, что data - изображение поддерживаемого формата разумного размера,
+// поворачивает его по EXIF и готовит полную версию и превью AvatarSizes.
+// JPEG остается JPEG, остальные форматы сохраняются в PNG (у GIF берется первый кадр)
+func processAvatar(data []byte) (*avatarImages, error) {
+	format := sniffImageFormat(data)
+	decoder, ok := avatarDecoders[format]
+	if !ok {
+		return nil, entity.ErrInvalidImage
+	}
+
+	config, err := decoder.decodeConfig(data)
+	if err != nil {
+		return nil, entity.ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, entity.ErrInvalidImage
+	}
+	if config.Width > maxAvatarDimension || config.Height > maxAvatarDimension ||
+		config.Width*config.Height > maxAvatarPixels {
+		return nil, entity.ErrImageTooLarge
+	}
+
+	img, err := decoder.decode(data)
+	if err != nil {
+		return nil, entity.ErrInvalidImage
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	encode, contentType := encodePNG, "image/png"
+	if format == "jpeg" {
+		encode, contentType = encodeJPEG, "image/jpeg"
+	}
+
+	result := &avatarImages{
+		ContentType: contentType,
+		Variants:    make(map[int][]byte, len(AvatarSizes)),
+	}
+	if result.Full, err = encode(fitImage(img, maxAvatarFullSize)); err != nil {
+		return nil, err
+	}
+	for _, size := range AvatarSizes {
+		if result.Variants[size], err = encode(squareThumbnail(img, size)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// sniffImageFormat определяет формат изображения по сигнатуре, а не по заявленному типу
+func sniffImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xFF\xD8\xFF")):
+		return "jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1A\n")):
+		return "png"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return "gif"
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return "webp"
+	default:
+		return ""
+	}
+}
+
+// fitImage уменьшает изображение, чтобы большая сторона не превышала maxSize
+func fitImage(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+
+	if w >= h {
+		w, h = maxSize, max(1, h*maxSize/w)
+	} else {
+		w, h = max(1, w*maxSize/h), maxSize
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// squareThumbnail вырезает квадрат из центра изображения и масштабирует его до size x size
+func squareThumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, image.Rect(x0, y0, x0+side, y0+side), draw.Src, nil)
+	return dst
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation возвращает тег Orientation (1-8) из EXIF в JPEG, 1 - если тега нет
+func jpegOrientation(data []byte) int {
+	// Сегменты идут после SOI (FF D8) до начала данных изображения (SOS)
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		if marker == 0xE1 {
+			if orientation := exifOrientation(data[i+4 : i+2+length]); orientation != 0 {
+				return orientation
+			}
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation ищет тег Orientation (0x0112) в IFD0 сегмента APP1, 0 - если не найден
+func exifOrientation(app1 []byte) int {
+	if len(app1) < 14 || string(app1[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := app1[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd + 2 + k*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// applyOrientation поворачивает и/или отражает изображение так, как требует тег EXIF Orientation:
+// после удаления EXIF картинка должна выглядеть так же, как до него
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				dx, dy = w-1-x, y
+			case 3: // поворот на 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				dx, dy = x, h-1-y
+			case 5: // транспонирование
+				dx, dy = y, x
+			case 6: // поворот на 90 по часовой
+				dx, dy = h-1-y, x
+			case 7: // транспонирование по побочной диагонали
+				dx, dy = h-1-y, w-1-x
+			case 8: // поворот на 90 против часовой
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
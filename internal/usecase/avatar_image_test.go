@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+)
+
+func TestProcessAvatarValidatesImages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatal(err)
+	}
+
+	images, err := processAvatar(pngData.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if images.ContentType != "image/png" {
+		t.Errorf("Expected image/png, got %q", images.ContentType)
+	}
+	for _, size := range AvatarSizes {
+		config, err := png.DecodeConfig(bytes.NewReader(images.Variants[size]))
+		if err != nil || config.Width != size || config.Height != size {
+			t.Errorf("Expected %dx%d variant, got %+v (%v)", size, size, config, err)
+		}
+	}
+
+	// PNG с заголовком 20000x20000: отклоняется до декодирования пикселей
+	var bomb bytes.Buffer
+	bomb.WriteString("\x89PNG\r\n\x1a\n")
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 20000)
+	binary.BigEndian.PutUint32(ihdr[4:], 20000)
+	ihdr[8], ihdr[9] = 8, 2 // 8 бит, RGB
+	binary.Write(&bomb, binary.BigEndian, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	bomb.Write(chunk)
+	binary.Write(&bomb, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("definitely not an image"), entity.ErrInvalidImage},
+		{"truncated png", pngData.Bytes()[:64], entity.ErrInvalidImage},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), entity.ErrInvalidImage},
+		{"decompression bomb", bomb.Bytes(), entity.ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		if _, err := processAvatar(tt.data); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestProcessAvatarStripsExif(t *testing.T) {
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// APP1 с EXIF, в котором Orientation = 6 (поворот на 90 по часовой)
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(app1)+2))
+	data := append(append(append([]byte{}, jpegData.Bytes()[:2]...), append(segment, app1...)...), jpegData.Bytes()[2:]...)
+
+	images, err := processAvatar(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if images.ContentType != "image/jpeg" {
+		t.Errorf("Expected image/jpeg, got %q", images.ContentType)
+	}
+	if bytes.Contains(images.Full, []byte("Exif")) {
+		t.Error("Expected EXIF to be stripped")
+	}
+	config, err := jpeg.DecodeConfig(bytes.NewReader(images.Full))
+	if err != nil || config.Width != 20 || config.Height != 40 {
+		t.Errorf("Expected rotated 20x40 image, got %+v (%v)", config, err)
+	}
+}
+
+func TestDownloadAvatarVariants(t *testing.T) {
+	ctx := context.Background()
+
+	var avatar *entity.Avatar
+	avatars := &MockAvatarRepository{
+		SaveFunc: func(ctx context.Context, a *entity.Avatar) (*entity.Avatar, error) {
+			avatar = a
+			return a, nil
+		},
+		GetByUserIdFunc: func(ctx context.Context, userID int) (*entity.Avatar, error) {
+			return avatar, nil
+		},
+	}
+	users := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	service := NewUserService(users, avatars, NewBlobService(storage.NewLocalStore(t.TempDir())), nil, nil, nil, &MockTransactor{})
+
+	var gifData bytes.Buffer
+	gifData.WriteString("GIF89a")
+	if _, err := service.UploadAvatar(ctx, 1, gifData.Bytes()); err != entity.ErrInvalidImage {
+		t.Errorf("Expected ErrInvalidImage, got %v", err)
+	}
+
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 30, 30))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UploadAvatar(ctx, 1, pngData.Bytes()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, contentType, err := service.DownloadAvatar(ctx, 1, 128)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if contentType != "image/png" || err != nil || config.Width != 128 {
+		t.Errorf("Expected 128px png variant, got %s %+v (%v)", contentType, config, err)
+	}
+	if _, _, err := service.DownloadAvatar(ctx, 1, 100); err != entity.ErrInvalidAvatarSize {
+		t.Errorf("Expected ErrInvalidAvatarSize, got %v", err)
+	}
+}
+
+// failingMoveStore - хранилище, в котором перенос объекта ломается после failAfter успешных
+type failingMoveStore struct {
+	storage.BlobStore
+	failAfter int
+}
+
+func (s *failingMoveStore) Move(ctx context.Context, srcKey, dstKey string) error {
+	if s.failAfter == 0 {
+		return errors.New("move failed")
+	}
+	s.failAfter--
+	return s.BlobStore.Move(ctx, srcKey, dstKey)
+}
+
+func TestUploadAvatarRollsBackFiles(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	avatars := &MockAvatarRepository{
+		SaveFunc: func(ctx context.Context, a *entity.Avatar) (*entity.Avatar, error) {
+			return a, nil
+		},
+		GetByUserIdFunc: func(ctx context.Context, userID int) (*entity.Avatar, error) {
+			return nil, nil
+		},
+	}
+	users := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	// Часть превью уже перенесена под итоговые ключи, когда перенос ломается
+	store := &failingMoveStore{BlobStore: storage.NewLocalStore(root), failAfter: 2}
+	service := NewUserService(users, avatars, NewBlobService(store), nil, nil, nil, &MockTransactor{})
+
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 300, 300))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UploadAvatar(ctx, 1, pngData.Bytes()); err == nil {
+		t.Fatal("Expected upload to fail when files cannot be moved")
+	}
+
+	var files []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if len(files) != 0 {
+		t.Errorf("Expected no files after failed upload, got %v", files)
+	}
+}
+
+// MockAvatarRepository - мок для IAvatarRepository
+type MockAvatarRepository struct {
+	SaveFunc           func(ctx context.Context, avatar *entity.Avatar) (*entity.Avatar, error)
+	GetByUserIdFunc    func(ctx context.Context, userId int) (*entity.Avatar, error)
+	DeleteByUserIdFunc func(ctx context.Context, userId int) error
+}
+
+func (m *MockAvatarRepository) Save(ctx context.Context, avatar *entity.Avatar) (*entity.Avatar, error) {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, avatar)
+	}
+	return avatar, nil
+}
+
+func (m *MockAvatarRepository) GetByUserId(ctx context.Context, userId int) (*entity.Avatar, error) {
+	if m.GetByUserIdFunc != nil {
+		return m.GetByUserIdFunc(ctx, userId)
+	}
+	return nil, nil
+}
+
+func (m *MockAvatarRepository) DeleteByUserId(ctx context.Context, userId int) error {
+	if m.DeleteByUserIdFunc != nil {
+		return m.DeleteByUserIdFunc(ctx, userId)
+	}
+	return nil
+}
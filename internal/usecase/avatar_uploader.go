@@ -142,7 +142,7 @@ func loadAvatarImages() ([][]byte, error) {
 
 // uploadUserAvatar загружает аватарку для одного пользователя
 func uploadUserAvatar(ctx context.Context, userService *UserService, userID int, imageData []byte) error {
-	_, err := userService.UploadAvatar(ctx, userID, imageData, "image/jpeg")
+	_, err := userService.UploadAvatar(ctx, userID, imageData)
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки аватарки: %w", err)
 	}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"path"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+)
+
+// BlobService - общий слой файлов для аватарок и вложений задач:
+// ограничение размера, определение типа по содержимому, запись, чтение чанками и удаление.
+// Где лежат файлы, решает BlobStore (локальный диск или S3)
+type BlobService struct {
+	store storage.BlobStore
+}
+
+func NewBlobService(store storage.BlobStore) *BlobService {
+	return &BlobService{
+		store: store,
+	}
+}
+
+// ReadAll читает файл целиком
+func (s *BlobService) ReadAll(ctx context.Context, blob *entity.Blob) ([]byte, error) {
+	reader, err := s.store.Get(ctx, blob.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// Remove удаляет файл. Ошибка только логируется: метаданные к этому моменту уже удалены.
+// Удаление не отменяется вместе с запросом, чтобы не оставлять файлы-сироты
+func (s *BlobService) Remove(ctx context.Context, blob *entity.Blob) {
+	if err := s.store.Delete(context.WithoutCancel(ctx), blob.StorageKey); err != nil {
+		log.Printf("⚠️  Не удалось удалить файл %s: %v", blob.StorageKey, err)
+	}
+}
+
+// Stream отдает содержимое файла чанками по chunkSize байт
+func (s *BlobService) Stream(ctx context.Context, blob *entity.Blob, chunkSize int) (<-chan []byte, <-chan error) {
+	dataChan := make(chan []byte)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(dataChan)
+		defer close(errChan)
+
+		if err := s.streamTo(ctx, blob, chunkSize, dataChan); err != nil {
+			errChan <- err
+		}
+	}()
+
+	return dataChan, errChan
+}
+
+// streamTo читает файл и отправляет его в dataChan чанками по chunkSize байт
+func (s *BlobService) streamTo(ctx context.Context, blob *entity.Blob, chunkSize int, dataChan chan<- []byte) error {
+	reader, err := s.store.Get(ctx, blob.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		// Каждый чанк в своем буфере: получатель может еще отправлять предыдущий
+		buffer := make([]byte, chunkSize)
+		n, err := io.ReadFull(reader, buffer)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		if n > 0 {
+			select {
+			case dataChan <- buffer[:n]:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// Open открывает файл для чтения с произвольной позиции. Размер берется из Stat,
+// содержимое не загружается в память: подходит для http.ServeContent с Range
+func (s *BlobService) Open(ctx context.Context, blob *entity.Blob) (*BlobReader, error) {
+	info, err := s.store.Stat(ctx, blob.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &BlobReader{ctx: ctx, store: s.store, key: blob.StorageKey, size: info.Size}, nil
+}
+
+// BlobReader - io.ReadSeeker поверх BlobStore. Seek только запоминает позицию, объект
+// открывается с нее при следующем Read, поэтому без чтения хранилище не трогается
+type BlobReader struct {
+	ctx    context.Context
+	store  storage.BlobStore
+	key    string
+	size   int64
+	offset int64
+	reader io.ReadCloser
+}
+
+// Size возвращает размер файла
+func (r *BlobReader) Size() int64 {
+	return r.size
+}
+
+func (r *BlobReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.reader == nil {
+		reader, err := r.store.GetRange(r.ctx, r.key, r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.reader = reader
+	}
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *BlobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("blob reader: negative position")
+	}
+	if offset != r.offset {
+		r.closeReader()
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+// Close закрывает открытый поток, повторный вызов безопасен
+func (r *BlobReader) Close() error {
+	return r.closeReader()
+}
+
+func (r *BlobReader) closeReader() error {
+	if r.reader == nil {
+		return nil
+	}
+	err := r.reader.Close()
+	r.reader = nil
+	return err
+}
+
+// newBlobKey создает уникальный ключ: несколько реплик могут сохранять файлы одновременно
+func newBlobKey(dir, prefix string) string {
+	random := make([]byte, 16)
+	rand.Read(random)
+	return path.Join(dir, prefix+"_"+hex.EncodeToString(random))
+}
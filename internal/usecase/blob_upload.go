@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// Незавершенные загрузки лежат под tmpBlobDir: если процесс упал до Commit,
+// файлы-сироты остаются только там и их можно чистить по возрасту
+const tmpBlobDir = "tmp"
+
+// sniffLen - сколько байт нужно http.DetectContentType
+const sniffLen = 512
+
+var (
+	errUploadAborted = errors.New("upload aborted")
+	errUploadClosed  = errors.New("upload is already closed")
+	errUploadPending = errors.New("upload is not closed")
+)
+
+// BlobUpload - потоковая загрузка файла. Чанки сразу пишутся во временный объект хранилища,
+// размер и SHA-256 считаются на лету, в памяти держится только начало файла для определения типа.
+// Под итоговым ключом файл появляется после Commit, который вызывают, когда запись в БД уже сделана
+type BlobUpload struct {
+	blobs   *BlobService
+	ctx     context.Context
+	tmpKey  string
+	maxSize int64
+
+	head        []byte // начало файла, пока запись во временный объект не начата
+	contentType string
+	hash        hash.Hash
+	size        int64
+
+	writer    *io.PipeWriter
+	done      chan error // результат записи временного объекта
+	closed    bool       // запись временного объекта завершена
+	err       error      // загрузка прервана, временный объект удален
+	blob      *entity.Blob
+	committed bool
+}
+
+// NewUpload начинает потоковую загрузку. Write вернет ErrFileTooLarge,
+// как только размер превысит maxSize
+func (s *BlobService) NewUpload(ctx context.Context, maxSize int64) *BlobUpload {
+	return &BlobUpload{
+		blobs:   s,
+		ctx:     ctx,
+		tmpKey:  newBlobKey(tmpBlobDir, "upload"),
+		maxSize: maxSize,
+		hash:    sha256.New(),
+	}
+}
+
+// Stage записывает data во временный объект, как если бы она пришла одним чанком
+func (s *BlobService) Stage(ctx context.Context, data []byte) (*BlobUpload, *entity.Blob, error) {
+	upload := s.NewUpload(ctx, int64(len(data)))
+	if _, err := upload.Write(data); err != nil {
+		return nil, nil, err
+	}
+	blob, err := upload.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	return upload, blob, nil
+}
+
+// Write дописывает чанк во временный объект
+func (u *BlobUpload) Write(p []byte) (int, error) {
+	if u.err != nil {
+		return 0, u.err
+	}
+	if u.closed {
+		return 0, errUploadClosed
+	}
+
+	u.size += int64(len(p))
+	if u.size > u.maxSize {
+		u.fail(entity.ErrFileTooLarge)
+		return 0, u.err
+	}
+	u.hash.Write(p)
+
+	// Тип определяется по началу файла, поэтому запись начинается, когда оно накоплено
+	if u.writer == nil {
+		u.head = append(u.head, p...)
+		if len(u.head) < sniffLen {
+			return len(p), nil
+		}
+		if err := u.start(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if _, err := u.writer.Write(p); err != nil {
+		u.fail(err)
+		return 0, u.err
+	}
+	return len(p), nil
+}
+
+// Close завершает запись временного объекта и возвращает метаданные файла.
+// StorageKey не заполнен: итоговый ключ задается при Commit
+func (u *BlobUpload) Close() (*entity.Blob, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	if u.closed {
+		return u.blob, nil
+	}
+	if u.size == 0 {
+		u.fail(entity.ErrEmptyFile)
+		return nil, u.err
+	}
+	if u.writer == nil {
+		if err := u.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	u.writer.Close()
+	err := <-u.done
+	u.closed = true
+	if err != nil {
+		u.fail(err)
+		return nil, err
+	}
+
+	u.blob = &entity.Blob{
+		FileSize:    u.size,
+		ContentType: u.contentType,
+		SHA256:      hex.EncodeToString(u.hash.Sum(nil)),
+	}
+	return u.blob, nil
+}
+
+// Open открывает на чтение записанный временный объект
+func (u *BlobUpload) Open(ctx context.Context) (io.ReadCloser, error) {
+	if u.blob == nil || u.err != nil {
+		return nil, errUploadPending
+	}
+	return u.blobs.store.Get(ctx, u.tmpKey)
+}
+
+// Commit переносит файл под итоговый ключ key
+func (u *BlobUpload) Commit(ctx context.Context, key string) error {
+	if u.blob == nil || u.err != nil {
+		return errUploadPending
+	}
+	if err := u.blobs.store.Move(ctx, u.tmpKey, key); err != nil {
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+// Abort прерывает загрузку и удаляет временный объект. После Commit ничего не делает,
+// поэтому его можно вызывать через defer
+func (u *BlobUpload) Abort() {
+	if u.committed || u.err != nil {
+		return
+	}
+	u.fail(errUploadAborted)
+}
+
+// start запускает запись временного объекта и отправляет в нее накопленное начало файла
+func (u *BlobUpload) start() error {
+	u.contentType = http.DetectContentType(u.head)
+
+	reader, writer := io.Pipe()
+	u.writer = writer
+	u.done = make(chan error, 1)
+	go func() {
+		err := u.blobs.store.Put(u.ctx, u.tmpKey, reader, -1, u.contentType)
+		// Если запись оборвалась, Write получит ошибку, а не зависнет
+		reader.CloseWithError(err)
+		u.done <- err
+	}()
+
+	head := u.head
+	u.head = nil
+	if _, err := writer.Write(head); err != nil {
+		u.fail(err)
+		return u.err
+	}
+	return nil
+}
+
+// fail прерывает запись и удаляет временный объект. Если запись оборвало хранилище,
+// клиенту возвращается его ошибка
+func (u *BlobUpload) fail(err error) {
+	if u.writer != nil && !u.closed {
+		u.writer.CloseWithError(err)
+		if putErr := <-u.done; putErr != nil && err != entity.ErrFileTooLarge && err != errUploadAborted {
+			err = putErr
+		}
+		u.closed = true
+	}
+	u.err = err
+	if u.writer != nil {
+		u.blobs.Remove(u.ctx, &entity.Blob{StorageKey: u.tmpKey})
+	}
+}
+
+// readUpload читает записанный временный объект целиком. Размер уже ограничен при загрузке
+func readUpload(ctx context.Context, upload *BlobUpload) ([]byte, error) {
+	reader, err := upload.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// commitUploads переносит загрузки под их итоговые ключи
+func commitUploads(ctx context.Context, uploads map[string]*BlobUpload) error {
+	for key, upload := range uploads {
+		if err := upload.Commit(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeCommittedUploads удаляет файлы загрузок, уже перенесенных под итоговые ключи,
+// когда запись о них в БД не сохранилась
+func removeCommittedUploads(ctx context.Context, blobs *BlobService, uploads map[string]*BlobUpload) {
+	for key, upload := range uploads {
+		if upload.committed {
+			blobs.Remove(ctx, &entity.Blob{StorageKey: key})
+		}
+	}
+}
+
+// abortUploads удаляет временные объекты загрузок, которые еще не перенесены
+func abortUploads(uploads map[string]*BlobUpload) {
+	for _, upload := range uploads {
+		upload.Abort()
+	}
+}
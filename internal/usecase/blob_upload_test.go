@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+)
+
+func TestBlobUploadStreamsAndCommits(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store := storage.NewLocalStore(root)
+	blobs := NewBlobService(store)
+
+	countFiles := func() int {
+		count := 0
+		filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				count++
+			}
+			return nil
+		})
+		return count
+	}
+
+	// Лимит превышен посреди потока: ошибка сразу, временный объект удален
+	upload := blobs.NewUpload(ctx, 1000)
+	chunk := bytes.Repeat([]byte("a"), 600)
+	if _, err := upload.Write(chunk); err != nil {
+		t.Fatalf("Expected first chunk to be accepted, got %v", err)
+	}
+	if _, err := upload.Write(chunk); err != entity.ErrFileTooLarge {
+		t.Errorf("Expected ErrFileTooLarge, got %v", err)
+	}
+	if _, err := upload.Close(); err != entity.ErrFileTooLarge {
+		t.Errorf("Expected Close to report ErrFileTooLarge, got %v", err)
+	}
+	if n := countFiles(); n != 0 {
+		t.Errorf("Expected no files after aborted upload, got %d", n)
+	}
+
+	// Успешная загрузка: файл виден под итоговым ключом только после Commit
+	data := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte("b"), 2000)...)
+	upload = blobs.NewUpload(ctx, int64(len(data)))
+	defer upload.Abort()
+	for i := 0; i < len(data); i += 300 {
+		if _, err := upload.Write(data[i:min(i+300, len(data))]); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	blob, err := upload.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	checksum := sha256.Sum256(data)
+	if blob.FileSize != int64(len(data)) || blob.ContentType != "image/png" || blob.SHA256 != hex.EncodeToString(checksum[:]) {
+		t.Errorf("Unexpected blob metadata %+v", blob)
+	}
+	if _, err := store.Stat(ctx, "attachments/a"); err != storage.ErrNotFound {
+		t.Errorf("Expected no object before Commit, got %v", err)
+	}
+
+	if err := upload.Commit(ctx, "attachments/a"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	upload.Abort()
+	if info, err := store.Stat(ctx, "attachments/a"); err != nil || info.Size != int64(len(data)) {
+		t.Errorf("Expected committed object, got %+v (%v)", info, err)
+	}
+	if n := countFiles(); n != 1 {
+		t.Errorf("Expected only the committed file, got %d files", n)
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
+)
+
+const (
+	// emailVerificationTTL - сколько действует ссылка подтверждения email
+	emailVerificationTTL = 24 * time.Hour
+	// resendVerificationInterval - не чаще одного письма за этот интервал
+	resendVerificationInterval = time.Minute
+	// resendVerificationHourlyLimit - не больше писем за час, считая письмо при регистрации
+	resendVerificationHourlyLimit = 5
+)
+
+// SetEmailVerificationURL задает страницу подтверждения email, токен добавляется к ней
+// параметром token. Без нее в письме только сам токен
+func (s *AuthService) SetEmailVerificationURL(verificationURL string) error {
+	if _, err := url.Parse(verificationURL); err != nil {
+		return fmt.Errorf("invalid email verification url: %w", err)
+	}
+	s.emailVerificationURL = verificationURL
+	return nil
+}
+
+// VerifyEmail подтверждает email по токену из письма. Токен подтверждает адрес, на который
+// отправлен: если email с тех пор сменился, токен не подходит. Ограничения неподтвержденного
+// аккаунта снимаются со следующим access token - после RefreshToken или нового входа
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	verification, err := s.emailVerificationRepo.Consume(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume verification token: %w", err)
+	}
+	if verification == nil {
+		return nil, entity.ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetById(ctx, verification.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Email == nil || *user.Email != verification.Email {
+		return nil, entity.ErrInvalidVerificationToken
+	}
+	if user.EmailVerified() {
+		return user, nil
+	}
+
+	user, err = s.userRepo.Update(ctx, user.ID, map[string]interface{}{"email_verified_at": time.Now()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	if user == nil {
+		return nil, entity.ErrInvalidVerificationToken
+	}
+	return user, nil
+}
+
+// ResendVerification повторно отправляет письмо подтверждения. Не чаще раза в минуту
+// и не больше resendVerificationHourlyLimit писем в час, иначе ErrTooManyRequests
+func (s *AuthService) ResendVerification(ctx context.Context, userID int) error {
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return entity.ErrUserNotFound
+	}
+	if user.Email == nil {
+		return entity.ErrInvalidUserData
+	}
+	if user.EmailVerified() {
+		return entity.ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	recent, err := s.emailVerificationRepo.CountSince(ctx, userID, now.Add(-resendVerificationInterval))
+	if err != nil {
+		return fmt.Errorf("failed to count verification emails: %w", err)
+	}
+	hourly, err := s.emailVerificationRepo.CountSince(ctx, userID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count verification emails: %w", err)
+	}
+	if recent > 0 || hourly >= resendVerificationHourlyLimit {
+		return entity.ErrTooManyRequests
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// sendVerification выдает новый токен подтверждения, гасит прежние и отправляет письмо
+func (s *AuthService) sendVerification(ctx context.Context, user *entity.User) error {
+	if user.Email == nil {
+		return entity.ErrInvalidUserData
+	}
+
+	token, err := newMailToken()
+	if err != nil {
+		return err
+	}
+	if err := s.emailVerificationRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+	if err := s.emailVerificationRepo.Create(ctx, user.ID, *user.Email, hashToken(token), time.Now().Add(emailVerificationTTL)); err != nil {
+		return fmt.Errorf("failed to save verification token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      *user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Чтобы подтвердить адрес, воспользуйтесь ссылкой в течение %d часов:\n%s\n\n"+
+			"Если вы не регистрировались, просто проигнорируйте письмо.\n",
+			user.Name, int(emailVerificationTTL.Hours()), mailLink(s.emailVerificationURL, token)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
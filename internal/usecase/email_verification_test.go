@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockEmailVerificationRepository - мок для IEmailVerificationRepository, хранит токены в памяти
+type MockEmailVerificationRepository struct {
+	tokens []*mockVerificationToken
+}
+
+type mockVerificationToken struct {
+	repository.EmailVerificationToken
+	hash      string
+	expiresAt time.Time
+	createdAt time.Time
+	used      bool
+}
+
+var _ repository.IEmailVerificationRepository = (*MockEmailVerificationRepository)(nil)
+
+func (m *MockEmailVerificationRepository) Create(ctx context.Context, userID int, email, tokenHash string, expiresAt time.Time) error {
+	m.tokens = append(m.tokens, &mockVerificationToken{
+		EmailVerificationToken: repository.EmailVerificationToken{UserID: userID, Email: email},
+		hash:                   tokenHash,
+		expiresAt:              expiresAt,
+		createdAt:              time.Now(),
+	})
+	return nil
+}
+
+func (m *MockEmailVerificationRepository) Consume(ctx context.Context, tokenHash string) (*repository.EmailVerificationToken, error) {
+	for _, token := range m.tokens {
+		if token.hash == tokenHash && !token.used && time.Now().Before(token.expiresAt) {
+			token.used = true
+			found := token.EmailVerificationToken
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockEmailVerificationRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			token.used = true
+		}
+	}
+	return nil
+}
+
+func (m *MockEmailVerificationRepository) CountSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	count := 0
+	for _, token := range m.tokens {
+		if token.UserID == userID && !token.createdAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	ctx := context.Background()
+	var user *entity.User
+	userRepo := &MockUserRepository{
+		CreateWithAuthFunc: func(ctx context.Context, name, email, passwordHash string) (*entity.User, error) {
+			user = &entity.User{ID: 1, Name: name, Email: &email, PasswordHash: passwordHash, IsActive: true}
+			return user, nil
+		},
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return user, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error) {
+			if verifiedAt, ok := updates["email_verified_at"].(time.Time); ok {
+				user.EmailVerifiedAt = &verifiedAt
+			}
+			return user, nil
+		},
+	}
+	service := newTestAuthService(userRepo)
+	verifications, mails, jwtManager := service.verifications, service.mails, service.jwtManager
+
+	// Регистрация отправляет письмо, access token помечен как неподтвержденный
+	resp, err := service.Register(ctx, &entity.RegisterRequest{Name: "Ann", Email: "ann@example.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if claims, err := jwtManager.ValidateAccessToken(resp.AccessToken); err != nil || claims.EmailVerified == nil || *claims.EmailVerified {
+		t.Errorf("Expected unverified access token, got %+v (%v)", claims, err)
+	}
+	if len(mails.Sent) != 1 || mails.Sent[0].To != "ann@example.com" {
+		t.Fatalf("Expected verification email, got %+v", mails.Sent)
+	}
+	tokenPattern := regexp.MustCompile(`[0-9a-f]{64}`)
+	token := tokenPattern.FindString(mails.Sent[0].Body)
+
+	// Повторная отправка сразу после регистрации упирается в лимит
+	if err := service.ResendVerification(ctx, 1); err != entity.ErrTooManyRequests {
+		t.Errorf("Expected ErrTooManyRequests, got %v", err)
+	}
+	verifications.tokens[0].createdAt = time.Now().Add(-2 * time.Minute)
+	if err := service.ResendVerification(ctx, 1); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+
+	// Письмо с новым токеном гасит прежний
+	if _, err := service.VerifyEmail(ctx, token); err != entity.ErrInvalidVerificationToken {
+		t.Errorf("Expected superseded token to be rejected, got %v", err)
+	}
+	token = tokenPattern.FindString(mails.Sent[1].Body)
+	verified, err := service.VerifyEmail(ctx, token)
+	if err != nil || !verified.EmailVerified() {
+		t.Fatalf("Expected email to be verified, got %v", err)
+	}
+	if _, err := service.VerifyEmail(ctx, token); err != entity.ErrInvalidVerificationToken {
+		t.Errorf("Expected used token to be rejected, got %v", err)
+	}
+	if err := service.ResendVerification(ctx, 1); err != entity.ErrEmailAlreadyVerified {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+
+	// Токен для старого адреса не подтверждает новый
+	user.EmailVerifiedAt = nil
+	verifications.tokens = nil
+	if err := service.sendVerification(ctx, user); err != nil {
+		t.Fatalf("sendVerification: %v", err)
+	}
+	token = tokenPattern.FindString(mails.Sent[2].Body)
+	changed := "other@example.com"
+	user.Email = &changed
+	if _, err := service.VerifyEmail(ctx, token); err != entity.ErrInvalidVerificationToken {
+		t.Errorf("Expected token for old address to be rejected, got %v", err)
+	}
+}
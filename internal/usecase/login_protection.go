@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// maxEmailLength - длина колонки email
+const maxEmailLength = 255
+
+var (
+	// DefaultAccountLoginPolicy - на один email: 3 попытки свободно, затем задержка 1s, 2s, 4s...
+	// до 30s, после 10 неудач - блокировка на 15 минут
+	DefaultAccountLoginPolicy = entity.LoginThrottlePolicy{
+		FreeAttempts:    3,
+		LockoutAttempts: 10,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+		LockoutDuration: 15 * time.Minute,
+		Window:          15 * time.Minute,
+	}
+	// DefaultIPLoginPolicy - с одного IP мягче: за одним адресом может быть целая сеть (NAT)
+	DefaultIPLoginPolicy = entity.LoginThrottlePolicy{
+		FreeAttempts:    20,
+		LockoutAttempts: 100,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+		LockoutDuration: 15 * time.Minute,
+		Window:          15 * time.Minute,
+	}
+)
+
+// loginKey - ключ счетчика неудачных входов и его политика
+type loginKey struct {
+	key       string
+	policy    entity.LoginThrottlePolicy
+	lockEvent entity.AuthEventType
+}
+
+// emailKey - ключ счетчика для email: адрес без пробелов и в нижнем регистре
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// loginKeys возвращает ключи попытки: сначала аккаунт, затем IP, если он известен
+func (s *AuthService) loginKeys(req *entity.LoginRequest) []loginKey {
+	keys := []loginKey{{
+		key:       emailKey(req.Email),
+		policy:    s.accountLoginPolicy,
+		lockEvent: entity.AuthEventAccountLocked,
+	}}
+	if req.Client.IPAddress != "" {
+		keys = append(keys, loginKey{
+			key:       "ip:" + req.Client.IPAddress,
+			policy:    s.ipLoginPolicy,
+			lockEvent: entity.AuthEventIPLocked,
+		})
+	}
+	return keys
+}
+
+// loginReservation - попытка входа, заранее учтенная по ключу как неудачная
+type loginReservation struct {
+	loginKey
+	failures     int
+	blockedUntil *time.Time // блокировка, выставленная этой попыткой
+}
+
+// reserveLogin до проверки пароля учитывает попытку как неудачную по всем ключам и сразу
+// закрывает вход на задержку, положенную после такой неудачи. Все это - одна транзакция:
+// строки счетчиков заблокированы, поэтому параллельные попытки проходят по очереди и не могут
+// проскочить мимо задержки. Если вход закрыт, возвращает LoginThrottledError, учет откатывается
+func (s *AuthService) reserveLogin(ctx context.Context, keys []loginKey) ([]loginReservation, error) {
+	now := time.Now()
+	var reservations []loginReservation
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		reservations = reservations[:0]
+		for _, k := range keys {
+			attempts, err := s.loginAttemptRepo.Reserve(ctx, k.key, now.Add(-k.policy.Window))
+			if err != nil {
+				return fmt.Errorf("failed to reserve login attempt: %w", err)
+			}
+			if attempts.BlockedUntil != nil && attempts.BlockedUntil.After(now) {
+				return &entity.LoginThrottledError{RetryAfter: attempts.BlockedUntil.Sub(now)}
+			}
+
+			reservation := loginReservation{loginKey: k, failures: attempts.Failures}
+			if block := k.policy.BlockFor(attempts.Failures); block > 0 {
+				until := now.Add(block)
+				if err := s.loginAttemptRepo.Block(ctx, k.key, until); err != nil {
+					return fmt.Errorf("failed to block login: %w", err)
+				}
+				reservation.blockedUntil = &until
+			}
+			reservations = append(reservations, reservation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// releaseLogin отменяет учет успешной попытки: счетчик аккаунта сбрасывается, а с IP снимается
+// только эта попытка - иначе перебор можно перемежать входами в свой аккаунт
+func (s *AuthService) releaseLogin(ctx context.Context, reservations []loginReservation) error {
+	for i, r := range reservations {
+		var err error
+		if i == 0 {
+			err = s.loginAttemptRepo.Reset(ctx, r.key)
+		} else {
+			err = s.loginAttemptRepo.Release(ctx, r.key, r.blockedUntil)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+	return nil
+}
+
+// loginFailed фиксирует неудачу в журнале, отмечает блокировки и возвращает единую ошибку
+// входа. Сама неудача и задержка уже учтены в reserveLogin
+func (s *AuthService) loginFailed(ctx context.Context, reservations []loginReservation, userID *int, req *entity.LoginRequest, reason string) error {
+	s.auditLogin(ctx, entity.AuthEventLoginFailed, userID, req, reason)
+
+	for _, r := range reservations {
+		if r.policy.IsLockout(r.failures) {
+			details := fmt.Sprintf("%s locked for %s after %d failed attempts", r.key, r.policy.BlockFor(r.failures), r.failures)
+			log.Printf("🔒 Вход заблокирован: %s", details)
+			s.auditLogin(ctx, r.lockEvent, userID, req, details)
+		}
+	}
+
+	return entity.ErrInvalidCredentials
+}
+
+// auditLogin пишет событие в журнал входов. Сбой журнала не мешает входу
+func (s *AuthService) auditLogin(ctx context.Context, eventType entity.AuthEventType, userID *int, req *entity.LoginRequest, details string) {
+	event := &entity.AuthEvent{
+		Type:      eventType,
+		UserID:    userID,
+		Email:     req.Email,
+		IPAddress: req.Client.IPAddress,
+		UserAgent: req.Client.UserAgent,
+		Details:   details,
+	}
+	if err := s.authAuditRepo.Create(ctx, event); err != nil {
+		log.Printf("⚠️  Не удалось записать событие входа %s: %v", eventType, err)
+	}
+}
+
+// dummyPasswordHash - хеш для проверки пароля неизвестного пользователя, считается один раз
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.passwordManager.HashPassword("dummy-password-for-timing")
+	})
+	return s.dummyHash
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockLoginAttemptRepository - мок для ILoginAttemptRepository, хранит счетчики в памяти
+type MockLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*repository.LoginAttempts
+}
+
+var _ repository.ILoginAttemptRepository = (*MockLoginAttemptRepository)(nil)
+
+func (m *MockLoginAttemptRepository) Reserve(ctx context.Context, key string, windowStart time.Time) (*repository.LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.attempts == nil {
+		m.attempts = make(map[string]*repository.LoginAttempts)
+	}
+	attempts, ok := m.attempts[key]
+	switch {
+	case ok && attempts.BlockedUntil != nil && attempts.BlockedUntil.After(time.Now()):
+		found := *attempts
+		return &found, nil
+	case !ok || attempts.LastFailureAt.Before(windowStart):
+		attempts = &repository.LoginAttempts{Key: key}
+		m.attempts[key] = attempts
+	}
+	attempts.Failures++
+	attempts.LastFailureAt = time.Now()
+	found := *attempts
+	return &found, nil
+}
+
+func (m *MockLoginAttemptRepository) Release(ctx context.Context, key string, blockedUntil *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempts, ok := m.attempts[key]; ok {
+		attempts.Failures = max(attempts.Failures-1, 0)
+		if blockedUntil != nil && attempts.BlockedUntil != nil && attempts.BlockedUntil.Equal(*blockedUntil) {
+			attempts.BlockedUntil = nil
+		}
+	}
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) Block(ctx context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempts, ok := m.attempts[key]; ok {
+		attempts.BlockedUntil = &until
+	}
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+// MockAuthAuditRepository - мок для IAuthAuditRepository, запоминает события
+type MockAuthAuditRepository struct {
+	mu     sync.Mutex
+	Events []entity.AuthEvent
+}
+
+func (m *MockAuthAuditRepository) Create(ctx context.Context, event *entity.AuthEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Events = append(m.Events, *event)
+	return nil
+}
+
+// lockingTransactor - транзакции выполняются по одной, как при блокировке строк счетчиков в БД
+type lockingTransactor struct {
+	mu sync.Mutex
+}
+
+func (t *lockingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return fn(ctx)
+}
+
+func TestLoginThrottlePolicyBlockFor(t *testing.T) {
+	policy := DefaultAccountLoginPolicy
+	expected := map[int]time.Duration{
+		1: 0, 2: 0, 3: time.Second, 4: 2 * time.Second, 5: 4 * time.Second,
+		8: 30 * time.Second, 9: 30 * time.Second, 10: 15 * time.Minute, 12: 15 * time.Minute,
+	}
+	for failures, want := range expected {
+		if got := policy.BlockFor(failures); got != want {
+			t.Errorf("BlockFor(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestLoginBruteForceProtection(t *testing.T) {
+	ctx := context.Background()
+	passwords := auth.NewPasswordManager()
+	hash, err := passwords.HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	users := map[string]*entity.User{
+		"ann@example.com": {ID: 1, PasswordHash: hash, IsActive: true},
+		"bob@example.com": {ID: 2, PasswordHash: hash, IsActive: false},
+	}
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*entity.User, error) {
+			return users[email], nil
+		},
+	}
+	service := newTestAuthService(userRepo)
+	attempts, audit := service.attempts, service.audit
+	service.accountLoginPolicy = entity.LoginThrottlePolicy{
+		FreeAttempts: 2, LockoutAttempts: 4, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute,
+		LockoutDuration: time.Hour, Window: time.Hour,
+	}
+	client := entity.ClientInfo{IPAddress: "203.0.113.9"}
+	login := func(email, password string) error {
+		_, err := service.Login(ctx, &entity.LoginRequest{Email: email, Password: password, Client: client})
+		return err
+	}
+	unblock := func(key string) {
+		attempts.attempts[key].BlockedUntil = nil
+	}
+
+	// Неизвестный email, неверный пароль и неактивный аккаунт неотличимы
+	for _, email := range []string{"nobody@example.com", "bob@example.com"} {
+		if err := login(email, "secret"); err != entity.ErrInvalidCredentials {
+			t.Errorf("Expected ErrInvalidCredentials for %s, got %v", email, err)
+		}
+	}
+
+	// Две попытки свободно, дальше вход закрыт на задержку
+	for i := 0; i < 2; i++ {
+		if err := login("ann@example.com", "wrong"); err != entity.ErrInvalidCredentials {
+			t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+	err = login("ann@example.com", "secret")
+	var throttled *entity.LoginThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, entity.ErrLoginThrottled) || throttled.RetryAfter <= 0 || throttled.RetryAfter > time.Minute {
+		t.Fatalf("Expected LoginThrottledError within a minute, got %v", err)
+	}
+
+	// Задержка растет, на четвертой неудаче - блокировка и запись в журнал
+	unblock("email:ann@example.com")
+	login("ann@example.com", "wrong")
+	if until := attempts.attempts["email:ann@example.com"].BlockedUntil; until == nil || time.Until(*until) <= time.Minute {
+		t.Errorf("Expected progressive delay, got %v", until)
+	}
+	unblock("email:ann@example.com")
+	login("ann@example.com", "wrong")
+	if until := attempts.attempts["email:ann@example.com"].BlockedUntil; until == nil || time.Until(*until) <= 5*time.Minute {
+		t.Errorf("Expected lockout, got %v", until)
+	}
+	locked := false
+	for _, event := range audit.Events {
+		if event.Type == entity.AuthEventAccountLocked && event.UserID != nil && *event.UserID == 1 {
+			locked = true
+		}
+	}
+	if !locked {
+		t.Error("Expected account_locked audit event")
+	}
+
+	// Успешный вход сбрасывает счетчик аккаунта, но не IP
+	unblock("email:ann@example.com")
+	if err := login("ann@example.com", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, ok := attempts.attempts["email:ann@example.com"]; ok {
+		t.Error("Expected account counter to be reset after successful login")
+	}
+	if ip := attempts.attempts["ip:203.0.113.9"]; ip == nil || ip.Failures != 6 {
+		t.Errorf("Expected 6 failures counted for IP, got %+v", ip)
+	}
+	if last := audit.Events[len(audit.Events)-1]; last.Type != entity.AuthEventLoginSucceeded || last.IPAddress != client.IPAddress {
+		t.Errorf("Expected login_succeeded audit event, got %+v", last)
+	}
+}
+
+func TestLoginConcurrentAttemptsRespectThrottle(t *testing.T) {
+	ctx := context.Background()
+	passwords := auth.NewPasswordManager()
+	hash, err := passwords.HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	var checked atomic.Int32
+	service := newTestAuthService(&MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*entity.User, error) {
+			checked.Add(1)
+			return &entity.User{ID: 1, PasswordHash: hash, IsActive: true}, nil
+		},
+	})
+	service.transactor = &lockingTransactor{}
+	service.accountLoginPolicy = entity.LoginThrottlePolicy{
+		FreeAttempts: 3, LockoutAttempts: 10, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute,
+		LockoutDuration: time.Hour, Window: time.Hour,
+	}
+
+	// Все попытки стартуют разом: мимо задержки не проходит ни одна лишняя
+	const parallel = 20
+	var wg sync.WaitGroup
+	var throttled atomic.Int32
+	start := make(chan struct{})
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := service.Login(ctx, &entity.LoginRequest{Email: "ann@example.com", Password: "wrong"})
+			if errors.Is(err, entity.ErrLoginThrottled) {
+				throttled.Add(1)
+			} else if err != entity.ErrInvalidCredentials {
+				t.Errorf("Expected ErrInvalidCredentials or throttling, got %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := checked.Load(); n != 3 {
+		t.Errorf("Expected exactly 3 password checks, got %d", n)
+	}
+	if n := throttled.Load(); n != parallel-3 {
+		t.Errorf("Expected %d throttled attempts, got %d", parallel-3, n)
+	}
+	if attempts := service.attempts.attempts["email:ann@example.com"]; attempts == nil || attempts.Failures != 3 {
+		t.Errorf("Expected 3 failures counted, got %+v", attempts)
+	}
+}
+
+func TestLoginSuccessReleasesIPReservation(t *testing.T) {
+	ctx := context.Background()
+	passwords := auth.NewPasswordManager()
+	hash, err := passwords.HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	service := newTestAuthService(&MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*entity.User, error) {
+			return &entity.User{ID: 1, PasswordHash: hash, IsActive: true}, nil
+		},
+	})
+	service.ipLoginPolicy = entity.LoginThrottlePolicy{
+		FreeAttempts: 2, LockoutAttempts: 10, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute,
+		LockoutDuration: time.Hour, Window: time.Hour,
+	}
+	client := entity.ClientInfo{IPAddress: "203.0.113.9"}
+	login := func(email, password string) error {
+		_, err := service.Login(ctx, &entity.LoginRequest{Email: email, Password: password, Client: client})
+		return err
+	}
+
+	// Одна неудача с IP, затем удачный вход: его попытка снимается, задержка с IP тоже
+	if err := login("bob@example.com", "wrong"); err != entity.ErrInvalidCredentials {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if err := login("ann@example.com", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	ip := service.attempts.attempts["ip:203.0.113.9"]
+	if ip == nil || ip.Failures != 1 || ip.BlockedUntil != nil {
+		t.Errorf("Expected 1 failure and no block for IP, got %+v", ip)
+	}
+	if err := login("ann@example.com", "secret"); err != nil {
+		t.Errorf("Expected repeated successful login from the same IP, got %v", err)
+	}
+}
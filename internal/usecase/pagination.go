@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"slices"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// normalizePage проверяет параметры страницы и подставляет значения по умолчанию.
+// Размер страницы больше MaxPageSize урезается до MaxPageSize
+func normalizePage(page *entity.PageRequest, defaultSortBy string, sortFields []string) error {
+	if page.PageSize <= 0 {
+		page.PageSize = DefaultPageSize
+	}
+	if page.PageSize > MaxPageSize {
+		page.PageSize = MaxPageSize
+	}
+
+	if page.SortBy == "" {
+		page.SortBy = defaultSortBy
+	}
+	if !slices.Contains(sortFields, page.SortBy) {
+		return entity.ErrInvalidSort
+	}
+
+	switch page.SortDir {
+	case "":
+		page.SortDir = entity.SortDesc
+	case entity.SortAsc, entity.SortDesc:
+	default:
+		return entity.ErrInvalidSort
+	}
+
+	switch page.Total {
+	case entity.TotalNone, entity.TotalExact, entity.TotalEstimated:
+	default:
+		return entity.ErrInvalidTotalMode
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestListTasksNormalizesPage(t *testing.T) {
+	ctx := context.Background()
+
+	var got *entity.ListTasksRequest
+	mockTaskRepo := &MockTaskRepository{
+		ListFunc: func(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+			got = req
+			return &entity.TaskPage{}, nil
+		},
+	}
+
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	_, err := service.ListTasks(ctx, 7, &entity.ListTasksRequest{
+		UserID: 999, // должен быть заменен на вызывающего пользователя
+		Page:   entity.PageRequest{PageSize: 100000},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got.UserID != 7 || got.Mode != entity.TaskListOwned {
+		t.Errorf("Expected owned tasks of user 7, got %d %s", got.UserID, got.Mode)
+	}
+	if got.Page.PageSize != MaxPageSize {
+		t.Errorf("Expected page size %d, got %d", MaxPageSize, got.Page.PageSize)
+	}
+	if got.Page.SortBy != entity.TaskSortCreatedAt || got.Page.SortDir != entity.SortDesc {
+		t.Errorf("Expected default sort created_at desc, got %s %s", got.Page.SortBy, got.Page.SortDir)
+	}
+}
+
+func TestListTasksInvalidSort(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTaskService(taskServiceDeps{})
+
+	tests := []entity.PageRequest{
+		{SortBy: "password_hash"},
+		{SortDir: "sideways"},
+		{Total: "approximately"},
+	}
+
+	for _, page := range tests {
+		_, err := service.ListTasks(ctx, 1, &entity.ListTasksRequest{Page: page})
+		if err != entity.ErrInvalidSort && err != entity.ErrInvalidTotalMode {
+			t.Errorf("Expected validation error for %+v, got %v", page, err)
+		}
+	}
+}
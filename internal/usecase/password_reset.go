@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
+)
+
+const (
+	// passwordResetTTL - сколько действует ссылка сброса пароля
+	passwordResetTTL = time.Hour
+	// passwordResetInterval - не чаще одного запроса на email за этот интервал
+	passwordResetInterval = time.Minute
+	// passwordResetHourlyLimit - не больше запросов на один email в час
+	passwordResetHourlyLimit = 5
+	// passwordResetIPHourlyLimit - не больше запросов с одного IP в час: за адресом может быть NAT
+	passwordResetIPHourlyLimit = 20
+)
+
+// SetPasswordResetURL задает страницу сброса пароля, токен добавляется к ней параметром token.
+// Без нее в письме только сам токен
+func (s *AuthService) SetPasswordResetURL(resetURL string) error {
+	if _, err := url.Parse(resetURL); err != nil {
+		return fmt.Errorf("invalid password reset url: %w", err)
+	}
+	s.passwordResetURL = resetURL
+	return nil
+}
+
+// RequestPasswordReset отправляет письмо со ссылкой сброса пароля. Для неизвестного
+// или неактивного email ничего не делает и не возвращает ошибку - ни по ответу, ни по его
+// времени нельзя узнать, зарегистрирован ли адрес: письмо уходит в фоне, сбой отправки
+// только логируется, а лимит запросов (ErrTooManyRequests) одинаков для любых адресов.
+// Предыдущие ссылки пользователя перестают действовать
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string, client entity.ClientInfo) error {
+	if err := s.checkPasswordResetLimit(ctx, email, client); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !user.IsActive || user.Email == nil {
+		return nil
+	}
+
+	token, err := newMailToken()
+	if err != nil {
+		return err
+	}
+
+	// Гашение старых ссылок и новая ссылка - одна транзакция: параллельные запросы
+	// не оставят двух действующих токенов
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.passwordResetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+		}
+		// В БД только хеш: утечка таблицы не дает сбросить пароль
+		if err := s.passwordResetRepo.Create(ctx, user.ID, hashToken(token), time.Now().Add(passwordResetTTL)); err != nil {
+			return fmt.Errorf("failed to save reset token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sendMailAsync(ctx, user.ID, s.passwordResetMessage(*user.Email, token))
+	return nil
+}
+
+// checkPasswordResetLimit учитывает запрос и проверяет лимиты на email и IP. Запрос
+// записывается до подсчета, поэтому параллельные запросы видят друг друга, а отклоненные
+// запросы тоже расходуют лимит
+func (s *AuthService) checkPasswordResetLimit(ctx context.Context, email string, client entity.ClientInfo) error {
+	now := time.Now()
+
+	emailKey := emailKey(email)
+	if err := s.passwordResetRepo.RecordRequest(ctx, emailKey); err != nil {
+		return fmt.Errorf("failed to record reset request: %w", err)
+	}
+	recent, err := s.passwordResetRepo.CountRequestsSince(ctx, emailKey, now.Add(-passwordResetInterval))
+	if err != nil {
+		return fmt.Errorf("failed to count reset requests: %w", err)
+	}
+	hourly, err := s.passwordResetRepo.CountRequestsSince(ctx, emailKey, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count reset requests: %w", err)
+	}
+	if recent > 1 || hourly > passwordResetHourlyLimit {
+		return entity.ErrTooManyRequests
+	}
+
+	if client.IPAddress == "" {
+		return nil
+	}
+	ipKey := "ip:" + client.IPAddress
+	if err := s.passwordResetRepo.RecordRequest(ctx, ipKey); err != nil {
+		return fmt.Errorf("failed to record reset request: %w", err)
+	}
+	fromIP, err := s.passwordResetRepo.CountRequestsSince(ctx, ipKey, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to count reset requests: %w", err)
+	}
+	if fromIP > passwordResetIPHourlyLimit {
+		return entity.ErrTooManyRequests
+	}
+	return nil
+}
+
+// passwordResetMessage собирает письмо со ссылкой или токеном сброса
+func (s *AuthService) passwordResetMessage(email, token string) mailer.Message {
+	return mailer.Message{
+		To:      email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Кто-то запросил сброс пароля для вашего аккаунта.\n\n"+
+			"Чтобы задать новый пароль, воспользуйтесь ссылкой в течение %d минут:\n%s\n\n"+
+			"Если это были не вы, просто проигнорируйте письмо.\n",
+			int(passwordResetTTL.Minutes()), mailLink(s.passwordResetURL, token)),
+	}
+}
+
+// ConfirmPasswordReset задает новый пароль по токену из письма. Токен срабатывает один раз,
+// после смены пароля все сессии пользователя завершаются
+func (s *AuthService) ConfirmPasswordReset(ctx context.Context, token, newPassword string) error {
+	// Неподходящий пароль не должен сжигать токен: ссылку можно открыть еще раз
+	if !isValidPassword(newPassword) {
+		return entity.ErrInvalidPassword
+	}
+
+	passwordHash, err := s.passwordManager.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Токен гасится вместе со сменой пароля и завершением сессий: если что-то не удалось,
+	// токен остается действующим и ссылку можно открыть повторно
+	var userID int
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		userID, err = s.passwordResetRepo.Consume(ctx, hashToken(token))
+		if err != nil {
+			return fmt.Errorf("failed to consume reset token: %w", err)
+		}
+		if userID == 0 {
+			return entity.ErrInvalidResetToken
+		}
+
+		user, err := s.userRepo.Update(ctx, userID, map[string]interface{}{"password_hash": passwordHash})
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		if user == nil {
+			return entity.ErrInvalidResetToken
+		}
+
+		// Старый пароль мог быть украден вместе с сессиями
+		if err := s.refreshTokenRepo.RevokeAll(ctx, userID); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		if err := s.passwordResetRepo.InvalidateForUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔑 Пароль пользователя %d сброшен, сессии завершены", userID)
+	return nil
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/mailer"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockPasswordResetRepository - мок для IPasswordResetRepository, хранит токены в памяти
+type MockPasswordResetRepository struct {
+	tokens   map[string]*mockResetToken
+	requests map[string][]time.Time
+}
+
+type mockResetToken struct {
+	userID    int
+	expiresAt time.Time
+	used      bool
+}
+
+var _ repository.IPasswordResetRepository = (*MockPasswordResetRepository)(nil)
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	if m.tokens == nil {
+		m.tokens = make(map[string]*mockResetToken)
+	}
+	m.tokens[tokenHash] = &mockResetToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (int, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok || token.used || time.Now().After(token.expiresAt) {
+		return 0, nil
+	}
+	token.used = true
+	return token.userID, nil
+}
+
+func (m *MockPasswordResetRepository) InvalidateForUser(ctx context.Context, userID int) error {
+	for _, token := range m.tokens {
+		if token.userID == userID {
+			token.used = true
+		}
+	}
+	return nil
+}
+
+func (m *MockPasswordResetRepository) RecordRequest(ctx context.Context, key string) error {
+	if m.requests == nil {
+		m.requests = make(map[string][]time.Time)
+	}
+	m.requests[key] = append(m.requests[key], time.Now())
+	return nil
+}
+
+func (m *MockPasswordResetRepository) CountRequestsSince(ctx context.Context, key string, since time.Time) (int, error) {
+	count := 0
+	for _, at := range m.requests[key] {
+		if !at.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// age сдвигает все запросы в прошлое, имитируя прошедшее время
+func (m *MockPasswordResetRepository) age(d time.Duration) {
+	for key, times := range m.requests {
+		for i := range times {
+			m.requests[key][i] = times[i].Add(-d)
+		}
+	}
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	ctx := context.Background()
+	email := "a@example.com"
+	user := &entity.User{ID: 1, Email: &email, IsActive: true}
+	var newHash string
+	userRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return user, nil
+		},
+		GetByEmailFunc: func(ctx context.Context, e string) (*entity.User, error) {
+			if e == email {
+				return user, nil
+			}
+			return nil, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error) {
+			newHash, _ = updates["password_hash"].(string)
+			return user, nil
+		},
+	}
+	service := newTestAuthService(userRepo)
+	refreshTokens, resets, mails, passwords := service.refreshTokens, service.resets, service.mails, service.passwordManager
+	if err := service.SetPasswordResetURL("https://app.example.com/reset?lang=ru"); err != nil {
+		t.Fatalf("SetPasswordResetURL: %v", err)
+	}
+	sessionToken, _ := issueRefreshToken(ctx, service.jwtManager, refreshTokens, 1, email, entity.ClientInfo{}, nil)
+
+	// Неизвестный адрес - без ошибки и без письма
+	if err := service.RequestPasswordReset(ctx, "nobody@example.com", entity.ClientInfo{}); err != nil || len(mails.Sent) != 0 {
+		t.Fatalf("Expected silent success for unknown email, got %v (%d mails)", err, len(mails.Sent))
+	}
+
+	// Новый запрос гасит предыдущую ссылку
+	if err := service.RequestPasswordReset(ctx, email, entity.ClientInfo{}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	service.WaitMail()
+	resets.age(2 * passwordResetInterval)
+	if err := service.RequestPasswordReset(ctx, email, entity.ClientInfo{}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	// Письма уходят в фоне
+	service.WaitMail()
+	if len(mails.Sent) != 2 || mails.Sent[1].To != email {
+		t.Fatalf("Expected 2 mails to %s, got %+v", email, mails.Sent)
+	}
+	tokenFrom := func(msg mailer.Message) string {
+		i := strings.Index(msg.Body, "token=")
+		if i < 0 || !strings.Contains(msg.Body, "https://app.example.com/reset?lang=ru&token=") {
+			t.Fatalf("Expected reset link in mail, got %q", msg.Body)
+		}
+		return strings.Fields(msg.Body[i+len("token="):])[0]
+	}
+	oldToken, token := tokenFrom(mails.Sent[0]), tokenFrom(mails.Sent[1])
+	for hash := range resets.tokens {
+		if hash == token || hash == oldToken {
+			t.Error("Expected only token hashes to be stored")
+		}
+	}
+
+	if err := service.ConfirmPasswordReset(ctx, oldToken, "new-password"); err != entity.ErrInvalidResetToken {
+		t.Errorf("Expected superseded token to be rejected, got %v", err)
+	}
+	// Неподходящий пароль отклоняется, а токен остается действующим
+	for _, password := range []string{"short", strings.Repeat("x", 73)} {
+		if err := service.ConfirmPasswordReset(ctx, token, password); err != entity.ErrInvalidPassword {
+			t.Errorf("Expected ErrInvalidPassword for %d-byte password, got %v", len(password), err)
+		}
+	}
+	if err := service.ConfirmPasswordReset(ctx, token, "new-password"); err != nil {
+		t.Fatalf("ConfirmPasswordReset: %v", err)
+	}
+	if !passwords.VerifyPassword(newHash, "new-password") {
+		t.Error("Expected password hash to be updated")
+	}
+	if _, err := service.RefreshToken(ctx, sessionToken, entity.ClientInfo{}); err == nil {
+		t.Error("Expected sessions to be revoked after password reset")
+	}
+
+	// Токен одноразовый
+	if err := service.ConfirmPasswordReset(ctx, token, "another-password"); err != entity.ErrInvalidResetToken {
+		t.Errorf("Expected used token to be rejected, got %v", err)
+	}
+}
+
+func TestPasswordResetRateLimit(t *testing.T) {
+	ctx := context.Background()
+	email := "a@example.com"
+	user := &entity.User{ID: 1, Email: &email, IsActive: true}
+	service := newTestAuthService(&MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, e string) (*entity.User, error) {
+			if e == email {
+				return user, nil
+			}
+			return nil, nil
+		},
+	})
+	resets, mails := service.resets, service.mails
+	client := entity.ClientInfo{IPAddress: "10.0.0.1"}
+
+	// Лимит одинаков для зарегистрированного и неизвестного адреса
+	for _, address := range []string{email, "nobody@example.com"} {
+		if err := service.RequestPasswordReset(ctx, address, client); err != nil {
+			t.Fatalf("RequestPasswordReset(%s): %v", address, err)
+		}
+		if err := service.RequestPasswordReset(ctx, " "+strings.ToUpper(address), client); err != entity.ErrTooManyRequests {
+			t.Errorf("Expected repeated request for %s to be limited, got %v", address, err)
+		}
+	}
+	service.WaitMail()
+	if len(mails.Sent) != 1 {
+		t.Errorf("Expected 1 mail, got %d", len(mails.Sent))
+	}
+
+	// Не больше passwordResetHourlyLimit запросов на адрес в час, отклоненный запрос тоже учтен
+	for i := 2; i < passwordResetHourlyLimit; i++ {
+		resets.age(2 * passwordResetInterval)
+		if err := service.RequestPasswordReset(ctx, email, entity.ClientInfo{}); err != nil {
+			t.Fatalf("Request %d: %v", i+1, err)
+		}
+	}
+	resets.age(2 * passwordResetInterval)
+	if err := service.RequestPasswordReset(ctx, email, entity.ClientInfo{}); err != entity.ErrTooManyRequests {
+		t.Errorf("Expected hourly limit per email, got %v", err)
+	}
+
+	// С одного IP - не больше passwordResetIPHourlyLimit запросов на любые адреса
+	other := entity.ClientInfo{IPAddress: "10.0.0.2"}
+	for i := 0; i < passwordResetIPHourlyLimit; i++ {
+		if err := service.RequestPasswordReset(ctx, fmt.Sprintf("user%d@example.com", i), other); err != nil {
+			t.Fatalf("Request %d from IP: %v", i+1, err)
+		}
+	}
+	if err := service.RequestPasswordReset(ctx, "fresh@example.com", other); err != entity.ErrTooManyRequests {
+		t.Errorf("Expected hourly limit per IP, got %v", err)
+	}
+}
+
+func TestPasswordResetHidesMailFailure(t *testing.T) {
+	ctx := context.Background()
+	email := "a@example.com"
+	user := &entity.User{ID: 1, Email: &email, IsActive: true}
+	service := newTestAuthService(&MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, e string) (*entity.User, error) {
+			return user, nil
+		},
+	})
+	service.mails.Err = errors.New("smtp unavailable")
+
+	// Ответ тот же, что и для неизвестного адреса: сбой почты не выдает аккаунт
+	if err := service.RequestPasswordReset(ctx, email, entity.ClientInfo{}); err != nil {
+		t.Errorf("Expected mail failure to be hidden, got %v", err)
+	}
+	service.WaitMail()
+	if len(service.resets.tokens) != 1 {
+		t.Errorf("Expected reset token to be issued, got %d", len(service.resets.tokens))
+	}
+}
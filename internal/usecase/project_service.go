@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+const maxProjectNameLength = 255
+
+// ProjectService - проекты и их участники. Роль участника определяет доступ к задачам проекта
+type ProjectService struct {
+	projectRepo repository.IProjectRepository
+	userRepo    repository.IUserRepository
+	taskService *TaskService
+	transactor  repository.ITransactor
+}
+
+func NewProjectService(
+	projectRepo repository.IProjectRepository,
+	userRepo repository.IUserRepository,
+	taskService *TaskService,
+	transactor repository.ITransactor,
+) *ProjectService {
+	return &ProjectService{
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		taskService: taskService,
+		transactor:  transactor,
+	}
+}
+
+// CreateProject создает проект, создатель становится его владельцем и администратором
+func (s *ProjectService) CreateProject(ctx context.Context, userID int, req *entity.CreateProjectRequest) (*entity.Project, error) {
+	name, ok := normalizeProjectName(req.Name)
+	if !ok {
+		return nil, entity.ErrInvalidProject
+	}
+
+	var project *entity.Project
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		project, err = s.projectRepo.Create(ctx, userID, &entity.CreateProjectRequest{Name: name, Description: req.Description})
+		if err != nil {
+			return err
+		}
+		_, err = s.projectRepo.SetMember(ctx, project.ID, userID, entity.ProjectRoleAdmin)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	project.Role = entity.ProjectRoleAdmin
+	return project, nil
+}
+
+// GetProject возвращает проект участнику
+func (s *ProjectService) GetProject(ctx context.Context, userID int, projectID int) (*entity.Project, error) {
+	role, err := s.memberRole(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, entity.ErrProjectNotFound
+	}
+
+	project.Role = role
+	return project, nil
+}
+
+// ListProjects возвращает проекты, где пользователь участник
+func (s *ProjectService) ListProjects(ctx context.Context, userID int) ([]entity.Project, error) {
+	return s.projectRepo.ListByMember(ctx, userID)
+}
+
+// UpdateProject переименовывает проект и/или меняет описание. Доступно администраторам
+func (s *ProjectService) UpdateProject(ctx context.Context, userID int, projectID int, req *entity.UpdateProjectRequest) (*entity.Project, error) {
+	role, err := s.requireRole(ctx, projectID, userID, entity.ProjectRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &entity.UpdateProjectRequest{Description: req.Description}
+	if req.Name != nil {
+		name, ok := normalizeProjectName(*req.Name)
+		if !ok {
+			return nil, entity.ErrInvalidProject
+		}
+		update.Name = &name
+	}
+	if update.Name == nil && update.Description == nil {
+		return nil, entity.ErrNoFieldsToUpdate
+	}
+
+	project, err := s.projectRepo.Update(ctx, projectID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Role = role
+	return project, nil
+}
+
+// DeleteProject удаляет проект и архивирует его задачи. Удалить проект может только владелец
+func (s *ProjectService) DeleteProject(ctx context.Context, userID int, projectID int) error {
+	if _, err := s.memberRole(ctx, projectID, userID); err != nil {
+		return err
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return entity.ErrProjectNotFound
+	}
+	if project.OwnerID != userID {
+		return entity.ErrForbidden
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Delete(ctx, projectID); err != nil {
+			return err
+		}
+		return s.taskService.archiveProjectTasks(ctx, projectID, userID)
+	})
+}
+
+// SetProjectMember добавляет участника или меняет его роль. Доступно администраторам,
+// роль владельца проекта не меняется
+func (s *ProjectService) SetProjectMember(ctx context.Context, userID int, projectID int, targetID int, role entity.ProjectRole) (*entity.ProjectMember, error) {
+	if !role.IsValid() {
+		return nil, entity.ErrInvalidMember
+	}
+	if _, err := s.requireRole(ctx, projectID, userID, entity.ProjectRoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotOwner(ctx, projectID, targetID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetById(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return s.projectRepo.SetMember(ctx, projectID, targetID, role)
+}
+
+// RemoveProjectMember исключает участника. Администратор исключает любого, кроме владельца,
+// участник может выйти из проекта сам
+func (s *ProjectService) RemoveProjectMember(ctx context.Context, userID int, projectID int, targetID int) error {
+	need := entity.ProjectRoleAdmin
+	if targetID == userID {
+		need = entity.ProjectRoleViewer
+	}
+	if _, err := s.requireRole(ctx, projectID, userID, need); err != nil {
+		return err
+	}
+	if err := s.checkNotOwner(ctx, projectID, targetID); err != nil {
+		return err
+	}
+
+	return s.projectRepo.RemoveMember(ctx, projectID, targetID)
+}
+
+// ListProjectMembers возвращает участников проекта любому его участнику
+func (s *ProjectService) ListProjectMembers(ctx context.Context, userID int, projectID int) ([]entity.ProjectMember, error) {
+	if _, err := s.memberRole(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.projectRepo.ListMembers(ctx, projectID)
+}
+
+// memberRole возвращает роль пользователя в проекте.
+// Для неучастника проект неотличим от несуществующего
+func (s *ProjectService) memberRole(ctx context.Context, projectID int, userID int) (entity.ProjectRole, error) {
+	role, err := s.projectRepo.GetMemberRole(ctx, projectID, userID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", entity.ErrProjectNotFound
+	}
+	return role, nil
+}
+
+// requireRole проверяет, что роль пользователя в проекте дает не меньше прав, чем need
+func (s *ProjectService) requireRole(ctx context.Context, projectID int, userID int, need entity.ProjectRole) (entity.ProjectRole, error) {
+	role, err := s.memberRole(ctx, projectID, userID)
+	if err != nil {
+		return "", err
+	}
+	if role.Access() < need.Access() {
+		return "", entity.ErrForbidden
+	}
+	return role, nil
+}
+
+// checkNotOwner запрещает менять роль владельца проекта или исключать его
+func (s *ProjectService) checkNotOwner(ctx context.Context, projectID int, targetID int) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return entity.ErrProjectNotFound
+	}
+	if project.OwnerID == targetID {
+		return entity.ErrInvalidMember
+	}
+	return nil
+}
+
+func normalizeProjectName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && utf8.RuneCountInString(name) <= maxProjectNameLength
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockProjectRepository - in-memory IProjectRepository
+type MockProjectRepository struct {
+	Projects map[int]*entity.Project
+	Members  map[int]map[int]entity.ProjectRole // project_id -> user_id -> role
+}
+
+var _ repository.IProjectRepository = (*MockProjectRepository)(nil)
+
+func (m *MockProjectRepository) Create(ctx context.Context, ownerID int, req *entity.CreateProjectRequest) (*entity.Project, error) {
+	if m.Projects == nil {
+		m.Projects = make(map[int]*entity.Project)
+	}
+	project := &entity.Project{ID: len(m.Projects) + 1, Name: req.Name, Description: req.Description, OwnerID: ownerID}
+	m.Projects[project.ID] = project
+	return project, nil
+}
+
+func (m *MockProjectRepository) GetByID(ctx context.Context, id int) (*entity.Project, error) {
+	if project, ok := m.Projects[id]; ok {
+		copied := *project
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockProjectRepository) ListByMember(ctx context.Context, userID int) ([]entity.Project, error) {
+	var projects []entity.Project
+	for id, members := range m.Members {
+		if role, ok := members[userID]; ok && m.Projects[id] != nil {
+			project := *m.Projects[id]
+			project.Role = role
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+func (m *MockProjectRepository) Update(ctx context.Context, id int, req *entity.UpdateProjectRequest) (*entity.Project, error) {
+	project, ok := m.Projects[id]
+	if !ok {
+		return nil, entity.ErrProjectNotFound
+	}
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (m *MockProjectRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.Projects[id]; !ok {
+		return entity.ErrProjectNotFound
+	}
+	delete(m.Projects, id)
+	return nil
+}
+
+func (m *MockProjectRepository) GetMemberRole(ctx context.Context, projectID, userID int) (entity.ProjectRole, error) {
+	if m.Projects[projectID] == nil {
+		return "", nil
+	}
+	return m.Members[projectID][userID], nil
+}
+
+func (m *MockProjectRepository) SetMember(ctx context.Context, projectID, userID int, role entity.ProjectRole) (*entity.ProjectMember, error) {
+	if m.Members == nil {
+		m.Members = make(map[int]map[int]entity.ProjectRole)
+	}
+	if m.Members[projectID] == nil {
+		m.Members[projectID] = make(map[int]entity.ProjectRole)
+	}
+	m.Members[projectID][userID] = role
+	return &entity.ProjectMember{ProjectID: projectID, UserID: userID, Role: role}, nil
+}
+
+func (m *MockProjectRepository) RemoveMember(ctx context.Context, projectID, userID int) error {
+	delete(m.Members[projectID], userID)
+	return nil
+}
+
+func (m *MockProjectRepository) ListMembers(ctx context.Context, projectID int) ([]entity.ProjectMember, error) {
+	var members []entity.ProjectMember
+	for userID, role := range m.Members[projectID] {
+		members = append(members, entity.ProjectMember{ProjectID: projectID, UserID: userID, Role: role})
+	}
+	return members, nil
+}
+
+func TestDeleteProjectArchivesTasks(t *testing.T) {
+	ctx := context.Background()
+
+	archivedAt := time.Now()
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 2, Version: 2, ProjectID: intPtr(1), ArchivedAt: &archivedAt}, nil
+		},
+		ArchiveFunc: func(ctx context.Context, projectID int) ([]entity.Task, error) {
+			return []entity.Task{
+				{ID: 10, OwnerId: 1, Version: 2, ProjectID: &projectID, ArchivedAt: &archivedAt},
+				{ID: 11, OwnerId: 2, Version: 2, ProjectID: &projectID, ArchivedAt: &archivedAt},
+			}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	projects := &MockProjectRepository{}
+	mockOutbox := &MockAuditOutboxRepository{}
+	taskService := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, projects: projects, outbox: mockOutbox})
+	service := NewProjectService(projects, mockUserRepo, taskService, &MockTransactor{})
+
+	project, err := service.CreateProject(ctx, 1, &entity.CreateProjectRequest{Name: " Backend "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if project.Name != "Backend" || project.Role != entity.ProjectRoleAdmin {
+		t.Errorf("Expected trimmed name and admin role, got %q and %q", project.Name, project.Role)
+	}
+	if _, err := service.SetProjectMember(ctx, 1, project.ID, 2, entity.ProjectRoleAdmin); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Администратор не может понизить владельца или удалить проект
+	if _, err := service.SetProjectMember(ctx, 2, project.ID, 1, entity.ProjectRoleViewer); err != entity.ErrInvalidMember {
+		t.Errorf("Expected ErrInvalidMember for owner, got %v", err)
+	}
+	if err := service.DeleteProject(ctx, 2, project.ID); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden for admin, got %v", err)
+	}
+
+	if err := service.DeleteProject(ctx, 1, project.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mockOutbox.Messages) != 2 {
+		t.Fatalf("Expected audit message per archived task, got %d", len(mockOutbox.Messages))
+	}
+	for _, msg := range mockOutbox.Messages {
+		if _, ok := msg.Changes["archived_at"]; !ok || len(msg.Changes) != 1 {
+			t.Errorf("Expected only archived_at change, got %v", msg.Changes)
+		}
+	}
+
+	// Архивную задачу владелец может читать, но не изменять
+	if _, err := taskService.GetTask(ctx, 11, 2); err != nil {
+		t.Errorf("Expected archived task to be readable, got %v", err)
+	}
+	if _, err := taskService.UpdateTask(ctx, 11, 2, &entity.UpdateTaskRequest{Title: "New"}); err != entity.ErrTaskArchived {
+		t.Errorf("Expected ErrTaskArchived, got %v", err)
+	}
+}
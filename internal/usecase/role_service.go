@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+type RoleService struct {
+	roleRepo repository.IRoleRepository
+	userRepo repository.IUserRepository
+
+	mu          sync.RWMutex
+	permissions map[entity.Role]map[entity.Permission]bool
+	// unverifiedDenied - права, которых лишены пользователи с неподтвержденным email
+	unverifiedDenied map[entity.Permission]bool
+}
+
+// DefaultUnverifiedDenied - по умолчанию неподтвержденный пользователь может войти и читать,
+// но не может создавать и менять задачи
+var DefaultUnverifiedDenied = []entity.Permission{entity.PermissionTaskWrite}
+
+func NewRoleService(roleRepo repository.IRoleRepository, userRepo repository.IUserRepository) *RoleService {
+	s := &RoleService{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		permissions: make(map[entity.Role]map[entity.Permission]bool),
+	}
+	s.SetUnverifiedPolicy(DefaultUnverifiedDenied)
+	return s
+}
+
+// SetUnverifiedPolicy задает права, которых лишены пользователи с неподтвержденным email.
+// Пустой список - email ни на что не влияет
+func (s *RoleService) SetUnverifiedPolicy(denied []entity.Permission) error {
+	deniedSet := make(map[entity.Permission]bool, len(denied))
+	for _, permission := range denied {
+		if !permission.IsValid() {
+			return fmt.Errorf("unknown permission %q", permission)
+		}
+		deniedSet[permission] = true
+	}
+
+	s.mu.Lock()
+	s.unverifiedDenied = deniedSet
+	s.mu.Unlock()
+	return nil
+}
+
+// RestrictUnverified возвращает права без тех, что запрещены до подтверждения email
+func (s *RoleService) RestrictUnverified(permissions map[entity.Permission]bool) map[entity.Permission]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	restricted := make(map[entity.Permission]bool, len(permissions))
+	for permission := range permissions {
+		if !s.unverifiedDenied[permission] {
+			restricted[permission] = true
+		}
+	}
+	return restricted
+}
+
+// LoadPermissions загружает права ролей из БД в память
+func (s *RoleService) LoadPermissions(ctx context.Context) error {
+	rolePermissions, err := s.roleRepo.GetPermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load role permissions: %w", err)
+	}
+
+	permissions := make(map[entity.Role]map[entity.Permission]bool, len(rolePermissions))
+	for role, perms := range rolePermissions {
+		permissions[role] = make(map[entity.Permission]bool, len(perms))
+		for _, perm := range perms {
+			permissions[role][perm] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.permissions = permissions
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Permissions возвращает объединение прав всех указанных ролей
+func (s *RoleService) Permissions(roles []entity.Role) map[entity.Permission]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	permissions := make(map[entity.Permission]bool)
+	for _, role := range roles {
+		for permission := range s.permissions[role] {
+			permissions[permission] = true
+		}
+	}
+	return permissions
+}
+
+// GetUserRoles возвращает роли пользователя. Пользователь без явных ролей - обычный user
+func (s *RoleService) GetUserRoles(ctx context.Context, userID int) ([]entity.Role, error) {
+	roles, err := s.roleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+	if len(roles) == 0 {
+		return []entity.Role{entity.RoleUser}, nil
+	}
+	return roles, nil
+}
+
+// EmailVerified возвращает, подтвердил ли пользователь email
+func (s *RoleService) EmailVerified(ctx context.Context, userID int) (bool, error) {
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return false, entity.ErrUserNotFound
+	}
+	return user.EmailVerified(), nil
+}
+
+// SetUserRoles заменяет роли пользователя
+func (s *RoleService) SetUserRoles(ctx context.Context, req *entity.SetUserRolesRequest) ([]entity.Role, error) {
+	if len(req.Roles) == 0 {
+		return nil, entity.ErrInvalidRole
+	}
+
+	seen := make(map[entity.Role]bool, len(req.Roles))
+	roles := make([]entity.Role, 0, len(req.Roles))
+	for _, role := range req.Roles {
+		if !role.IsValid() {
+			return nil, entity.ErrInvalidRole
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	user, err := s.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	if err := s.roleRepo.SetForUser(ctx, req.UserID, roles); err != nil {
+		return nil, fmt.Errorf("failed to set user roles: %w", err)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// Вложения хранятся в общем слое файлов в подкаталоге attachmentBlobDir
+const (
+	attachmentBlobDir     = "attachments"
+	maxAttachmentNameSize = 255
+)
+
+// DefaultAttachmentLimits - ограничения на вложения по умолчанию:
+// файл до 25MB, до 100MB на задачу и до 1GB на пользователя
+func DefaultAttachmentLimits() entity.AttachmentLimits {
+	return entity.AttachmentLimits{
+		MaxFileSize: 25 << 20,
+		TaskQuota:   100 << 20,
+		UserQuota:   1 << 30,
+	}
+}
+
+// SetAttachmentLimits заменяет ограничения на вложения (настраивается при старте)
+func (s *TaskService) SetAttachmentLimits(limits entity.AttachmentLimits) error {
+	if limits.MaxFileSize <= 0 || limits.TaskQuota <= 0 || limits.UserQuota <= 0 {
+		return fmt.Errorf("attachment limits must be positive")
+	}
+	s.attachments = limits
+	return nil
+}
+
+// AttachmentLimits возвращает текущие ограничения на вложения
+func (s *TaskService) AttachmentLimits() entity.AttachmentLimits {
+	return s.attachments
+}
+
+// NewAttachmentUpload проверяет имя файла и доступ на изменение задачи и начинает потоковую
+// загрузку вложения: чанки пишутся во временный объект, загрузка обрывается
+// с ErrFileTooLarge, как только файл превысит лимит
+func (s *TaskService) NewAttachmentUpload(ctx context.Context, userID int, req *entity.UploadAttachmentRequest) (*BlobUpload, error) {
+	if _, ok := normalizeAttachmentName(req.FileName); !ok {
+		return nil, entity.ErrInvalidAttachment
+	}
+	if _, err := s.authorizeTask(ctx, req.TaskID, userID, entity.TaskAccessWrite); err != nil {
+		return nil, err
+	}
+	return s.blobs.NewUpload(ctx, s.attachments.MaxFileSize), nil
+}
+
+// UploadAttachment прикрепляет к задаче файл из потоковой загрузки (см. NewAttachmentUpload).
+// Нужен доступ на изменение задачи, квоты задачи и пользователя не должны переполняться.
+// Под итоговым ключом файл появляется только вместе с записью в БД
+func (s *TaskService) UploadAttachment(ctx context.Context, userID int, req *entity.UploadAttachmentRequest, upload *BlobUpload) (*entity.TaskAttachment, error) {
+	defer upload.Abort()
+
+	fileName, ok := normalizeAttachmentName(req.FileName)
+	if !ok {
+		return nil, entity.ErrInvalidAttachment
+	}
+	// Доступ могли отозвать, пока шла загрузка
+	if _, err := s.authorizeTask(ctx, req.TaskID, userID, entity.TaskAccessWrite); err != nil {
+		return nil, err
+	}
+
+	closed, err := upload.Close()
+	if err != nil {
+		return nil, err
+	}
+	blob := *closed
+	blob.StorageKey = newBlobKey(attachmentBlobDir, fmt.Sprintf("task_%d", req.TaskID))
+
+	var attachment *entity.TaskAttachment
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.attachmentRepo.LockUsage(ctx, req.TaskID, userID); err != nil {
+			return err
+		}
+		usage, err := s.attachmentRepo.GetUsage(ctx, req.TaskID, userID)
+		if err != nil {
+			return err
+		}
+		if usage.TaskBytes+blob.FileSize > s.attachments.TaskQuota || usage.UserBytes+blob.FileSize > s.attachments.UserQuota {
+			return entity.ErrQuotaExceeded
+		}
+
+		attachment, err = s.attachmentRepo.Create(ctx, &entity.TaskAttachment{
+			TaskID:     req.TaskID,
+			UploaderID: &userID,
+			FileName:   fileName,
+			Blob:       blob,
+		})
+		if err != nil {
+			return err
+		}
+		// Переносим файл последним шагом: если перенос не удался, запись откатится
+		return upload.Commit(ctx, blob.StorageKey)
+	})
+	if err != nil {
+		// Транзакция не зафиксирована, а файл уже перенесен - удаляем его
+		if upload.committed {
+			s.blobs.Remove(ctx, &blob)
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments возвращает вложения задачи в порядке загрузки
+func (s *TaskService) ListAttachments(ctx context.Context, userID int, taskID int) ([]entity.TaskAttachment, error) {
+	if _, err := s.GetTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentRepo.ListByTasks(ctx, []int{taskID})
+}
+
+// DownloadAttachment возвращает метаданные вложения и его содержимое чанками по chunkSize байт
+func (s *TaskService) DownloadAttachment(ctx context.Context, userID int, taskID int, attachmentID int, chunkSize int) (*entity.TaskAttachment, <-chan []byte, <-chan error, error) {
+	attachment, err := s.getAttachment(ctx, taskID, attachmentID, userID, entity.TaskAccessRead)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dataChan, errChan := s.blobs.Stream(ctx, &attachment.Blob, chunkSize)
+	return attachment, dataChan, errChan, nil
+}
+
+// DeleteAttachment удаляет вложение. Нужен доступ на изменение задачи
+func (s *TaskService) DeleteAttachment(ctx context.Context, userID int, taskID int, attachmentID int) error {
+	attachment, err := s.getAttachment(ctx, taskID, attachmentID, userID, entity.TaskAccessWrite)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+	s.blobs.Remove(ctx, &attachment.Blob)
+	return nil
+}
+
+// getAttachment проверяет доступ к задаче и загружает ее вложение
+func (s *TaskService) getAttachment(ctx context.Context, taskID int, attachmentID int, userID int, need entity.TaskAccess) (*entity.TaskAttachment, error) {
+	if _, err := s.authorizeTask(ctx, taskID, userID, need); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil || attachment.TaskID != taskID {
+		return nil, entity.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// normalizeAttachmentName оставляет от имени файла только последний элемент пути
+// и запрещает управляющие символы
+func normalizeAttachmentName(name string) (string, bool) {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" || utf8.RuneCountInString(name) > maxAttachmentNameSize {
+		return "", false
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return "", false
+	}
+	return name, utf8.ValidString(name)
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockAttachmentRepository - in-memory IAttachmentRepository
+type MockAttachmentRepository struct {
+	Attachments map[int]*entity.TaskAttachment
+}
+
+var _ repository.IAttachmentRepository = (*MockAttachmentRepository)(nil)
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *entity.TaskAttachment) (*entity.TaskAttachment, error) {
+	if m.Attachments == nil {
+		m.Attachments = make(map[int]*entity.TaskAttachment)
+	}
+	created := *attachment
+	created.ID = len(m.Attachments) + 1
+	m.Attachments[created.ID] = &created
+	copied := created
+	return &copied, nil
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id int) (*entity.TaskAttachment, error) {
+	if attachment, ok := m.Attachments[id]; ok {
+		copied := *attachment
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockAttachmentRepository) ListByTasks(ctx context.Context, taskIDs []int) ([]entity.TaskAttachment, error) {
+	var attachments []entity.TaskAttachment
+	for _, attachment := range m.Attachments {
+		if slices.Contains(taskIDs, attachment.TaskID) {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.Attachments[id]; !ok {
+		return entity.ErrAttachmentNotFound
+	}
+	delete(m.Attachments, id)
+	return nil
+}
+
+func (m *MockAttachmentRepository) LockUsage(ctx context.Context, taskID int, userID int) error {
+	return nil
+}
+
+func (m *MockAttachmentRepository) GetUsage(ctx context.Context, taskID int, userID int) (*entity.AttachmentUsage, error) {
+	usage := &entity.AttachmentUsage{}
+	for _, attachment := range m.Attachments {
+		if attachment.TaskID == taskID {
+			usage.TaskBytes += attachment.FileSize
+		}
+		if attachment.UploaderID != nil && *attachment.UploaderID == userID {
+			usage.UserBytes += attachment.FileSize
+		}
+	}
+	return usage, nil
+}
+
+func TestUploadAttachmentLimits(t *testing.T) {
+	ctx := context.Background()
+
+	// Владелец 1, editor 2, viewer 3
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{
+		Shares: map[int]map[int]entity.ShareRole{10: {2: entity.ShareRoleEditor, 3: entity.ShareRoleViewer}},
+	}
+	attachments := &MockAttachmentRepository{}
+	dir := t.TempDir()
+	blobs := NewBlobService(storage.NewLocalStore(dir))
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, collaborators: collab, attachments: attachments, blobs: blobs})
+	if err := service.SetAttachmentLimits(entity.AttachmentLimits{MaxFileSize: 10, TaskQuota: 14, UserQuota: 100}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	attachment, err := uploadTestAttachment(ctx, service, 2, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "../notes.txt"}, []byte("hello"))
+	if err != nil {
+		t.Fatalf("Expected editor to upload, got %v", err)
+	}
+	if attachment.FileName != "notes.txt" {
+		t.Errorf("Expected path to be stripped from file name, got %q", attachment.FileName)
+	}
+	if attachment.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("Expected sniffed content type, got %q", attachment.ContentType)
+	}
+
+	tests := []struct {
+		name   string
+		userID int
+		req    *entity.UploadAttachmentRequest
+		data   []byte
+		want   error
+	}{
+		{"viewer", 3, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "a.txt"}, []byte("x"), entity.ErrForbidden},
+		{"empty name", 1, &entity.UploadAttachmentRequest{TaskID: 10, FileName: " "}, []byte("x"), entity.ErrInvalidAttachment},
+		{"empty file", 1, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "a.txt"}, nil, entity.ErrEmptyFile},
+		{"too large", 1, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "a.txt"}, make([]byte, 11), entity.ErrFileTooLarge},
+		{"task quota", 1, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "a.txt"}, make([]byte, 10), entity.ErrQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uploadTestAttachment(ctx, service, tt.userID, tt.req, tt.data); err != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if len(attachments.Attachments) != 1 {
+		t.Errorf("Expected rejected uploads to leave no attachments, got %d", len(attachments.Attachments))
+	}
+
+	// Отклоненные загрузки не оставляют файлов ни под итоговыми ключами, ни во временных
+	var files []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if len(files) != 1 || !strings.HasSuffix(files[0], filepath.FromSlash(attachment.StorageKey)) {
+		t.Errorf("Expected only the stored attachment file, got %v", files)
+	}
+}
+
+func TestUploadAttachmentForbiddenBeforeData(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	blobs := NewBlobService(storage.NewLocalStore(t.TempDir()))
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, blobs: blobs})
+
+	// Чужой задаче загрузка даже не начинается
+	if upload, err := service.NewAttachmentUpload(ctx, 2, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "a.txt"}); err != entity.ErrForbidden || upload != nil {
+		t.Errorf("Expected ErrForbidden before any data, got %v", err)
+	}
+}
+
+// uploadTestAttachment загружает data одним чанком, как это делает gRPC хендлер
+func uploadTestAttachment(ctx context.Context, service *TaskService, userID int, req *entity.UploadAttachmentRequest, data []byte) (*entity.TaskAttachment, error) {
+	upload, err := service.NewAttachmentUpload(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	defer upload.Abort()
+
+	if _, err := upload.Write(data); err != nil {
+		return nil, err
+	}
+	return service.UploadAttachment(ctx, userID, req, upload)
+}
+
+func TestDeleteTaskRemovesAttachmentFiles(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1}, nil
+		},
+		GetSubtreeFunc: func(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error) {
+			return []entity.Task{{ID: rootID, OwnerId: 1, Version: 1}}, nil
+		},
+	}
+	attachments := &MockAttachmentRepository{}
+	store := storage.NewLocalStore(t.TempDir())
+	blobs := NewBlobService(store)
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, attachments: attachments, blobs: blobs})
+
+	attachment, err := uploadTestAttachment(ctx, service, 1, &entity.UploadAttachmentRequest{TaskID: 10, FileName: "image.png"}, []byte("\x89PNG\r\n\x1a\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attachment.ContentType != "image/png" {
+		t.Errorf("Expected image/png, got %q", attachment.ContentType)
+	}
+
+	if err := service.DeleteTask(ctx, 10, 1, 0, entity.SubtaskCascade); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.Stat(ctx, attachment.StorageKey); err != storage.ErrNotFound {
+		t.Errorf("Expected attachment file to be removed, got %v", err)
+	}
+}
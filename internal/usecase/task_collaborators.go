@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// AssignTask назначает пользователя исполнителем задачи. Исполнитель получает право изменять
+// задачу и ее подзадачи, поэтому назначать, как и выдавать доступ, может только владелец
+// (или администратор проекта)
+func (s *TaskService) AssignTask(ctx context.Context, userID int, taskID int, assigneeID int) (*entity.Task, error) {
+	return s.changeAssignees(ctx, userID, taskID, assigneeID, true)
+}
+
+// UnassignTask снимает исполнителя с задачи. Владелец снимает любого исполнителя,
+// пользователь - себя
+func (s *TaskService) UnassignTask(ctx context.Context, userID int, taskID int, assigneeID int) (*entity.Task, error) {
+	return s.changeAssignees(ctx, userID, taskID, assigneeID, false)
+}
+
+func (s *TaskService) changeAssignees(ctx context.Context, userID int, taskID int, assigneeID int, assign bool) (*entity.Task, error) {
+	need := entity.TaskAccessOwner
+	if !assign && assigneeID == userID {
+		need = entity.TaskAccessRead
+	}
+	oldTask, err := s.authorizeTask(ctx, taskID, userID, need)
+	if err != nil {
+		return nil, err
+	}
+	if oldTask.ArchivedAt != nil {
+		return nil, entity.ErrTaskArchived
+	}
+
+	// Повторное назначение/снятие ничего не меняет
+	if slices.Contains(oldTask.AssigneeIDs, assigneeID) == assign {
+		return oldTask, nil
+	}
+
+	newTask := *oldTask
+	if assign {
+		user, err := s.userRepo.GetById(ctx, assigneeID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, entity.ErrUserNotFound
+		}
+		newTask.AssigneeIDs = append(slices.Clone(oldTask.AssigneeIDs), assigneeID)
+		slices.Sort(newTask.AssigneeIDs)
+	} else {
+		newTask.AssigneeIDs = slices.DeleteFunc(slices.Clone(oldTask.AssigneeIDs), func(id int) bool { return id == assigneeID })
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if assign {
+			err = s.collabRepo.AddAssignee(ctx, taskID, assigneeID)
+		} else {
+			err = s.collabRepo.RemoveAssignee(ctx, taskID, assigneeID)
+		}
+		if err != nil {
+			return err
+		}
+		return s.sendAuditMessage(ctx, entity.ActionUpdate, userID, taskID, oldTask, &newTask, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &newTask, nil
+}
+
+// ShareTask выдает пользователю доступ к задаче и ее подзадачам или меняет его роль.
+// Управлять доступом может только владелец
+func (s *TaskService) ShareTask(ctx context.Context, userID int, taskID int, targetID int, role entity.ShareRole) (*entity.TaskShare, error) {
+	if !role.IsValid() {
+		return nil, entity.ErrInvalidShare
+	}
+	task, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessOwner)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == task.OwnerId {
+		return nil, entity.ErrInvalidShare
+	}
+
+	user, err := s.userRepo.GetById(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return s.collabRepo.SetShare(ctx, taskID, targetID, role)
+}
+
+// UnshareTask отзывает доступ. Владелец отзывает любой доступ, пользователь - свой собственный
+func (s *TaskService) UnshareTask(ctx context.Context, userID int, taskID int, targetID int) error {
+	need := entity.TaskAccessOwner
+	if targetID == userID {
+		need = entity.TaskAccessRead
+	}
+	if _, err := s.authorizeTask(ctx, taskID, userID, need); err != nil {
+		return err
+	}
+
+	return s.collabRepo.RemoveShare(ctx, taskID, targetID)
+}
+
+// ListTaskShares возвращает, кому выдан доступ к задаче
+func (s *TaskService) ListTaskShares(ctx context.Context, userID int, taskID int) ([]entity.TaskShare, error) {
+	if _, err := s.GetTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.collabRepo.ListShares(ctx, taskID)
+}
+
+// authorizeTask загружает задачу с метками и исполнителями и проверяет,
+// что доступ пользователя к ней не ниже need. Архивную задачу изменять нельзя
+func (s *TaskService) authorizeTask(ctx context.Context, taskID int, userID int, need entity.TaskAccess) (*entity.Task, error) {
+	task, err := s.taskRepo.GetByTaskId(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, entity.ErrTaskNotFound
+	}
+
+	access, err := s.taskAccess(ctx, task, userID)
+	if err != nil {
+		return nil, err
+	}
+	if access < need {
+		return nil, entity.ErrForbidden
+	}
+	if need == entity.TaskAccessWrite && task.ArchivedAt != nil {
+		return nil, entity.ErrTaskArchived
+	}
+
+	if err := s.loadDetails(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// taskAccess - доступ пользователя к задаче: владелец, исполнитель, выданный доступ
+// или роль в проекте задачи (берется наибольший)
+func (s *TaskService) taskAccess(ctx context.Context, task *entity.Task, userID int) (entity.TaskAccess, error) {
+	if task.OwnerId == userID {
+		return entity.TaskAccessOwner, nil
+	}
+	access, err := s.collabRepo.GetAccess(ctx, task.ID, userID)
+	if err != nil {
+		return entity.TaskAccessNone, err
+	}
+	if task.ProjectID != nil {
+		role, err := s.projectRepo.GetMemberRole(ctx, *task.ProjectID, userID)
+		if err != nil {
+			return entity.TaskAccessNone, err
+		}
+		access = max(access, role.Access())
+	}
+	return access, nil
+}
+
+// loadDetails заполняет у задач метки и исполнителей
+func (s *TaskService) loadDetails(ctx context.Context, tasks ...*entity.Task) error {
+	if err := s.loadLabels(ctx, tasks...); err != nil {
+		return err
+	}
+	return s.loadAssignees(ctx, tasks...)
+}
+
+// loadAssignees заполняет исполнителей у задач одним запросом
+func (s *TaskService) loadAssignees(ctx context.Context, tasks ...*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	assignees, err := s.collabRepo.ListAssigneeIDs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.AssigneeIDs = assignees[task.ID]
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockCollaboratorRepository - in-memory ICollaboratorRepository, доступ без наследования от предков
+type MockCollaboratorRepository struct {
+	Assignees map[int][]int                    // task_id -> user_id
+	Shares    map[int]map[int]entity.ShareRole // task_id -> user_id -> role
+}
+
+var _ repository.ICollaboratorRepository = (*MockCollaboratorRepository)(nil)
+
+func (m *MockCollaboratorRepository) GetAccess(ctx context.Context, taskID, userID int) (entity.TaskAccess, error) {
+	if slices.Contains(m.Assignees[taskID], userID) {
+		return entity.TaskAccessWrite, nil
+	}
+	return m.Shares[taskID][userID].Access(), nil
+}
+
+func (m *MockCollaboratorRepository) AddAssignee(ctx context.Context, taskID, userID int) error {
+	if m.Assignees == nil {
+		m.Assignees = make(map[int][]int)
+	}
+	m.Assignees[taskID] = append(m.Assignees[taskID], userID)
+	return nil
+}
+
+func (m *MockCollaboratorRepository) RemoveAssignee(ctx context.Context, taskID, userID int) error {
+	m.Assignees[taskID] = slices.DeleteFunc(m.Assignees[taskID], func(id int) bool { return id == userID })
+	return nil
+}
+
+func (m *MockCollaboratorRepository) ListAssigneeIDs(ctx context.Context, taskIDs []int) (map[int][]int, error) {
+	result := make(map[int][]int)
+	for _, taskID := range taskIDs {
+		if ids := m.Assignees[taskID]; len(ids) > 0 {
+			result[taskID] = slices.Clone(ids)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockCollaboratorRepository) SetShare(ctx context.Context, taskID, userID int, role entity.ShareRole) (*entity.TaskShare, error) {
+	if m.Shares == nil {
+		m.Shares = make(map[int]map[int]entity.ShareRole)
+	}
+	if m.Shares[taskID] == nil {
+		m.Shares[taskID] = make(map[int]entity.ShareRole)
+	}
+	m.Shares[taskID][userID] = role
+	return &entity.TaskShare{TaskID: taskID, UserID: userID, Role: role}, nil
+}
+
+func (m *MockCollaboratorRepository) RemoveShare(ctx context.Context, taskID, userID int) error {
+	delete(m.Shares[taskID], userID)
+	return nil
+}
+
+func (m *MockCollaboratorRepository) ListShares(ctx context.Context, taskID int) ([]entity.TaskShare, error) {
+	var shares []entity.TaskShare
+	for userID, role := range m.Shares[taskID] {
+		shares = append(shares, entity.TaskShare{TaskID: taskID, UserID: userID, Role: role})
+	}
+	return shares, nil
+}
+
+func TestTaskAccessLevels(t *testing.T) {
+	ctx := context.Background()
+
+	// Владелец 1, исполнитель 2, editor 3, viewer 4, посторонний 5
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1, Title: "Task", Status: entity.StatusPending}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			return &entity.Task{ID: id, OwnerId: 1, Version: version + 1, Title: updates["title"].(string)}, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{
+		Assignees: map[int][]int{10: {2}},
+		Shares:    map[int]map[int]entity.ShareRole{10: {3: entity.ShareRoleEditor, 4: entity.ShareRoleViewer}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, collaborators: collab})
+
+	tests := []struct {
+		userID    int
+		canRead   bool
+		canWrite  bool
+		canDelete bool
+	}{
+		{1, true, true, true},
+		{2, true, true, false},
+		{3, true, true, false},
+		{4, true, false, false},
+		{5, false, false, false},
+	}
+
+	for _, tt := range tests {
+		_, err := service.GetTask(ctx, 10, tt.userID)
+		if (err == nil) != tt.canRead {
+			t.Errorf("User %d: GetTask error %v, expected read=%t", tt.userID, err, tt.canRead)
+		}
+		_, err = service.UpdateTask(ctx, 10, tt.userID, &entity.UpdateTaskRequest{Title: "New"})
+		if (err == nil) != tt.canWrite {
+			t.Errorf("User %d: UpdateTask error %v, expected write=%t", tt.userID, err, tt.canWrite)
+		}
+		err = service.DeleteTask(ctx, 10, tt.userID, 0, entity.SubtaskCascade)
+		if (err == nil) != tt.canDelete {
+			t.Errorf("User %d: DeleteTask error %v, expected delete=%t", tt.userID, err, tt.canDelete)
+		}
+	}
+}
+
+func TestAssignTaskAuditsAssignees(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			if id == 2 {
+				return &entity.User{ID: 2}, nil
+			}
+			return nil, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{}
+	mockOutbox := &MockAuditOutboxRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, collaborators: collab, outbox: mockOutbox})
+
+	task, err := service.AssignTask(ctx, 1, 10, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(task.AssigneeIDs, []int{2}) {
+		t.Errorf("Expected assignees [2], got %v", task.AssigneeIDs)
+	}
+	if len(mockOutbox.Messages) != 1 {
+		t.Fatalf("Expected one audit message, got %d", len(mockOutbox.Messages))
+	}
+	if _, ok := mockOutbox.Messages[0].Changes["assignee_ids"]; !ok || len(mockOutbox.Messages[0].Changes) != 1 {
+		t.Errorf("Expected only assignee_ids change, got %v", mockOutbox.Messages[0].Changes)
+	}
+
+	// Повторное назначение не пишет аудит, несуществующего пользователя назначить нельзя
+	if _, err := service.AssignTask(ctx, 1, 10, 2); err != nil || len(mockOutbox.Messages) != 1 {
+		t.Errorf("Expected no-op, got %v and %d messages", err, len(mockOutbox.Messages))
+	}
+	if _, err := service.AssignTask(ctx, 1, 10, 3); err != entity.ErrUserNotFound {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAssignTaskCannotRaiseAccess(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	// 2 - редактор, 3 - читатель, 4 - исполнитель
+	collab := &MockCollaboratorRepository{
+		Assignees: map[int][]int{10: {4}},
+		Shares:    map[int]map[int]entity.ShareRole{10: {2: entity.ShareRoleEditor, 3: entity.ShareRoleViewer}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, collaborators: collab})
+
+	// Ни редактор, ни исполнитель не могут выдать право изменения через назначение
+	for _, editorID := range []int{2, 4} {
+		for _, targetID := range []int{3, 5} {
+			if _, err := service.AssignTask(ctx, editorID, 10, targetID); err != entity.ErrForbidden {
+				t.Errorf("User %d assigning %d: expected ErrForbidden, got %v", editorID, targetID, err)
+			}
+		}
+	}
+	if access, _ := collab.GetAccess(ctx, 10, 3); access != entity.TaskAccessRead {
+		t.Errorf("Expected viewer to keep read access, got %v", access)
+	}
+
+	// Снять себя исполнитель может, чужого исполнителя редактор снять не может
+	if _, err := service.UnassignTask(ctx, 2, 10, 4); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden for editor unassigning another user, got %v", err)
+	}
+	if _, err := service.UnassignTask(ctx, 4, 10, 4); err != nil {
+		t.Errorf("Expected assignee to unassign themselves, got %v", err)
+	}
+	if _, err := service.AssignTask(ctx, 1, 10, 3); err != nil {
+		t.Errorf("Expected owner to assign, got %v", err)
+	}
+}
+
+func TestShareTaskOnlyByOwner(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{Shares: map[int]map[int]entity.ShareRole{10: {3: entity.ShareRoleEditor}}}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, collaborators: collab})
+
+	if _, err := service.ShareTask(ctx, 3, 10, 4, entity.ShareRoleViewer); err != entity.ErrForbidden {
+		t.Errorf("Expected editor to be forbidden from sharing, got %v", err)
+	}
+	if _, err := service.ShareTask(ctx, 1, 10, 4, "admin"); err != entity.ErrInvalidShare {
+		t.Errorf("Expected ErrInvalidShare for unknown role, got %v", err)
+	}
+	if _, err := service.ShareTask(ctx, 1, 10, 1, entity.ShareRoleViewer); err != entity.ErrInvalidShare {
+		t.Errorf("Expected ErrInvalidShare for owner, got %v", err)
+	}
+	if _, err := service.ShareTask(ctx, 1, 10, 4, entity.ShareRoleViewer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Пользователь может отказаться от своего доступа, но не отозвать чужой
+	if err := service.UnshareTask(ctx, 3, 10, 4); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if err := service.UnshareTask(ctx, 3, 10, 3); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, ok := collab.Shares[10][3]; ok {
+		t.Errorf("Expected share of user 3 removed")
+	}
+}
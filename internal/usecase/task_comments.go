@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+const maxCommentLength = 10000
+
+// commentSortFields - поля, по которым можно сортировать комментарии
+var commentSortFields = []string{
+	entity.CommentSortCreatedAt,
+}
+
+// CreateComment добавляет комментарий или ответ на комментарий.
+// Комментировать может любой, кто может читать задачу
+func (s *TaskService) CreateComment(ctx context.Context, userID int, req *entity.CreateCommentRequest) (*entity.TaskComment, error) {
+	body, ok := normalizeCommentBody(req.Body)
+	if !ok {
+		return nil, entity.ErrInvalidComment
+	}
+	if _, err := s.GetTask(ctx, req.TaskID, userID); err != nil {
+		return nil, err
+	}
+
+	// Отвечать можно только на неудаленный комментарий той же задачи
+	if req.ParentCommentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.TaskID != req.TaskID || parent.DeletedAt != nil {
+			return nil, entity.ErrInvalidComment
+		}
+	}
+
+	var comment *entity.TaskComment
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		comment, err = s.commentRepo.Create(ctx, userID, &entity.CreateCommentRequest{
+			TaskID:          req.TaskID,
+			ParentCommentID: req.ParentCommentID,
+			Body:            body,
+		})
+		if err != nil {
+			return err
+		}
+		return s.sendCommentAudit(ctx, entity.ActionCommentCreate, userID, nil, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// UpdateComment меняет текст комментария, предыдущий текст попадает в историю правок.
+// Редактировать может только автор, пока у него есть доступ к задаче
+func (s *TaskService) UpdateComment(ctx context.Context, userID int, taskID int, commentID int, body string) (*entity.TaskComment, error) {
+	body, ok := normalizeCommentBody(body)
+	if !ok {
+		return nil, entity.ErrInvalidComment
+	}
+	oldComment, err := s.authorizeComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if oldComment.AuthorID != userID {
+		return nil, entity.ErrForbidden
+	}
+	if oldComment.Body == body {
+		return oldComment, nil
+	}
+
+	var comment *entity.TaskComment
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		comment, err = s.commentRepo.Update(ctx, commentID, body)
+		if err != nil {
+			return err
+		}
+		return s.sendCommentAudit(ctx, entity.ActionCommentUpdate, userID, oldComment, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// DeleteComment удаляет комментарий. Удалить может автор или владелец задачи.
+// Ответы на удаленный комментарий остаются
+func (s *TaskService) DeleteComment(ctx context.Context, userID int, taskID int, commentID int) error {
+	comment, err := s.authorizeComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		if _, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessOwner); err != nil {
+			return err
+		}
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+			return err
+		}
+		return s.sendCommentAudit(ctx, entity.ActionCommentDelete, userID, comment, nil)
+	})
+}
+
+// ListComments возвращает страницу корневых комментариев задачи или ответов на комментарий.
+// По умолчанию комментарии идут от старых к новым
+func (s *TaskService) ListComments(ctx context.Context, userID int, req *entity.ListCommentsRequest) (*entity.CommentPage, error) {
+	if req.Page.SortDir == "" {
+		req.Page.SortDir = entity.SortAsc
+	}
+	if err := normalizePage(&req.Page, entity.CommentSortCreatedAt, commentSortFields); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTask(ctx, req.TaskID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.ParentCommentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.TaskID != req.TaskID {
+			return nil, entity.ErrCommentNotFound
+		}
+	}
+
+	return s.commentRepo.List(ctx, req)
+}
+
+// ListCommentRevisions возвращает предыдущие версии текста комментария
+func (s *TaskService) ListCommentRevisions(ctx context.Context, userID int, taskID int, commentID int) ([]entity.CommentRevision, error) {
+	if _, err := s.authorizeComment(ctx, userID, taskID, commentID); err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.ListRevisions(ctx, commentID)
+}
+
+// authorizeComment загружает неудаленный комментарий задачи taskID
+// и проверяет, что пользователь может читать задачу
+func (s *TaskService) authorizeComment(ctx context.Context, userID int, taskID int, commentID int) (*entity.TaskComment, error) {
+	if _, err := s.GetTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.TaskID != taskID || comment.DeletedAt != nil {
+		return nil, entity.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// sendCommentAudit кладет действие с комментарием в outbox как событие истории задачи
+func (s *TaskService) sendCommentAudit(ctx context.Context, action entity.ActionType, userID int, oldComment, newComment *entity.TaskComment) error {
+	auditMsg := &entity.AuditMessage{
+		Action:    action,
+		UserID:    userID,
+		Timestamp: time.Now(),
+	}
+	if oldComment != nil {
+		auditMsg.EntityID = oldComment.TaskID
+		auditMsg.OldValues = commentAuditValues(oldComment)
+	}
+	if newComment != nil {
+		auditMsg.EntityID = newComment.TaskID
+		auditMsg.NewValues = commentAuditValues(newComment)
+	}
+	if oldComment != nil && newComment != nil {
+		auditMsg.Changes = auditChanges(auditMsg.OldValues, auditMsg.NewValues)
+	}
+
+	if err := s.outboxRepo.Add(ctx, auditMsg); err != nil {
+		return fmt.Errorf("failed to save audit message to outbox: %w", err)
+	}
+	return nil
+}
+
+// commentAuditValues - поля комментария, которые попадают в аудит
+func commentAuditValues(comment *entity.TaskComment) map[string]interface{} {
+	return map[string]interface{}{
+		"comment_id":        comment.ID,
+		"parent_comment_id": formatAuditID(comment.ParentCommentID),
+		"author_id":         comment.AuthorID,
+		"body":              comment.Body,
+	}
+}
+
+func normalizeCommentBody(body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	return body, body != "" && utf8.RuneCountInString(body) <= maxCommentLength
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockCommentRepository - in-memory ICommentRepository
+type MockCommentRepository struct {
+	Comments  map[int]*entity.TaskComment
+	Revisions map[int][]entity.CommentRevision // comment_id -> предыдущие версии
+}
+
+var _ repository.ICommentRepository = (*MockCommentRepository)(nil)
+
+func (m *MockCommentRepository) Create(ctx context.Context, authorID int, req *entity.CreateCommentRequest) (*entity.TaskComment, error) {
+	if m.Comments == nil {
+		m.Comments = make(map[int]*entity.TaskComment)
+	}
+	comment := &entity.TaskComment{ID: len(m.Comments) + 1, TaskID: req.TaskID, ParentCommentID: req.ParentCommentID, AuthorID: authorID, Body: req.Body}
+	m.Comments[comment.ID] = comment
+	copied := *comment
+	return &copied, nil
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, id int) (*entity.TaskComment, error) {
+	if comment, ok := m.Comments[id]; ok {
+		copied := *comment
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, id int, body string) (*entity.TaskComment, error) {
+	comment, ok := m.Comments[id]
+	if !ok || comment.DeletedAt != nil {
+		return nil, entity.ErrCommentNotFound
+	}
+	if m.Revisions == nil {
+		m.Revisions = make(map[int][]entity.CommentRevision)
+	}
+	m.Revisions[id] = append(m.Revisions[id], entity.CommentRevision{ID: len(m.Revisions[id]) + 1, CommentID: id, Body: comment.Body})
+	now := time.Now()
+	comment.Body = body
+	comment.EditedAt = &now
+	copied := *comment
+	return &copied, nil
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id int) error {
+	comment, ok := m.Comments[id]
+	if !ok || comment.DeletedAt != nil {
+		return entity.ErrCommentNotFound
+	}
+	now := time.Now()
+	comment.Body = ""
+	comment.DeletedAt = &now
+	delete(m.Revisions, id)
+	return nil
+}
+
+func (m *MockCommentRepository) List(ctx context.Context, req *entity.ListCommentsRequest) (*entity.CommentPage, error) {
+	page := &entity.CommentPage{}
+	for _, comment := range m.Comments {
+		sameParent := (comment.ParentCommentID == nil) == (req.ParentCommentID == nil) &&
+			(req.ParentCommentID == nil || *comment.ParentCommentID == *req.ParentCommentID)
+		if comment.TaskID == req.TaskID && sameParent {
+			page.Comments = append(page.Comments, *comment)
+		}
+	}
+	return page, nil
+}
+
+func (m *MockCommentRepository) ListRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error) {
+	return m.Revisions[commentID], nil
+}
+
+func TestCommentPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	// Владелец 1, viewer 2, viewer 3, посторонний 4
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{
+		Shares: map[int]map[int]entity.ShareRole{10: {2: entity.ShareRoleViewer, 3: entity.ShareRoleViewer}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, collaborators: collab})
+
+	comment, err := service.CreateComment(ctx, 2, &entity.CreateCommentRequest{TaskID: 10, Body: "  Looks good  "})
+	if err != nil {
+		t.Fatalf("Expected reader to comment, got %v", err)
+	}
+	if comment.Body != "Looks good" {
+		t.Errorf("Expected trimmed body, got %q", comment.Body)
+	}
+	if _, err := service.CreateComment(ctx, 4, &entity.CreateCommentRequest{TaskID: 10, Body: "Hi"}); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden for stranger, got %v", err)
+	}
+	if _, err := service.CreateComment(ctx, 2, &entity.CreateCommentRequest{TaskID: 10, Body: "   "}); err != entity.ErrInvalidComment {
+		t.Errorf("Expected ErrInvalidComment for empty body, got %v", err)
+	}
+
+	// Ответ должен ссылаться на комментарий той же задачи
+	reply, err := service.CreateComment(ctx, 3, &entity.CreateCommentRequest{TaskID: 10, ParentCommentID: &comment.ID, Body: "Agreed"})
+	if err != nil {
+		t.Fatalf("Expected reply, got %v", err)
+	}
+	if _, err := service.CreateComment(ctx, 1, &entity.CreateCommentRequest{TaskID: 11, ParentCommentID: &comment.ID, Body: "Wrong task"}); err != entity.ErrInvalidComment {
+		t.Errorf("Expected ErrInvalidComment for reply to another task, got %v", err)
+	}
+
+	// Редактирует только автор, удаляет автор или владелец задачи
+	if _, err := service.UpdateComment(ctx, 3, 10, comment.ID, "Edited"); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden for non-author edit, got %v", err)
+	}
+	if err := service.DeleteComment(ctx, 3, 10, comment.ID); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden for non-author delete, got %v", err)
+	}
+	if err := service.DeleteComment(ctx, 1, 10, reply.ID); err != nil {
+		t.Errorf("Expected owner to delete, got %v", err)
+	}
+	if _, err := service.UpdateComment(ctx, 3, 10, reply.ID, "Edited"); err != entity.ErrCommentNotFound {
+		t.Errorf("Expected ErrCommentNotFound for deleted comment, got %v", err)
+	}
+}
+
+func TestUpdateCommentKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	mockOutbox := &MockAuditOutboxRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, outbox: mockOutbox})
+
+	comment, err := service.CreateComment(ctx, 1, &entity.CreateCommentRequest{TaskID: 10, Body: "First"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated, err := service.UpdateComment(ctx, 1, 10, comment.ID, "Second")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Body != "Second" || updated.EditedAt == nil {
+		t.Errorf("Expected edited comment, got %+v", updated)
+	}
+
+	revisions, err := service.ListCommentRevisions(ctx, 1, 10, comment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Body != "First" {
+		t.Errorf("Expected one revision with the old body, got %+v", revisions)
+	}
+
+	// Оба действия попадают в историю задачи
+	if len(mockOutbox.Messages) != 2 {
+		t.Fatalf("Expected two audit messages, got %d", len(mockOutbox.Messages))
+	}
+	create, update := mockOutbox.Messages[0], mockOutbox.Messages[1]
+	if create.Action != entity.ActionCommentCreate || create.EntityID != 10 {
+		t.Errorf("Expected CommentCreate for task 10, got %s for %d", create.Action, create.EntityID)
+	}
+	if update.Action != entity.ActionCommentUpdate || len(update.Changes) != 1 {
+		t.Errorf("Expected CommentUpdate with one change, got %s with %v", update.Action, update.Changes)
+	}
+	if _, ok := update.Changes["body"]; !ok {
+		t.Errorf("Expected body change, got %v", update.Changes)
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// AddDependency делает blockerID блокирующей задачей для taskID.
+// Зависимость, замыкающая цикл, отклоняется с ErrDependencyCycle
+func (s *TaskService) AddDependency(ctx context.Context, userID int, taskID int, blockerID int) (*entity.TaskDependencies, error) {
+	if taskID == blockerID {
+		return nil, entity.ErrDependencyCycle
+	}
+	// Блокировать можно только задачу, которую пользователь изменяет, и только видимой ему задачей
+	if _, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessWrite); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTask(ctx, blockerID, userID); err != nil {
+		return nil, err
+	}
+
+	// Цикл проверяем под блокировкой графа, иначе встречные добавления A->B и B->A пройдут оба
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.dependencyRepo.LockGraph(ctx); err != nil {
+			return err
+		}
+		cycle, err := s.dependencyRepo.Reaches(ctx, taskID, blockerID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return entity.ErrDependencyCycle
+		}
+		return s.dependencyRepo.Add(ctx, blockerID, taskID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListDependencies(ctx, userID, taskID)
+}
+
+// RemoveDependency снимает блокировку taskID задачей blockerID
+func (s *TaskService) RemoveDependency(ctx context.Context, userID int, taskID int, blockerID int) (*entity.TaskDependencies, error) {
+	if _, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.dependencyRepo.Remove(ctx, blockerID, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.ListDependencies(ctx, userID, taskID)
+}
+
+// ListDependencies возвращает задачи, блокирующие taskID, и задачи, которые блокирует она сама.
+// Связанные задачи, которые пользователь не может читать, возвращаются только с ID и статусом
+func (s *TaskService) ListDependencies(ctx context.Context, userID int, taskID int) (*entity.TaskDependencies, error) {
+	if _, err := s.GetTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	blockedBy, err := s.dependencyRepo.ListBlockers(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.dependencyRepo.ListBlocked(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*entity.Task, 0, len(blockedBy)+len(blocks))
+	for _, linked := range [][]entity.Task{blockedBy, blocks} {
+		for i := range linked {
+			task := &linked[i]
+			access, err := s.taskAccess(ctx, task, userID)
+			if err != nil {
+				return nil, err
+			}
+			if access < entity.TaskAccessRead {
+				*task = entity.Task{ID: task.ID, Status: task.Status}
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	if err := s.loadDetails(ctx, tasks...); err != nil {
+		return nil, err
+	}
+
+	return &entity.TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// checkNotBlocked возвращает *entity.BlockedTaskError, если переход в status
+// требует завершения блокирующих задач, а они еще открыты. Вызывается в транзакции
+// под LockGraph
+func (s *TaskService) checkNotBlocked(ctx context.Context, taskID int, status entity.TaskStatus) error {
+	if !status.BlockedByOpenTasks() {
+		return nil
+	}
+
+	blockerIDs, err := s.dependencyRepo.ListOpenBlockerIDs(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if len(blockerIDs) > 0 {
+		return &entity.BlockedTaskError{TaskID: taskID, BlockerIDs: blockerIDs}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockDependencyRepository - in-memory IDependencyRepository
+type MockDependencyRepository struct {
+	Edges       [][2]int            // blocker_id, blocked_id
+	OpenTaskIDs map[int]bool        // незавершенные задачи
+	Tasks       map[int]entity.Task // связанные задачи, по умолчанию только ID
+	Locks       int                 // сколько раз брали LockGraph
+}
+
+func (m *MockDependencyRepository) task(id int) entity.Task {
+	if task, ok := m.Tasks[id]; ok {
+		return task
+	}
+	return entity.Task{ID: id}
+}
+
+var _ repository.IDependencyRepository = (*MockDependencyRepository)(nil)
+
+func (m *MockDependencyRepository) Add(ctx context.Context, blockerID, blockedID int) error {
+	if !slices.Contains(m.Edges, [2]int{blockerID, blockedID}) {
+		m.Edges = append(m.Edges, [2]int{blockerID, blockedID})
+	}
+	return nil
+}
+
+func (m *MockDependencyRepository) Remove(ctx context.Context, blockerID, blockedID int) error {
+	m.Edges = slices.DeleteFunc(m.Edges, func(edge [2]int) bool { return edge == [2]int{blockerID, blockedID} })
+	return nil
+}
+
+func (m *MockDependencyRepository) ListBlockers(ctx context.Context, taskID int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	for _, edge := range m.Edges {
+		if edge[1] == taskID {
+			tasks = append(tasks, m.task(edge[0]))
+		}
+	}
+	return tasks, nil
+}
+
+func (m *MockDependencyRepository) ListBlocked(ctx context.Context, taskID int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	for _, edge := range m.Edges {
+		if edge[0] == taskID {
+			tasks = append(tasks, m.task(edge[1]))
+		}
+	}
+	return tasks, nil
+}
+
+func (m *MockDependencyRepository) ListOpenBlockerIDs(ctx context.Context, taskID int) ([]int, error) {
+	if m.Locks == 0 {
+		return nil, errors.New("dependency graph is not locked")
+	}
+	var ids []int
+	for _, edge := range m.Edges {
+		if edge[1] == taskID && m.OpenTaskIDs[edge[0]] {
+			ids = append(ids, edge[0])
+		}
+	}
+	return ids, nil
+}
+
+func (m *MockDependencyRepository) Reaches(ctx context.Context, fromID, toID int) (bool, error) {
+	visited := map[int]bool{}
+	queue := []int{fromID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, edge := range m.Edges {
+			if edge[0] == id && !visited[edge[1]] {
+				if edge[1] == toID {
+					return true, nil
+				}
+				visited[edge[1]] = true
+				queue = append(queue, edge[1])
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *MockDependencyRepository) LockGraph(ctx context.Context) error {
+	m.Locks++
+	return nil
+}
+
+func TestAddDependencyRejectsCycle(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	deps := &MockDependencyRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, dependencies: deps})
+
+	// 1 блокирует 2, 2 блокирует 3
+	if _, err := service.AddDependency(ctx, 1, 2, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	result, err := service.AddDependency(ctx, 1, 3, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.BlockedBy) != 1 || result.BlockedBy[0].ID != 2 {
+		t.Errorf("Expected task 3 blocked by 2, got %+v", result.BlockedBy)
+	}
+
+	// 3 -> 1 замкнул бы цикл 1 -> 2 -> 3 -> 1
+	if _, err := service.AddDependency(ctx, 1, 1, 3); err != entity.ErrDependencyCycle {
+		t.Errorf("Expected ErrDependencyCycle, got %v", err)
+	}
+	if _, err := service.AddDependency(ctx, 1, 1, 1); err != entity.ErrDependencyCycle {
+		t.Errorf("Expected ErrDependencyCycle for self dependency, got %v", err)
+	}
+	if len(deps.Edges) != 2 {
+		t.Errorf("Expected 2 dependencies, got %v", deps.Edges)
+	}
+}
+
+func TestUpdateTaskBlockedByOpenTasks(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1, Status: entity.StatusPending}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			return &entity.Task{ID: id, OwnerId: 1, Version: version + 1, Status: updates["status"].(entity.TaskStatus)}, nil
+		},
+	}
+	deps := &MockDependencyRepository{
+		Edges:       [][2]int{{1, 5}, {2, 5}, {3, 5}},
+		OpenTaskIDs: map[int]bool{1: true, 3: true},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, dependencies: deps})
+
+	for _, to := range []entity.TaskStatus{entity.StatusInProgress, entity.StatusCompleted} {
+		_, err := service.UpdateTask(ctx, 5, 1, &entity.UpdateTaskRequest{Status: to})
+		var blocked *entity.BlockedTaskError
+		if !errors.As(err, &blocked) || !errors.Is(err, entity.ErrTaskBlocked) {
+			t.Fatalf("%s: expected BlockedTaskError, got %v", to, err)
+		}
+		if !slices.Equal(blocked.BlockerIDs, []int{1, 3}) {
+			t.Errorf("%s: expected blockers [1 3], got %v", to, blocked.BlockerIDs)
+		}
+	}
+
+	// Отмена заблокированной задачи разрешена
+	if _, err := service.UpdateTask(ctx, 5, 1, &entity.UpdateTaskRequest{Status: entity.StatusCancelled}); err != nil {
+		t.Errorf("Expected cancel to pass, got %v", err)
+	}
+}
+
+func TestListDependenciesHidesUnreadableTasks(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Status: entity.StatusPending}, nil
+		},
+	}
+	// Задача 5 пользователя 1: ее блокируют своя 1 и чужая 7, сама она блокирует чужую 8,
+	// к которой у пользователя 1 есть доступ на чтение
+	deps := &MockDependencyRepository{
+		Edges: [][2]int{{1, 5}, {7, 5}, {5, 8}},
+		Tasks: map[int]entity.Task{
+			1: {ID: 1, OwnerId: 1, Title: "Mine", Status: entity.StatusCompleted},
+			7: {ID: 7, OwnerId: 9, Title: "Hidden", Description: "secret", Status: entity.StatusInProgress},
+			8: {ID: 8, OwnerId: 9, Title: "Shared", Status: entity.StatusPending},
+		},
+	}
+	collab := &MockCollaboratorRepository{
+		Assignees: map[int][]int{7: {9}},
+		Shares:    map[int]map[int]entity.ShareRole{8: {1: entity.ShareRoleViewer}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, dependencies: deps, collaborators: collab})
+
+	result, err := service.ListDependencies(ctx, 1, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.BlockedBy) != 2 || len(result.Blocks) != 1 {
+		t.Fatalf("Expected 2 blockers and 1 blocked task, got %+v", result)
+	}
+	if result.BlockedBy[0].Title != "Mine" || result.Blocks[0].Title != "Shared" {
+		t.Errorf("Expected readable tasks in full, got %+v and %+v", result.BlockedBy[0], result.Blocks[0])
+	}
+	hidden := result.BlockedBy[1]
+	if hidden.ID != 7 || hidden.Status != entity.StatusInProgress {
+		t.Errorf("Expected unreadable blocker to keep ID and status, got %+v", hidden)
+	}
+	if hidden.Title != "" || hidden.Description != "" || hidden.OwnerId != 0 || hidden.AssigneeIDs != nil {
+		t.Errorf("Expected unreadable blocker to be stripped, got %+v", hidden)
+	}
+}
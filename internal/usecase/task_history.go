@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// taskHistorySortFields - поля, по которым можно сортировать историю задачи
+var taskHistorySortFields = []string{
+	entity.TaskHistorySortChangedAt,
+}
+
+// GetTaskHistory возвращает страницу аудита задачи. Читать историю может владелец задачи
+// (исполнителям и получившим доступ она не видна), а с readAny (право task:read_any) -
+// любой задачи, в том числе удаленной
+func (s *TaskService) GetTaskHistory(ctx context.Context, userID int, readAny bool, req *entity.TaskHistoryRequest) (*entity.TaskHistoryPage, error) {
+	if req.Action != "" && !req.Action.IsValid() {
+		return nil, entity.ErrInvalidFilter
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, entity.ErrInvalidFilter
+	}
+	if err := normalizePage(&req.Page, entity.TaskHistorySortChangedAt, taskHistorySortFields); err != nil {
+		return nil, err
+	}
+
+	// 1. Определяем владельца: у удаленной задачи - по аудиту
+	ownerID := 0
+	task, err := s.taskRepo.GetByTaskId(ctx, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task != nil {
+		ownerID = task.OwnerId
+	} else {
+		ownerID, err = s.auditRepo.GetTaskOwnerID(ctx, req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if ownerID == 0 {
+			return nil, entity.ErrTaskNotFound
+		}
+	}
+
+	// 2. Проверяем права доступа
+	if ownerID != userID && !readAny {
+		return nil, entity.ErrForbidden
+	}
+
+	// 3. Читаем и разбираем аудит
+	auditPage, err := s.auditRepo.ListByTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.TaskHistoryPage{
+		Entries:  make([]entity.TaskHistoryEntry, 0, len(auditPage.Audits)),
+		PageInfo: auditPage.PageInfo,
+	}
+	for _, audit := range auditPage.Audits {
+		entry, err := decodeTaskAudit(&audit)
+		if err != nil {
+			return nil, err
+		}
+		page.Entries = append(page.Entries, *entry)
+	}
+
+	return page, nil
+}
+
+// decodeTaskAudit разбирает JSONB значения аудита в структуру
+func decodeTaskAudit(audit *entity.TaskAudit) (*entity.TaskHistoryEntry, error) {
+	entry := &entity.TaskHistoryEntry{
+		ID:        audit.ID,
+		TaskID:    audit.EntityID,
+		ActorID:   audit.UserID,
+		Action:    audit.Action,
+		ChangedAt: audit.ChangesAt,
+	}
+
+	if err := decodeJSONObject(audit.OldValues, &entry.OldValues); err != nil {
+		return nil, fmt.Errorf("failed to decode old_values of audit %d: %w", audit.ID, err)
+	}
+	if err := decodeJSONObject(audit.NewValues, &entry.NewValues); err != nil {
+		return nil, fmt.Errorf("failed to decode new_values of audit %d: %w", audit.ID, err)
+	}
+
+	// changes хранится как {"field": {"old": ..., "new": ...}}
+	var changes map[string]struct {
+		Old any `json:"old"`
+		New any `json:"new"`
+	}
+	if audit.Changes != nil {
+		if err := json.Unmarshal([]byte(*audit.Changes), &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode changes of audit %d: %w", audit.ID, err)
+		}
+	}
+	for field, change := range changes {
+		entry.Changes = append(entry.Changes, entity.FieldChange{
+			Field: field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+	sort.Slice(entry.Changes, func(i, j int) bool {
+		return entry.Changes[i].Field < entry.Changes[j].Field
+	})
+
+	return entry, nil
+}
+
+func decodeJSONObject(raw *string, dst *map[string]any) error {
+	if raw == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(*raw), dst)
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestGetTaskHistoryForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1}, nil
+		},
+	}
+	collab := &MockCollaboratorRepository{
+		Assignees: map[int][]int{1: {3}},
+		Shares:    map[int]map[int]entity.ShareRole{1: {4: entity.ShareRoleEditor}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, collaborators: collab})
+
+	_, err := service.GetTaskHistory(ctx, 2, false, &entity.TaskHistoryRequest{TaskID: 1})
+	if err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+
+	// Исполнитель и получивший доступ задачу видят, но историю читает только владелец
+	for _, userID := range []int{3, 4} {
+		if _, err := service.GetTask(ctx, 1, userID); err != nil {
+			t.Fatalf("user %d: expected task to be readable, got %v", userID, err)
+		}
+		if _, err := service.GetTaskHistory(ctx, userID, false, &entity.TaskHistoryRequest{TaskID: 1}); err != entity.ErrForbidden {
+			t.Errorf("user %d: expected ErrForbidden for history, got %v", userID, err)
+		}
+	}
+
+	// С правом task:read_any историю чужой задачи читать можно
+	if _, err := service.GetTaskHistory(ctx, 2, true, &entity.TaskHistoryRequest{TaskID: 1}); err != nil {
+		t.Errorf("Expected no error for read_any, got %v", err)
+	}
+}
+
+func TestGetTaskHistoryDecodesChanges(t *testing.T) {
+	ctx := context.Background()
+	oldValues := `{"title": "Old", "status": "pending"}`
+	newValues := `{"title": "New", "status": "completed"}`
+	changes := `{"title": {"old": "Old", "new": "New"}, "status": {"old": "pending", "new": "completed"}}`
+
+	mockAuditRepo := &MockTaskAuditRepository{
+		// Задача удалена - владелец определяется по аудиту
+		GetTaskOwnerIDFunc: func(ctx context.Context, taskID int) (int, error) {
+			return 1, nil
+		},
+		ListByTaskFunc: func(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error) {
+			return &entity.TaskAuditPage{Audits: []entity.TaskAudit{{
+				ID:        10,
+				UserID:    1,
+				Action:    entity.ActionUpdate,
+				EntityID:  req.TaskID,
+				OldValues: &oldValues,
+				NewValues: &newValues,
+				Changes:   &changes,
+			}}}, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{audit: mockAuditRepo})
+
+	page, err := service.GetTaskHistory(ctx, 1, false, &entity.TaskHistoryRequest{TaskID: 5})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(page.Entries))
+	}
+
+	entry := page.Entries[0]
+	if entry.TaskID != 5 || entry.NewValues["title"] != "New" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	want := []entity.FieldChange{
+		{Field: "status", Old: "pending", New: "completed"},
+		{Field: "title", Old: "Old", New: "New"},
+	}
+	if len(entry.Changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %v", len(want), entry.Changes)
+	}
+	for i := range want {
+		if entry.Changes[i] != want[i] {
+			t.Errorf("Expected change %v, got %v", want[i], entry.Changes[i])
+		}
+	}
+}
+
+func TestGetTaskHistoryInvalidFilter(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTaskService(taskServiceDeps{})
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	tests := []*entity.TaskHistoryRequest{
+		{TaskID: 1, Action: "Archive"},
+		{TaskID: 1, From: &from, To: &to},
+	}
+	for _, req := range tests {
+		if _, err := service.GetTaskHistory(ctx, 1, false, req); err != entity.ErrInvalidFilter {
+			t.Errorf("Expected ErrInvalidFilter for %+v, got %v", req, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+const maxLabelNameLength = 100
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+func (s *TaskService) CreateLabel(ctx context.Context, userID int, req *entity.CreateLabelRequest) (*entity.Label, error) {
+	name, ok := normalizeLabelName(req.Name)
+	if !ok {
+		return nil, entity.ErrInvalidLabelData
+	}
+	color := entity.DefaultLabelColor
+	if req.Color != "" {
+		if color, ok = normalizeLabelColor(req.Color); !ok {
+			return nil, entity.ErrInvalidLabelData
+		}
+	}
+
+	return s.labelRepo.Create(ctx, userID, &entity.CreateLabelRequest{Name: name, Color: color})
+}
+
+func (s *TaskService) ListLabels(ctx context.Context, userID int) ([]entity.Label, error) {
+	return s.labelRepo.ListByOwner(ctx, userID)
+}
+
+// UpdateLabel переименовывает метку и/или меняет ее цвет
+func (s *TaskService) UpdateLabel(ctx context.Context, userID int, labelID int, req *entity.UpdateLabelRequest) (*entity.Label, error) {
+	if _, err := s.getOwnLabel(ctx, userID, labelID); err != nil {
+		return nil, err
+	}
+
+	update := &entity.UpdateLabelRequest{}
+	if req.Name != nil {
+		name, ok := normalizeLabelName(*req.Name)
+		if !ok {
+			return nil, entity.ErrInvalidLabelData
+		}
+		update.Name = &name
+	}
+	if req.Color != nil {
+		color, ok := normalizeLabelColor(*req.Color)
+		if !ok {
+			return nil, entity.ErrInvalidLabelData
+		}
+		update.Color = &color
+	}
+	if update.Name == nil && update.Color == nil {
+		return nil, entity.ErrNoFieldsToUpdate
+	}
+
+	return s.labelRepo.Update(ctx, labelID, update)
+}
+
+// DeleteLabel удаляет метку и пишет аудит по каждой задаче, с которой она была снята
+func (s *TaskService) DeleteLabel(ctx context.Context, userID int, labelID int) error {
+	if _, err := s.getOwnLabel(ctx, userID, labelID); err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		taskIDs, err := s.labelRepo.ListTaskIDsByLabel(ctx, labelID)
+		if err != nil {
+			return err
+		}
+
+		// Запоминаем задачи с метками до удаления
+		tasks := make([]*entity.Task, 0, len(taskIDs))
+		for _, taskID := range taskIDs {
+			task, err := s.taskRepo.GetByTaskId(ctx, taskID)
+			if err != nil {
+				return err
+			}
+			if task != nil {
+				tasks = append(tasks, task)
+			}
+		}
+		if err := s.loadDetails(ctx, tasks...); err != nil {
+			return err
+		}
+
+		if err := s.labelRepo.Delete(ctx, labelID); err != nil {
+			return err
+		}
+
+		for _, oldTask := range tasks {
+			newTask := *oldTask
+			newTask.Labels = slices.DeleteFunc(slices.Clone(oldTask.Labels), func(label entity.Label) bool {
+				return label.ID == labelID
+			})
+			if err := s.sendAuditMessage(ctx, entity.ActionUpdate, userID, oldTask.ID, oldTask, &newTask, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AttachLabel привязывает метку к задаче и возвращает задачу с метками
+func (s *TaskService) AttachLabel(ctx context.Context, userID int, taskID int, labelID int) (*entity.Task, error) {
+	return s.changeTaskLabels(ctx, userID, taskID, labelID, true)
+}
+
+// DetachLabel отвязывает метку от задачи и возвращает задачу с метками
+func (s *TaskService) DetachLabel(ctx context.Context, userID int, taskID int, labelID int) (*entity.Task, error) {
+	return s.changeTaskLabels(ctx, userID, taskID, labelID, false)
+}
+
+func (s *TaskService) changeTaskLabels(ctx context.Context, userID int, taskID int, labelID int, attach bool) (*entity.Task, error) {
+	// 1. Задачу вызывающий должен иметь право изменять, а метка - принадлежать ему
+	oldTask, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessWrite)
+	if err != nil {
+		return nil, err
+	}
+	label, err := s.getOwnLabel(ctx, userID, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Считаем новый набор меток, повторная привязка/отвязка ничего не меняет
+	attached := slices.ContainsFunc(oldTask.Labels, func(l entity.Label) bool { return l.ID == labelID })
+	if attached == attach {
+		return oldTask, nil
+	}
+
+	newTask := *oldTask
+	if attach {
+		newTask.Labels = append(slices.Clone(oldTask.Labels), *label)
+		slices.SortFunc(newTask.Labels, func(a, b entity.Label) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	} else {
+		newTask.Labels = slices.DeleteFunc(slices.Clone(oldTask.Labels), func(l entity.Label) bool { return l.ID == labelID })
+	}
+
+	// 3. Меняем связь и кладем аудит в outbox в одной транзакции
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if attach {
+			err = s.labelRepo.Attach(ctx, taskID, labelID)
+		} else {
+			err = s.labelRepo.Detach(ctx, taskID, labelID)
+		}
+		if err != nil {
+			return err
+		}
+		return s.sendAuditMessage(ctx, entity.ActionUpdate, userID, taskID, oldTask, &newTask, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &newTask, nil
+}
+
+// getOwnLabel возвращает метку вызывающего. Чужая метка неотличима от несуществующей
+func (s *TaskService) getOwnLabel(ctx context.Context, userID int, labelID int) (*entity.Label, error) {
+	label, err := s.labelRepo.GetByID(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if label == nil || label.OwnerID != userID {
+		return nil, entity.ErrLabelNotFound
+	}
+	return label, nil
+}
+
+// loadLabels заполняет метки у задач одним запросом
+func (s *TaskService) loadLabels(ctx context.Context, tasks ...*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	labels, err := s.labelRepo.ListByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.Labels = labels[task.ID]
+	}
+	return nil
+}
+
+// labelNames - имена меток для аудита (пустой список, а не null)
+func labelNames(labels []entity.Label) []string {
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = label.Name
+	}
+	return names
+}
+
+func normalizeLabelName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && utf8.RuneCountInString(name) <= maxLabelNameLength
+}
+
+func normalizeLabelColor(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	return color, labelColorPattern.MatchString(color)
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+	"github.com/St1cky1/task-service/internal/repository"
+)
+
+// MockLabelRepository - in-memory ILabelRepository
+type MockLabelRepository struct {
+	Labels    map[int]*entity.Label
+	TaskLabel map[int][]int // task_id -> label_id
+}
+
+var _ repository.ILabelRepository = (*MockLabelRepository)(nil)
+
+func (m *MockLabelRepository) Create(ctx context.Context, ownerID int, req *entity.CreateLabelRequest) (*entity.Label, error) {
+	if m.Labels == nil {
+		m.Labels = make(map[int]*entity.Label)
+	}
+	label := &entity.Label{ID: len(m.Labels) + 1, OwnerID: ownerID, Name: req.Name, Color: req.Color}
+	m.Labels[label.ID] = label
+	return label, nil
+}
+
+func (m *MockLabelRepository) GetByID(ctx context.Context, id int) (*entity.Label, error) {
+	return m.Labels[id], nil
+}
+
+func (m *MockLabelRepository) ListByOwner(ctx context.Context, ownerID int) ([]entity.Label, error) {
+	var labels []entity.Label
+	for _, label := range m.Labels {
+		if label.OwnerID == ownerID {
+			labels = append(labels, *label)
+		}
+	}
+	return labels, nil
+}
+
+func (m *MockLabelRepository) Update(ctx context.Context, id int, req *entity.UpdateLabelRequest) (*entity.Label, error) {
+	label := m.Labels[id]
+	if req.Name != nil {
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+	return label, nil
+}
+
+func (m *MockLabelRepository) Delete(ctx context.Context, id int) error {
+	delete(m.Labels, id)
+	for taskID := range m.TaskLabel {
+		m.Detach(ctx, taskID, id)
+	}
+	return nil
+}
+
+func (m *MockLabelRepository) Attach(ctx context.Context, taskID, labelID int) error {
+	if m.TaskLabel == nil {
+		m.TaskLabel = make(map[int][]int)
+	}
+	m.TaskLabel[taskID] = append(m.TaskLabel[taskID], labelID)
+	return nil
+}
+
+func (m *MockLabelRepository) Detach(ctx context.Context, taskID, labelID int) error {
+	m.TaskLabel[taskID] = slices.DeleteFunc(m.TaskLabel[taskID], func(id int) bool { return id == labelID })
+	return nil
+}
+
+func (m *MockLabelRepository) ListByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]entity.Label, error) {
+	result := make(map[int][]entity.Label)
+	for _, taskID := range taskIDs {
+		for _, labelID := range m.TaskLabel[taskID] {
+			result[taskID] = append(result[taskID], *m.Labels[labelID])
+		}
+	}
+	return result, nil
+}
+
+func (m *MockLabelRepository) ListTaskIDsByLabel(ctx context.Context, labelID int) ([]int, error) {
+	var taskIDs []int
+	for taskID, labelIDs := range m.TaskLabel {
+		if slices.Contains(labelIDs, labelID) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	return taskIDs, nil
+}
+
+func TestAttachLabelAuditsDiff(t *testing.T) {
+	ctx := context.Background()
+	labelRepo := &MockLabelRepository{}
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, Title: "Task", OwnerId: 1}, nil
+		},
+	}
+	mockOutbox := &MockAuditOutboxRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, labels: labelRepo, outbox: mockOutbox})
+
+	bug, _ := service.CreateLabel(ctx, 1, &entity.CreateLabelRequest{Name: " bug ", Color: "#FF0000"})
+	if bug.Name != "bug" || bug.Color != "#ff0000" {
+		t.Fatalf("Expected normalized label, got %+v", bug)
+	}
+	backend, _ := service.CreateLabel(ctx, 1, &entity.CreateLabelRequest{Name: "backend"})
+
+	if _, err := service.AttachLabel(ctx, 1, 1, bug.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	task, err := service.AttachLabel(ctx, 1, 1, backend.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := labelNames(task.Labels); !slices.Equal(got, []string{"backend", "bug"}) {
+		t.Errorf("Expected labels sorted by name, got %v", got)
+	}
+
+	if len(mockOutbox.Messages) != 2 {
+		t.Fatalf("Expected 2 audit messages, got %d", len(mockOutbox.Messages))
+	}
+	change, ok := mockOutbox.Messages[1].Changes["labels"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected labels change in audit, got %v", mockOutbox.Messages[1].Changes)
+	}
+	if !slices.Equal(change["old"].([]string), []string{"bug"}) || !slices.Equal(change["new"].([]string), []string{"backend", "bug"}) {
+		t.Errorf("Unexpected labels change %v", change)
+	}
+	if len(mockOutbox.Messages[1].Changes) != 1 {
+		t.Errorf("Expected only labels to change, got %v", mockOutbox.Messages[1].Changes)
+	}
+
+	// Повторная привязка ничего не меняет и не пишет аудит
+	if _, err := service.AttachLabel(ctx, 1, 1, bug.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mockOutbox.Messages) != 2 {
+		t.Errorf("Expected no audit for repeated attach, got %d messages", len(mockOutbox.Messages))
+	}
+}
+
+func TestLabelOwnership(t *testing.T) {
+	ctx := context.Background()
+	labelRepo := &MockLabelRepository{}
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 2}, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, labels: labelRepo})
+
+	label, _ := service.CreateLabel(ctx, 1, &entity.CreateLabelRequest{Name: "bug"})
+
+	// Чужую метку нельзя ни переименовать, ни удалить
+	newName := "feature"
+	if _, err := service.UpdateLabel(ctx, 2, label.ID, &entity.UpdateLabelRequest{Name: &newName}); err != entity.ErrLabelNotFound {
+		t.Errorf("Expected ErrLabelNotFound, got %v", err)
+	}
+	if err := service.DeleteLabel(ctx, 2, label.ID); err != entity.ErrLabelNotFound {
+		t.Errorf("Expected ErrLabelNotFound, got %v", err)
+	}
+	// Свою метку нельзя повесить на чужую задачу
+	if _, err := service.AttachLabel(ctx, 1, 1, label.ID); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestCreateLabelValidation(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTaskService(taskServiceDeps{})
+
+	invalid := []*entity.CreateLabelRequest{
+		{Name: "   "},
+		{Name: "bug", Color: "red"},
+		{Name: "bug", Color: "#12345"},
+	}
+	for _, req := range invalid {
+		if _, err := service.CreateLabel(ctx, 1, req); err != entity.ErrInvalidLabelData {
+			t.Errorf("Expected ErrInvalidLabelData for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestListTasksLabelFilter(t *testing.T) {
+	ctx := context.Background()
+	var got *entity.ListTasksRequest
+	mockTaskRepo := &MockTaskRepository{
+		ListFunc: func(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+			got = req
+			return &entity.TaskPage{}, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	if _, err := service.ListTasks(ctx, 1, &entity.ListTasksRequest{LabelIDs: []int{3, 1, 3}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.LabelMatch != entity.LabelMatchAny || !slices.Equal(got.LabelIDs, []int{1, 3}) {
+		t.Errorf("Expected deduplicated label IDs with any match, got %v %q", got.LabelIDs, got.LabelMatch)
+	}
+
+	if _, err := service.ListTasks(ctx, 1, &entity.ListTasksRequest{LabelMatch: "none"}); err != entity.ErrInvalidFilter {
+		t.Errorf("Expected ErrInvalidFilter, got %v", err)
+	}
+}
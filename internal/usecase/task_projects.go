@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// projectAccess - доступ пользователя к задачам проекта по его роли.
+// Для неучастников и удаленных проектов возвращает ErrProjectNotFound
+func (s *TaskService) projectAccess(ctx context.Context, projectID int, userID int) (entity.TaskAccess, error) {
+	role, err := s.projectRepo.GetMemberRole(ctx, projectID, userID)
+	if err != nil {
+		return entity.TaskAccessNone, err
+	}
+	if role == "" {
+		return entity.TaskAccessNone, entity.ErrProjectNotFound
+	}
+	return role.Access(), nil
+}
+
+// archiveProjectTasks архивирует задачи удаляемого проекта и пишет аудит по каждой.
+// Вызывается в транзакции удаления проекта
+func (s *TaskService) archiveProjectTasks(ctx context.Context, projectID int, userID int) error {
+	tasks, err := s.taskRepo.ArchiveByProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	refs := make([]*entity.Task, len(tasks))
+	for i := range tasks {
+		refs[i] = &tasks[i]
+	}
+	if err := s.loadDetails(ctx, refs...); err != nil {
+		return err
+	}
+
+	for _, task := range refs {
+		oldTask := *task
+		oldTask.ArchivedAt = nil
+		if err := s.sendAuditMessage(ctx, entity.ActionUpdate, userID, task.ID, &oldTask, task, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestProjectRolesGrantTaskAccess(t *testing.T) {
+	ctx := context.Background()
+
+	// Задача проекта 7 владельца 1: admin 2, editor 3, viewer 4, посторонний 5
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1, Title: "Task", Status: entity.StatusPending, ProjectID: intPtr(7)}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			return &entity.Task{ID: id, OwnerId: 1, Version: version + 1, Title: updates["title"].(string), ProjectID: intPtr(7)}, nil
+		},
+	}
+	projects := &MockProjectRepository{
+		Projects: map[int]*entity.Project{7: {ID: 7, OwnerID: 1}},
+		Members: map[int]map[int]entity.ProjectRole{7: {
+			1: entity.ProjectRoleAdmin,
+			2: entity.ProjectRoleAdmin,
+			3: entity.ProjectRoleEditor,
+			4: entity.ProjectRoleViewer,
+		}},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, projects: projects})
+
+	tests := []struct {
+		userID    int
+		canRead   bool
+		canWrite  bool
+		canDelete bool
+	}{
+		{2, true, true, true},
+		{3, true, true, false},
+		{4, true, false, false},
+		{5, false, false, false},
+	}
+
+	for _, tt := range tests {
+		_, err := service.GetTask(ctx, 10, tt.userID)
+		if (err == nil) != tt.canRead {
+			t.Errorf("User %d: GetTask error %v, expected read=%t", tt.userID, err, tt.canRead)
+		}
+		_, err = service.UpdateTask(ctx, 10, tt.userID, &entity.UpdateTaskRequest{Title: "New"})
+		if (err == nil) != tt.canWrite {
+			t.Errorf("User %d: UpdateTask error %v, expected write=%t", tt.userID, err, tt.canWrite)
+		}
+		err = service.DeleteTask(ctx, 10, tt.userID, 0, entity.SubtaskCascade)
+		if (err == nil) != tt.canDelete {
+			t.Errorf("User %d: DeleteTask error %v, expected delete=%t", tt.userID, err, tt.canDelete)
+		}
+	}
+
+	// После удаления проекта роли больше не дают доступа
+	delete(projects.Projects, 7)
+	if _, err := service.GetTask(ctx, 10, 3); err != entity.ErrForbidden {
+		t.Errorf("Expected ErrForbidden after project deletion, got %v", err)
+	}
+}
+
+func TestCreateTaskInProject(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		CreateFunc: func(ctx context.Context, req *entity.CreateTaskRequest) (*entity.Task, error) {
+			return &entity.Task{ID: 20, OwnerId: req.OwnerId, ParentID: req.ParentID, ProjectID: req.ProjectID}, nil
+		},
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			// Родитель 10 принадлежит другому участнику проекта
+			return &entity.Task{ID: taskId, OwnerId: 1, ProjectID: intPtr(7)}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	projects := &MockProjectRepository{
+		Projects: map[int]*entity.Project{7: {ID: 7, OwnerID: 1}, 8: {ID: 8, OwnerID: 3}},
+		Members: map[int]map[int]entity.ProjectRole{
+			7: {1: entity.ProjectRoleAdmin, 3: entity.ProjectRoleEditor, 4: entity.ProjectRoleViewer},
+			8: {3: entity.ProjectRoleAdmin},
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, projects: projects})
+
+	if _, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Task", ProjectID: intPtr(7)}, 4); err != entity.ErrForbidden {
+		t.Errorf("Expected viewer to be forbidden, got %v", err)
+	}
+	if _, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Task", ProjectID: intPtr(7)}, 5); err != entity.ErrProjectNotFound {
+		t.Errorf("Expected ErrProjectNotFound for non-member, got %v", err)
+	}
+
+	// Подзадача наследует проект родителя и может принадлежать другому участнику
+	task, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Subtask", ParentID: intPtr(10)}, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if task.ProjectID == nil || *task.ProjectID != 7 {
+		t.Errorf("Expected subtask in project 7, got %v", task.ProjectID)
+	}
+	if _, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Subtask", ParentID: intPtr(10), ProjectID: intPtr(8)}, 3); err != entity.ErrInvalidParent {
+		t.Errorf("Expected ErrInvalidParent for parent from another project, got %v", err)
+	}
+}
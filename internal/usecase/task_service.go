@@ -2,37 +2,86 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"reflect"
+	"slices"
 	"time"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/repository"
 )
 
-// RabbitMQPublisher интерфейс для публикации в RabbitMQ
-type RabbitMQPublisher interface {
-	PublishAuditMessage(ctx context.Context, message *entity.AuditMessage) error
-}
-
 type TaskService struct {
-	taskRepo  repository.ITaskRepository
-	userRepo  repository.IUserRepository
-	auditRepo repository.ITaskAuditRepository
-	rabbitMQ  RabbitMQPublisher
+	taskRepo       repository.ITaskRepository
+	userRepo       repository.IUserRepository
+	labelRepo      repository.ILabelRepository
+	dependencyRepo repository.IDependencyRepository
+	collabRepo     repository.ICollaboratorRepository
+	projectRepo    repository.IProjectRepository
+	commentRepo    repository.ICommentRepository
+	attachmentRepo repository.IAttachmentRepository
+	auditRepo      repository.ITaskAuditRepository
+	outboxRepo     repository.IAuditOutboxRepository
+	transactor     repository.ITransactor
+	blobs          *BlobService
+	statuses       *StatusMachine
+	subtasks       entity.SubtaskPolicy // что делать с подзадачами при удалении по умолчанию
+	attachments    entity.AttachmentLimits
 }
 
 func NewTaskService(
 	taskRepo repository.ITaskRepository,
 	userRepo repository.IUserRepository,
+	labelRepo repository.ILabelRepository,
+	dependencyRepo repository.IDependencyRepository,
+	collabRepo repository.ICollaboratorRepository,
+	projectRepo repository.IProjectRepository,
+	commentRepo repository.ICommentRepository,
+	attachmentRepo repository.IAttachmentRepository,
 	auditRepo repository.ITaskAuditRepository,
-	rabbitMQ RabbitMQPublisher,
+	outboxRepo repository.IAuditOutboxRepository,
+	transactor repository.ITransactor,
+	blobs *BlobService,
 ) *TaskService {
 	return &TaskService{
-		taskRepo:  taskRepo,
-		userRepo:  userRepo,
-		auditRepo: auditRepo,
-		rabbitMQ:  rabbitMQ,
+		taskRepo:       taskRepo,
+		userRepo:       userRepo,
+		labelRepo:      labelRepo,
+		dependencyRepo: dependencyRepo,
+		collabRepo:     collabRepo,
+		projectRepo:    projectRepo,
+		commentRepo:    commentRepo,
+		attachmentRepo: attachmentRepo,
+		auditRepo:      auditRepo,
+		outboxRepo:     outboxRepo,
+		transactor:     transactor,
+		blobs:          blobs,
+		statuses:       defaultStatusMachine(),
+		subtasks:       entity.SubtaskReparent,
+		attachments:    DefaultAttachmentLimits(),
+	}
+}
+
+// SetStatusMachine заменяет граф переходов статусов (настраивается при старте)
+func (s *TaskService) SetStatusMachine(statuses *StatusMachine) {
+	s.statuses = statuses
+}
+
+// SetSubtaskPolicy задает поведение DeleteTask для подзадач, если запрос его не указал
+func (s *TaskService) SetSubtaskPolicy(policy entity.SubtaskPolicy) error {
+	if !policy.IsValid() {
+		return fmt.Errorf("unknown subtask policy %q", policy)
+	}
+	s.subtasks = policy
+	return nil
+}
+
+func defaultStatusMachine() *StatusMachine {
+	statuses, err := NewStatusMachine(DefaultStatusTransitions())
+	if err != nil {
+		panic(err) // граф по умолчанию всегда валиден
 	}
+	return statuses
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, req *entity.CreateTaskRequest, userID int) (*entity.Task, error) {
@@ -48,48 +97,67 @@ func (s *TaskService) CreateTask(ctx context.Context, req *entity.CreateTaskRequ
 	// 2. Устанавливаем владельца из контекста (безопасность!)
 	req.OwnerId = userID
 
-	// 3. Создаем задачу
-	task, err := s.taskRepo.Create(ctx, req)
+	if req.Status == "" {
+		req.Status = entity.StatusPending
+	}
+	if req.Priority == "" {
+		req.Priority = entity.PriorityMedium
+	}
+	if !req.Status.IsValid() || !req.Priority.IsValid() || !validSchedule(req.StartAt, req.DueAt) {
+		return nil, entity.ErrInvalidTaskData
+	}
+	if req.ParentID != nil {
+		parent, err := s.loadParent(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		// Подзадача создается в проекте родителя
+		if req.ProjectID == nil {
+			req.ProjectID = parent.ProjectID
+		}
+		if err := s.checkParent(ctx, parent, userID, req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if req.ProjectID != nil {
+		access, err := s.projectAccess(ctx, *req.ProjectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if access < entity.TaskAccessWrite {
+			return nil, entity.ErrForbidden
+		}
+	}
+
+	// 3. Создаем задачу и кладем аудит в outbox в одной транзакции
+	var task *entity.Task
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		task, err = s.taskRepo.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.sendAuditMessage(ctx, entity.ActionCreate, userID, task.ID, nil, task, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Асинхронно отправляем аудит
-	s.sendAuditMessage(ctx, entity.ActionCreate, userID, task.ID, nil, task, nil)
-
 	return task, nil
 }
 
+// GetTask возвращает задачу владельцу, исполнителю или пользователю с выданным доступом
 func (s *TaskService) GetTask(ctx context.Context, taskID int, userID int) (*entity.Task, error) {
-	task, err := s.taskRepo.GetByTaskId(ctx, taskID)
-	if err != nil {
-		return nil, err
-	}
-	if task == nil {
-		return nil, entity.ErrTaskNotFound
-	}
-
-	// Проверяем права доступа
-	if task.OwnerId != userID {
-		return nil, entity.ErrForbidden
-	}
-
-	return task, nil
+	return s.authorizeTask(ctx, taskID, userID, entity.TaskAccessRead)
 }
 
 func (s *TaskService) UpdateTask(ctx context.Context, taskID int, userID int, req *entity.UpdateTaskRequest) (*entity.Task, error) {
-	// 1. Получаем текущую задачу
-	oldTask, err := s.taskRepo.GetByTaskId(ctx, taskID)
+	// 1-2. Получаем текущую задачу, проверяем право изменения и ожидаемую клиентом версию
+	oldTask, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessWrite)
 	if err != nil {
 		return nil, err
 	}
-	if oldTask == nil {
-		return nil, entity.ErrTaskNotFound
-	}
-
-	// 2. Проверяем права доступа
-	if oldTask.OwnerId != userID {
-		return nil, entity.ErrForbidden
+	if req.ExpectedVersion != 0 && req.ExpectedVersion != oldTask.Version {
+		return nil, entity.ErrVersionMismatch
 	}
 
 	// 3. Подготавливаем обновления
@@ -103,58 +171,224 @@ func (s *TaskService) UpdateTask(ctx context.Context, taskID int, userID int, re
 		updates["description"] = *req.Description
 	}
 
-	if req.Status != "" {
+	if req.Status != "" && req.Status != oldTask.Status {
+		if err := s.statuses.CheckTransition(oldTask.Status, req.Status); err != nil {
+			return nil, err
+		}
 		updates["status"] = req.Status
+
+		// Фиксируем момент завершения/отмены, при выходе из статуса - сбрасываем
+		now := time.Now()
+		switch {
+		case req.Status == entity.StatusCompleted:
+			updates["completed_at"] = &now
+		case oldTask.Status == entity.StatusCompleted:
+			updates["completed_at"] = (*time.Time)(nil)
+		}
+		switch {
+		case req.Status == entity.StatusCancelled:
+			updates["cancelled_at"] = &now
+		case oldTask.Status == entity.StatusCancelled:
+			updates["cancelled_at"] = (*time.Time)(nil)
+		}
+	}
+
+	// Для дат важно наличие поля: пустое значение очищает дату
+	startAt, dueAt := oldTask.StartAt, oldTask.DueAt
+	if req.StartAt != nil {
+		startAt = req.StartAt.Time
+		updates["start_at"] = startAt
+	}
+	if req.DueAt != nil {
+		dueAt = req.DueAt.Time
+		updates["due_at"] = dueAt
+	}
+	if !validSchedule(startAt, dueAt) {
+		return nil, entity.ErrInvalidTaskData
+	}
+
+	if req.Priority != nil {
+		if !req.Priority.IsValid() {
+			return nil, entity.ErrInvalidTaskData
+		}
+		updates["priority"] = *req.Priority
+	}
+
+	// Перенос в другую ветку дерева: родитель должен быть своей задачей или задачей того же проекта
+	var newParentID *int
+	if req.ParentID != nil && !equalIDs(req.ParentID.ID, oldTask.ParentID) {
+		newParentID = req.ParentID.ID
+		if newParentID != nil {
+			if *newParentID == taskID {
+				return nil, entity.ErrTaskCycle
+			}
+			parent, err := s.loadParent(ctx, *newParentID)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.checkParent(ctx, parent, oldTask.OwnerId, oldTask.ProjectID, userID); err != nil {
+				return nil, err
+			}
+		}
+		updates["parent_id"] = newParentID
 	}
 
 	if len(updates) == 0 {
 		return nil, entity.ErrNoFieldsToUpdate
 	}
 
-	// 4. Обновляем задачу
-	updatedTask, err := s.taskRepo.Update(ctx, taskID, updates)
+	// 4. Обновляем задачу и кладем аудит в outbox в одной транзакции.
+	// Обновление проходит, только если задачу не изменили после чтения в п.1
+	var updatedTask *entity.Task
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Блокирующие задачи проверяем под блокировкой графа, иначе параллельный
+		// AddDependency может добавить открытую блокирующую задачу после проверки
+		if status, ok := updates["status"].(entity.TaskStatus); ok && status.BlockedByOpenTasks() {
+			if err := s.dependencyRepo.LockGraph(ctx); err != nil {
+				return err
+			}
+			if err := s.checkNotBlocked(ctx, taskID, status); err != nil {
+				return err
+			}
+		}
+
+		// Цикл проверяем под блокировкой, иначе два встречных переноса могут его создать
+		if newParentID != nil {
+			if err := s.taskRepo.LockHierarchy(ctx, oldTask.OwnerId, oldTask.ProjectID); err != nil {
+				return err
+			}
+			if err := s.checkNoCycle(ctx, taskID, *newParentID); err != nil {
+				return err
+			}
+		}
+
+		updatedTask, err = s.taskRepo.Update(ctx, taskID, oldTask.Version, updates)
+		if err != nil {
+			return err
+		}
+		updatedTask.Labels = oldTask.Labels
+		updatedTask.AssigneeIDs = oldTask.AssigneeIDs
+		return s.sendAuditMessage(ctx, entity.ActionUpdate, userID, taskID, oldTask, updatedTask, updates)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. Асинхронно отправляем аудит
-	s.sendAuditMessage(ctx, entity.ActionUpdate, userID, taskID, oldTask, updatedTask, updates)
-
 	return updatedTask, nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskID int, userID int) error {
-	// 1. Получаем задачу (для аудита и проверки прав)
-	task, err := s.taskRepo.GetByTaskId(ctx, taskID)
-	if err != nil {
-		return err
+// DeleteTask удаляет задачу. expectedVersion - ожидаемая клиентом версия, 0 - без проверки.
+// policy определяет судьбу подзадач, пустое значение - настройка сервиса
+func (s *TaskService) DeleteTask(ctx context.Context, taskID int, userID int, expectedVersion int, policy entity.SubtaskPolicy) error {
+	if policy == "" {
+		policy = s.subtasks
 	}
-	if task == nil {
-		return entity.ErrTaskNotFound
+	if !policy.IsValid() {
+		return entity.ErrInvalidTaskData
 	}
 
-	// 2. Проверяем права доступа
-	if task.OwnerId != userID {
-		return entity.ErrForbidden
+	// 1-2. Получаем задачу (для аудита), удалять может только владелец
+	task, err := s.authorizeTask(ctx, taskID, userID, entity.TaskAccessOwner)
+	if err != nil {
+		return err
+	}
+	if expectedVersion != 0 && expectedVersion != task.Version {
+		return entity.ErrVersionMismatch
 	}
 
-	// 3. Удаляем задачу
-	err = s.taskRepo.Delete(ctx, taskID)
+	// 3. Удаляем задачу (и поддерево или переносим детей) и кладем аудит в outbox в одной транзакции
+	var attachments []entity.TaskAttachment
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.LockHierarchy(ctx, task.OwnerId, task.ProjectID); err != nil {
+			return err
+		}
+		if policy == entity.SubtaskCascade {
+			attachments, err = s.deleteSubtree(ctx, task, userID)
+		} else {
+			attachments, err = s.deleteReparent(ctx, task, userID)
+		}
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	// 4. Асинхронно отправляем аудит
-	s.sendAuditMessage(ctx, entity.ActionDelete, userID, taskID, task, nil, nil)
-
+	// 4. Файлы вложений удаляем только после коммита
+	for i := range attachments {
+		s.blobs.Remove(ctx, &attachments[i].Blob)
+	}
 	return nil
 }
 
-func (s *TaskService) ListTasks(ctx context.Context, userID int, status string) ([]entity.Task, error) {
-	return s.taskRepo.List(ctx, userID, status)
+// taskSortFields - поля, по которым можно сортировать задачи
+var taskSortFields = []string{
+	entity.TaskSortCreatedAt,
+	entity.TaskSortUpdatedAt,
+	entity.TaskSortTitle,
+	entity.TaskSortID,
 }
 
-// Вспомогательный метод для отправки аудита
+// ListTasks возвращает задачи пользователя: свои, назначенные ему или доступные ему (req.Mode).
+// С фильтром по проекту без Mode - все задачи проекта, если пользователь его участник
+func (s *TaskService) ListTasks(ctx context.Context, userID int, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+	// Список всегда ограничен задачами вызывающего пользователя или его проекта
+	req.UserID = userID
+	if req.ProjectID != nil {
+		if _, err := s.projectAccess(ctx, *req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	} else if req.Mode == "" {
+		req.Mode = entity.TaskListOwned
+	}
+	if req.Mode != "" && !req.Mode.IsValid() {
+		return nil, entity.ErrInvalidFilter
+	}
+
+	return s.listTasks(ctx, req)
+}
+
+func (s *TaskService) listTasks(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+	if req.Status != "" && !entity.TaskStatus(req.Status).IsValid() {
+		return nil, entity.ErrInvalidFilter
+	}
+	if req.Priority != "" && !req.Priority.IsValid() {
+		return nil, entity.ErrInvalidFilter
+	}
+	if req.DueAfter != nil && req.DueBefore != nil && !req.DueAfter.Before(*req.DueBefore) {
+		return nil, entity.ErrInvalidFilter
+	}
+	if req.LabelMatch == "" {
+		req.LabelMatch = entity.LabelMatchAny
+	}
+	if !req.LabelMatch.IsValid() {
+		return nil, entity.ErrInvalidFilter
+	}
+	// Для all важно, чтобы ID не повторялись, а для курсора - чтобы порядок был стабильным
+	slices.Sort(req.LabelIDs)
+	req.LabelIDs = slices.Compact(req.LabelIDs)
+
+	if err := normalizePage(&req.Page, entity.TaskSortCreatedAt, taskSortFields); err != nil {
+		return nil, err
+	}
+
+	page, err := s.taskRepo.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*entity.Task, len(page.Tasks))
+	for i := range page.Tasks {
+		tasks[i] = &page.Tasks[i]
+	}
+	if err := s.loadDetails(ctx, tasks...); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Вспомогательный метод для отправки аудита: сообщение кладется в outbox
+// в транзакции из ctx, а в RabbitMQ его публикует OutboxRelay
 func (s *TaskService) sendAuditMessage(
 	ctx context.Context,
 	action entity.ActionType,
@@ -163,7 +397,7 @@ func (s *TaskService) sendAuditMessage(
 	oldTask *entity.Task,
 	newTask *entity.Task,
 	updates map[string]interface{},
-) {
+) error {
 	auditMsg := &entity.AuditMessage{
 		Action:    action,
 		UserID:    userID,
@@ -175,57 +409,74 @@ func (s *TaskService) sendAuditMessage(
 	switch action {
 	case entity.ActionCreate:
 		if newTask != nil {
-			auditMsg.NewValues = map[string]interface{}{
-				"title":       newTask.Title,
-				"description": newTask.Description,
-				"status":      newTask.Status,
-				"owner_id":    newTask.OwnerId,
-			}
+			auditMsg.NewValues = taskAuditValues(newTask)
 		}
 
 	case entity.ActionUpdate:
 		if oldTask != nil && newTask != nil {
-			auditMsg.OldValues = map[string]interface{}{
-				"title":       oldTask.Title,
-				"description": oldTask.Description,
-				"status":      oldTask.Status,
-			}
-			auditMsg.NewValues = map[string]interface{}{
-				"title":       newTask.Title,
-				"description": newTask.Description,
-				"status":      newTask.Status,
-			}
-			// Вычисляем изменения
-			changes := make(map[string]interface{})
-			if oldTask.Title != newTask.Title {
-				changes["title"] = map[string]interface{}{"old": oldTask.Title, "new": newTask.Title}
-			}
-			if oldTask.Description != newTask.Description {
-				changes["description"] = map[string]interface{}{"old": oldTask.Description, "new": newTask.Description}
-			}
-			if oldTask.Status != newTask.Status {
-				changes["status"] = map[string]interface{}{"old": oldTask.Status, "new": newTask.Status}
-			}
-			auditMsg.Changes = changes
+			auditMsg.OldValues = taskAuditValues(oldTask)
+			auditMsg.NewValues = taskAuditValues(newTask)
+			auditMsg.Changes = auditChanges(auditMsg.OldValues, auditMsg.NewValues)
 		}
 
 	case entity.ActionDelete:
 		if oldTask != nil {
-			auditMsg.OldValues = map[string]interface{}{
-				"title":       oldTask.Title,
-				"description": oldTask.Description,
-				"status":      oldTask.Status,
-				"owner_id":    oldTask.OwnerId,
-			}
+			auditMsg.OldValues = taskAuditValues(oldTask)
 		}
 	}
 
-	// Асинхронная отправка в RabbitMQ
-	go func() {
-		if err := s.rabbitMQ.PublishAuditMessage(context.Background(), auditMsg); err != nil {
-			log.Printf("❌ Ошибка отправки аудита в RabbitMQ: %v", err)
-		} else {
-			log.Printf("Аудит отправлен в RabbitMQ: %s задача ID=%d", action, taskID)
+	if err := s.outboxRepo.Add(ctx, auditMsg); err != nil {
+		return fmt.Errorf("failed to save audit message to outbox: %w", err)
+	}
+	return nil
+}
+
+// auditChanges - поля, значения которых различаются, в виде {"field": {"old": ..., "new": ...}}
+func auditChanges(oldValues, newValues map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for field, newValue := range newValues {
+		if oldValue := oldValues[field]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = map[string]interface{}{"old": oldValue, "new": newValue}
 		}
-	}()
+	}
+	return changes
+}
+
+// taskAuditValues - поля задачи, которые попадают в аудит
+func taskAuditValues(task *entity.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"title":        task.Title,
+		"description":  task.Description,
+		"status":       task.Status,
+		"owner_id":     task.OwnerId,
+		"start_at":     formatAuditTime(task.StartAt),
+		"due_at":       formatAuditTime(task.DueAt),
+		"priority":     task.Priority,
+		"labels":       labelNames(task.Labels),
+		"parent_id":    formatAuditID(task.ParentID),
+		"assignee_ids": append([]int{}, task.AssigneeIDs...),
+		"project_id":   formatAuditID(task.ProjectID),
+		"archived_at":  formatAuditTime(task.ArchivedAt),
+	}
+}
+
+// formatAuditTime форматирует необязательную дату для аудита (nil -> null)
+func formatAuditTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// equalIDs сравнивает необязательные ссылки по значению
+func equalIDs(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validSchedule проверяет, что начало не позже срока
+func validSchedule(startAt, dueAt *time.Time) bool {
+	return startAt == nil || dueAt == nil || !startAt.After(*dueAt)
 }
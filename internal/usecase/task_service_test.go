@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,9 +14,13 @@ import (
 type MockTaskRepository struct {
 	CreateFunc      func(ctx context.Context, task *entity.CreateTaskRequest) (*entity.Task, error)
 	GetByTaskIdFunc func(ctx context.Context, taskId int) (*entity.Task, error)
-	UpdateFunc      func(ctx context.Context, id int, updates map[string]interface{}) (*entity.Task, error)
-	DeleteFunc      func(ctx context.Context, id int) error
-	ListFunc        func(ctx context.Context, ownerID int, status string) ([]entity.Task, error)
+	UpdateFunc      func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error)
+	DeleteFunc      func(ctx context.Context, id int, version int) error
+	ListFunc        func(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error)
+	GetSubtreeFunc  func(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error)
+	StatusesFunc    func(ctx context.Context, rootID int) ([]entity.TaskStatusNode, error)
+	AncestorsFunc   func(ctx context.Context, taskID int) ([]int, error)
+	ArchiveFunc     func(ctx context.Context, projectID int) ([]entity.Task, error)
 }
 
 var _ repository.ITaskRepository = (*MockTaskRepository)(nil)
@@ -34,34 +39,69 @@ func (m *MockTaskRepository) GetByTaskId(ctx context.Context, taskId int) (*enti
 	return nil, nil
 }
 
-func (m *MockTaskRepository) Update(ctx context.Context, id int, updates map[string]interface{}) (*entity.Task, error) {
+func (m *MockTaskRepository) Update(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
 	if m.UpdateFunc != nil {
-		return m.UpdateFunc(ctx, id, updates)
+		return m.UpdateFunc(ctx, id, version, updates)
 	}
 	return nil, nil
 }
 
-func (m *MockTaskRepository) Delete(ctx context.Context, id int) error {
+func (m *MockTaskRepository) Delete(ctx context.Context, id int, version int) error {
 	if m.DeleteFunc != nil {
-		return m.DeleteFunc(ctx, id)
+		return m.DeleteFunc(ctx, id, version)
 	}
 	return nil
 }
 
-func (m *MockTaskRepository) List(ctx context.Context, ownerID int, status string) ([]entity.Task, error) {
+func (m *MockTaskRepository) List(ctx context.Context, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
 	if m.ListFunc != nil {
-		return m.ListFunc(ctx, ownerID, status)
+		return m.ListFunc(ctx, req)
+	}
+	return &entity.TaskPage{}, nil
+}
+
+func (m *MockTaskRepository) GetSubtree(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error) {
+	if m.GetSubtreeFunc != nil {
+		return m.GetSubtreeFunc(ctx, rootID, maxDepth)
+	}
+	return nil, nil
+}
+
+func (m *MockTaskRepository) ListSubtreeStatuses(ctx context.Context, rootID int) ([]entity.TaskStatusNode, error) {
+	if m.StatusesFunc != nil {
+		return m.StatusesFunc(ctx, rootID)
+	}
+	return nil, nil
+}
+
+func (m *MockTaskRepository) GetAncestorIDs(ctx context.Context, taskID int) ([]int, error) {
+	if m.AncestorsFunc != nil {
+		return m.AncestorsFunc(ctx, taskID)
+	}
+	return nil, nil
+}
+
+func (m *MockTaskRepository) LockHierarchy(ctx context.Context, ownerID int, projectID *int) error {
+	return nil
+}
+
+func (m *MockTaskRepository) ArchiveByProject(ctx context.Context, projectID int) ([]entity.Task, error) {
+	if m.ArchiveFunc != nil {
+		return m.ArchiveFunc(ctx, projectID)
 	}
 	return nil, nil
 }
 
 // MockUserRepository - мок для IUserRepository
 type MockUserRepository struct {
-	GetByIdFunc func(ctx context.Context, id int) (*entity.User, error)
-	CreateFunc  func(ctx context.Context, user *entity.CreateUserRequest) (*entity.User, error)
-	UpdateFunc  func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error)
-	ListFunc    func(ctx context.Context) ([]entity.User, error)
-	DeleteFunc  func(ctx context.Context, id int) error
+	GetByIdFunc        func(ctx context.Context, id int) (*entity.User, error)
+	GetByEmailFunc     func(ctx context.Context, email string) (*entity.User, error)
+	CreateFunc         func(ctx context.Context, user *entity.CreateUserRequest) (*entity.User, error)
+	CreateWithAuthFunc func(ctx context.Context, name, email, passwordHash string) (*entity.User, error)
+	UpdateFunc         func(ctx context.Context, id int, updates map[string]interface{}) (*entity.User, error)
+	ListFunc           func(ctx context.Context) ([]entity.User, error)
+	ListPageFunc       func(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserPage, error)
+	DeleteFunc         func(ctx context.Context, id int) error
 }
 
 var _ repository.IUserRepository = (*MockUserRepository)(nil)
@@ -73,6 +113,20 @@ func (m *MockUserRepository) GetById(ctx context.Context, id int) (*entity.User,
 	return nil, nil
 }
 
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	if m.GetByEmailFunc != nil {
+		return m.GetByEmailFunc(ctx, email)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) CreateWithAuth(ctx context.Context, name, email, passwordHash string) (*entity.User, error) {
+	if m.CreateWithAuthFunc != nil {
+		return m.CreateWithAuthFunc(ctx, name, email, passwordHash)
+	}
+	return nil, nil
+}
+
 func (m *MockUserRepository) Create(ctx context.Context, user *entity.CreateUserRequest) (*entity.User, error) {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(ctx, user)
@@ -94,6 +148,13 @@ func (m *MockUserRepository) List(ctx context.Context) ([]entity.User, error) {
 	return nil, nil
 }
 
+func (m *MockUserRepository) ListPage(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserPage, error) {
+	if m.ListPageFunc != nil {
+		return m.ListPageFunc(ctx, req)
+	}
+	return &entity.UserPage{}, nil
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -103,8 +164,9 @@ func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
 
 // MockTaskAuditRepository - мок для ITaskAuditRepository
 type MockTaskAuditRepository struct {
-	CreateFunc           func(ctx context.Context, audit *entity.TaskAudit) error
-	GetByTaskAuditIdFunc func(ctx context.Context, taskAuditId int) ([]entity.TaskAudit, error)
+	CreateFunc         func(ctx context.Context, audit *entity.TaskAudit) error
+	ListByTaskFunc     func(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error)
+	GetTaskOwnerIDFunc func(ctx context.Context, taskID int) (int, error)
 }
 
 var _ repository.ITaskAuditRepository = (*MockTaskAuditRepository)(nil)
@@ -116,25 +178,115 @@ func (m *MockTaskAuditRepository) Create(ctx context.Context, audit *entity.Task
 	return nil
 }
 
-func (m *MockTaskAuditRepository) GetByTaskAuditId(ctx context.Context, taskAuditId int) ([]entity.TaskAudit, error) {
-	if m.GetByTaskAuditIdFunc != nil {
-		return m.GetByTaskAuditIdFunc(ctx, taskAuditId)
+func (m *MockTaskAuditRepository) ListByTask(ctx context.Context, req *entity.TaskHistoryRequest) (*entity.TaskAuditPage, error) {
+	if m.ListByTaskFunc != nil {
+		return m.ListByTaskFunc(ctx, req)
 	}
-	return nil, nil
+	return &entity.TaskAuditPage{}, nil
 }
 
-// MockRabbitMQPublisher - мок для RabbitMQPublisher
-type MockRabbitMQPublisher struct {
-	PublishAuditMessageFunc func(ctx context.Context, message *entity.AuditMessage) error
+func (m *MockTaskAuditRepository) GetTaskOwnerID(ctx context.Context, taskID int) (int, error) {
+	if m.GetTaskOwnerIDFunc != nil {
+		return m.GetTaskOwnerIDFunc(ctx, taskID)
+	}
+	return 0, nil
+}
+
+// MockAuditOutboxRepository - мок для IAuditOutboxRepository
+type MockAuditOutboxRepository struct {
+	AddFunc  func(ctx context.Context, message *entity.AuditMessage) error
+	Messages []*entity.AuditMessage
 }
 
-func (m *MockRabbitMQPublisher) PublishAuditMessage(ctx context.Context, message *entity.AuditMessage) error {
-	if m.PublishAuditMessageFunc != nil {
-		return m.PublishAuditMessageFunc(ctx, message)
+var _ repository.IAuditOutboxRepository = (*MockAuditOutboxRepository)(nil)
+
+func (m *MockAuditOutboxRepository) Add(ctx context.Context, message *entity.AuditMessage) error {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, message)
 	}
+	m.Messages = append(m.Messages, message)
+	return nil
+}
+
+func (m *MockAuditOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	return nil, nil
+}
+
+func (m *MockAuditOutboxRepository) MarkSent(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (m *MockAuditOutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	return nil
+}
+
+func (m *MockAuditOutboxRepository) MarkDead(ctx context.Context, id int64, lastError string) error {
+	return nil
+}
+
+func (m *MockAuditOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// MockTransactor - мок для ITransactor, просто вызывает fn
+type MockTransactor struct{}
+
+var _ repository.ITransactor = (*MockTransactor)(nil)
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// taskServiceDeps - зависимости newTestTaskService, незаданные заменяются пустыми моками
+type taskServiceDeps struct {
+	tasks         *MockTaskRepository
+	users         *MockUserRepository
+	labels        *MockLabelRepository
+	dependencies  *MockDependencyRepository
+	collaborators *MockCollaboratorRepository
+	projects      *MockProjectRepository
+	comments      *MockCommentRepository
+	attachments   *MockAttachmentRepository
+	audit         *MockTaskAuditRepository
+	outbox        *MockAuditOutboxRepository
+	blobs         *BlobService
+}
+
+func newTestTaskService(deps taskServiceDeps) *TaskService {
+	if deps.tasks == nil {
+		deps.tasks = &MockTaskRepository{}
+	}
+	if deps.users == nil {
+		deps.users = &MockUserRepository{}
+	}
+	if deps.labels == nil {
+		deps.labels = &MockLabelRepository{}
+	}
+	if deps.dependencies == nil {
+		deps.dependencies = &MockDependencyRepository{}
+	}
+	if deps.collaborators == nil {
+		deps.collaborators = &MockCollaboratorRepository{}
+	}
+	if deps.projects == nil {
+		deps.projects = &MockProjectRepository{}
+	}
+	if deps.comments == nil {
+		deps.comments = &MockCommentRepository{}
+	}
+	if deps.attachments == nil {
+		deps.attachments = &MockAttachmentRepository{}
+	}
+	if deps.audit == nil {
+		deps.audit = &MockTaskAuditRepository{}
+	}
+	if deps.outbox == nil {
+		deps.outbox = &MockAuditOutboxRepository{}
+	}
+	return NewTaskService(deps.tasks, deps.users, deps.labels, deps.dependencies, deps.collaborators, deps.projects,
+		deps.comments, deps.attachments, deps.audit, deps.outbox, &MockTransactor{}, deps.blobs)
+}
+
 // Tests
 
 func TestCreateTaskSuccess(t *testing.T) {
@@ -166,9 +318,9 @@ func TestCreateTaskSuccess(t *testing.T) {
 	}
 
 	mockAuditRepo := &MockTaskAuditRepository{}
-	mockRabbitMQ := &MockRabbitMQPublisher{}
+	mockOutbox := &MockAuditOutboxRepository{}
 
-	service := NewTaskService(mockTaskRepo, mockUserRepo, mockAuditRepo, mockRabbitMQ)
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, audit: mockAuditRepo, outbox: mockOutbox})
 
 	req := &entity.CreateTaskRequest{
 		Title:       "Test Task",
@@ -188,6 +340,38 @@ func TestCreateTaskSuccess(t *testing.T) {
 	if result.Title != mockTask.Title {
 		t.Errorf("Expected title %s, got %s", mockTask.Title, result.Title)
 	}
+
+	if len(mockOutbox.Messages) != 1 || mockOutbox.Messages[0].Action != entity.ActionCreate {
+		t.Errorf("Expected one Create audit message in outbox, got %v", mockOutbox.Messages)
+	}
+}
+
+func TestCreateTaskOutboxFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	mockTaskRepo := &MockTaskRepository{
+		CreateFunc: func(ctx context.Context, task *entity.CreateTaskRequest) (*entity.Task, error) {
+			return &entity.Task{ID: 1, Title: task.Title, OwnerId: task.OwnerId}, nil
+		},
+	}
+	mockOutbox := &MockAuditOutboxRepository{
+		AddFunc: func(ctx context.Context, message *entity.AuditMessage) error {
+			return errors.New("outbox is unavailable")
+		},
+	}
+
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, outbox: mockOutbox})
+
+	// Без записи в outbox задача не должна считаться созданной
+	result, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Test Task"}, 1)
+	if err == nil {
+		t.Fatalf("Expected error, got task %v", result)
+	}
 }
 
 func TestCreateTaskUserNotFound(t *testing.T) {
@@ -201,9 +385,9 @@ func TestCreateTaskUserNotFound(t *testing.T) {
 
 	mockTaskRepo := &MockTaskRepository{}
 	mockAuditRepo := &MockTaskAuditRepository{}
-	mockRabbitMQ := &MockRabbitMQPublisher{}
+	mockOutbox := &MockAuditOutboxRepository{}
 
-	service := NewTaskService(mockTaskRepo, mockUserRepo, mockAuditRepo, mockRabbitMQ)
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, audit: mockAuditRepo, outbox: mockOutbox})
 
 	req := &entity.CreateTaskRequest{
 		Title:       "Test Task",
@@ -250,16 +434,16 @@ func TestUpdateTaskSuccess(t *testing.T) {
 			}
 			return nil, nil
 		},
-		UpdateFunc: func(ctx context.Context, id int, updates map[string]interface{}) (*entity.Task, error) {
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
 			return updatedTask, nil
 		},
 	}
 
 	mockUserRepo := &MockUserRepository{}
 	mockAuditRepo := &MockTaskAuditRepository{}
-	mockRabbitMQ := &MockRabbitMQPublisher{}
+	mockOutbox := &MockAuditOutboxRepository{}
 
-	service := NewTaskService(mockTaskRepo, mockUserRepo, mockAuditRepo, mockRabbitMQ)
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, audit: mockAuditRepo, outbox: mockOutbox})
 
 	req := &entity.UpdateTaskRequest{
 		Title:  "New Title",
@@ -280,6 +464,76 @@ func TestUpdateTaskSuccess(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskVersionMismatch(t *testing.T) {
+	ctx := context.Background()
+	updateCalled := false
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, Title: "Title", OwnerId: 1, Version: 3}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			updateCalled = true
+			return nil, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	_, err := service.UpdateTask(ctx, 1, 1, &entity.UpdateTaskRequest{Title: "New Title", ExpectedVersion: 2})
+	if err != entity.ErrVersionMismatch {
+		t.Errorf("Expected ErrVersionMismatch, got %v", err)
+	}
+	if updateCalled {
+		t.Errorf("Update must not be called on version mismatch")
+	}
+}
+
+func TestUpdateTaskUsesReadVersion(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, Title: "Title", OwnerId: 1, Version: 3}, nil
+		},
+		// Задачу изменили между чтением и записью
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			if version != 3 {
+				t.Errorf("Expected update guarded by version 3, got %d", version)
+			}
+			return nil, entity.ErrVersionMismatch
+		},
+	}
+	mockOutbox := &MockAuditOutboxRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, outbox: mockOutbox})
+
+	_, err := service.UpdateTask(ctx, 1, 1, &entity.UpdateTaskRequest{Title: "New Title"})
+	if err != entity.ErrVersionMismatch {
+		t.Errorf("Expected ErrVersionMismatch, got %v", err)
+	}
+	if len(mockOutbox.Messages) != 0 {
+		t.Errorf("Expected no audit for lost update, got %v", mockOutbox.Messages)
+	}
+}
+
+func TestDeleteTaskVersionMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 5}, nil
+		},
+		DeleteFunc: func(ctx context.Context, id int, version int) error {
+			t.Errorf("Delete must not be called on version mismatch")
+			return nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	if err := service.DeleteTask(ctx, 1, 1, 4, ""); err != entity.ErrVersionMismatch {
+		t.Errorf("Expected ErrVersionMismatch, got %v", err)
+	}
+}
+
 func TestUpdateTaskNotFound(t *testing.T) {
 	ctx := context.Background()
 
@@ -291,9 +545,9 @@ func TestUpdateTaskNotFound(t *testing.T) {
 
 	mockUserRepo := &MockUserRepository{}
 	mockAuditRepo := &MockTaskAuditRepository{}
-	mockRabbitMQ := &MockRabbitMQPublisher{}
+	mockOutbox := &MockAuditOutboxRepository{}
 
-	service := NewTaskService(mockTaskRepo, mockUserRepo, mockAuditRepo, mockRabbitMQ)
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo, audit: mockAuditRepo, outbox: mockOutbox})
 
 	req := &entity.UpdateTaskRequest{
 		Title: "New Title",
@@ -308,3 +562,101 @@ func TestUpdateTaskNotFound(t *testing.T) {
 		t.Errorf("Expected nil task, got %v", result)
 	}
 }
+
+func TestCreateTaskDefaultsAndValidatesSchedule(t *testing.T) {
+	ctx := context.Background()
+	var created *entity.CreateTaskRequest
+
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	mockTaskRepo := &MockTaskRepository{
+		CreateFunc: func(ctx context.Context, task *entity.CreateTaskRequest) (*entity.Task, error) {
+			created = task
+			return &entity.Task{ID: 1, Title: task.Title, Priority: task.Priority}, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, users: mockUserRepo})
+
+	if _, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Task"}, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Priority != entity.PriorityMedium {
+		t.Errorf("Expected default priority medium, got %q", created.Priority)
+	}
+
+	dueAt := time.Now()
+	startAt := dueAt.Add(time.Hour)
+	invalid := []*entity.CreateTaskRequest{
+		{Title: "Task", Priority: "asap"},
+		{Title: "Task", StartAt: &startAt, DueAt: &dueAt},
+	}
+	for _, req := range invalid {
+		if _, err := service.CreateTask(ctx, req, 1); err != entity.ErrInvalidTaskData {
+			t.Errorf("Expected ErrInvalidTaskData for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestUpdateTaskClearsDueDate(t *testing.T) {
+	ctx := context.Background()
+	dueAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldTask := &entity.Task{ID: 1, Title: "Task", OwnerId: 1, Version: 1, DueAt: &dueAt, Priority: entity.PriorityMedium}
+
+	var gotUpdates map[string]interface{}
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return oldTask, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			gotUpdates = updates
+			updated := *oldTask
+			updated.DueAt = nil
+			updated.Version++
+			return &updated, nil
+		},
+	}
+	mockOutbox := &MockAuditOutboxRepository{}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, outbox: mockOutbox})
+
+	// OptionalTime{} без значения - явная очистка срока
+	_, err := service.UpdateTask(ctx, 1, 1, &entity.UpdateTaskRequest{DueAt: &entity.OptionalTime{}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	value, ok := gotUpdates["due_at"]
+	if !ok {
+		t.Fatalf("Expected due_at in updates, got %v", gotUpdates)
+	}
+	if value.(*time.Time) != nil {
+		t.Errorf("Expected due_at to be cleared, got %v", value)
+	}
+
+	if len(mockOutbox.Messages) != 1 {
+		t.Fatalf("Expected one audit message, got %d", len(mockOutbox.Messages))
+	}
+	change, ok := mockOutbox.Messages[0].Changes["due_at"].(map[string]interface{})
+	if !ok || change["old"] != "2030-01-01T00:00:00Z" || change["new"] != nil {
+		t.Errorf("Expected due_at change in audit, got %v", mockOutbox.Messages[0].Changes)
+	}
+}
+
+func TestListTasksInvalidScheduleFilter(t *testing.T) {
+	ctx := context.Background()
+	service := newTestTaskService(taskServiceDeps{})
+
+	after := time.Now()
+	before := after.Add(-time.Hour)
+	tests := []*entity.ListTasksRequest{
+		{Priority: "asap"},
+		{DueAfter: &after, DueBefore: &before},
+	}
+	for _, req := range tests {
+		if _, err := service.ListTasks(ctx, 1, req); err != entity.ErrInvalidFilter {
+			t.Errorf("Expected ErrInvalidFilter for %+v, got %v", req, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// DefaultStatusTransitions - граф переходов статусов по умолчанию.
+// Завершенную задачу можно вернуть в работу, отмененную - восстановить
+func DefaultStatusTransitions() map[entity.TaskStatus][]entity.TaskStatus {
+	return map[entity.TaskStatus][]entity.TaskStatus{
+		entity.StatusPending:    {entity.StatusInProgress, entity.StatusCompleted, entity.StatusCancelled},
+		entity.StatusInProgress: {entity.StatusPending, entity.StatusCompleted, entity.StatusCancelled},
+		entity.StatusCompleted:  {entity.StatusInProgress},
+		entity.StatusCancelled:  {entity.StatusPending},
+	}
+}
+
+// StatusMachine проверяет переходы статусов задачи по графу
+type StatusMachine struct {
+	allowed map[entity.TaskStatus]map[entity.TaskStatus]bool
+}
+
+// NewStatusMachine создает StatusMachine по графу переходов from -> []to
+func NewStatusMachine(transitions map[entity.TaskStatus][]entity.TaskStatus) (*StatusMachine, error) {
+	allowed := make(map[entity.TaskStatus]map[entity.TaskStatus]bool, len(transitions))
+	for from, targets := range transitions {
+		if !from.IsValid() {
+			return nil, fmt.Errorf("unknown status %q in transitions", from)
+		}
+		allowed[from] = make(map[entity.TaskStatus]bool, len(targets))
+		for _, to := range targets {
+			if !to.IsValid() {
+				return nil, fmt.Errorf("unknown status %q in transitions", to)
+			}
+			allowed[from][to] = true
+		}
+	}
+	return &StatusMachine{allowed: allowed}, nil
+}
+
+// ParseStatusTransitions разбирает граф из строки вида
+// "pending:in_progress,cancelled;in_progress:completed"
+func ParseStatusTransitions(spec string) (map[entity.TaskStatus][]entity.TaskStatus, error) {
+	transitions := make(map[entity.TaskStatus][]entity.TaskStatus)
+	for _, rule := range strings.Split(spec, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		from, targets, ok := strings.Cut(rule, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid transition rule %q, expected from:to1,to2", rule)
+		}
+
+		status := entity.TaskStatus(strings.TrimSpace(from))
+		transitions[status] = []entity.TaskStatus{}
+		for _, to := range strings.Split(targets, ",") {
+			if to = strings.TrimSpace(to); to != "" {
+				transitions[status] = append(transitions[status], entity.TaskStatus(to))
+			}
+		}
+	}
+	return transitions, nil
+}
+
+// CheckTransition возвращает *entity.InvalidTransitionError, если переход from -> to запрещен.
+// Переход в тот же статус всегда разрешен
+func (m *StatusMachine) CheckTransition(from, to entity.TaskStatus) error {
+	if !to.IsValid() {
+		return entity.ErrInvalidTaskData
+	}
+	if from == to || m.allowed[from][to] {
+		return nil
+	}
+	return &entity.InvalidTransitionError{From: from, To: to}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func TestUpdateTaskStatusTransitions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		from    entity.TaskStatus
+		to      entity.TaskStatus
+		wantErr error
+	}{
+		{name: "start work", from: entity.StatusPending, to: entity.StatusInProgress},
+		{name: "complete", from: entity.StatusInProgress, to: entity.StatusCompleted},
+		{name: "reopen", from: entity.StatusCompleted, to: entity.StatusInProgress},
+		{name: "cancelled to in_progress", from: entity.StatusCancelled, to: entity.StatusInProgress, wantErr: entity.ErrInvalidTransition},
+		{name: "completed to cancelled", from: entity.StatusCompleted, to: entity.StatusCancelled, wantErr: entity.ErrInvalidTransition},
+		{name: "unknown status", from: entity.StatusPending, to: "done", wantErr: entity.ErrInvalidTaskData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUpdates map[string]interface{}
+			mockTaskRepo := &MockTaskRepository{
+				GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+					return &entity.Task{ID: taskId, OwnerId: 1, Status: tt.from}, nil
+				},
+				UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+					gotUpdates = updates
+					return &entity.Task{ID: id, OwnerId: 1, Status: tt.to}, nil
+				},
+			}
+			service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+			_, err := service.UpdateTask(ctx, 1, 1, &entity.UpdateTaskRequest{Status: tt.to})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// completed_at выставляется при завершении и сбрасывается при возврате в работу
+			completedAt, ok := gotUpdates["completed_at"].(*time.Time)
+			switch {
+			case tt.to == entity.StatusCompleted && (!ok || completedAt == nil):
+				t.Errorf("Expected completed_at to be set, got %v", gotUpdates)
+			case tt.from == entity.StatusCompleted && (!ok || completedAt != nil):
+				t.Errorf("Expected completed_at to be cleared, got %v", gotUpdates)
+			}
+		})
+	}
+}
+
+func TestCustomStatusMachine(t *testing.T) {
+	transitions, err := ParseStatusTransitions("pending:in_progress; in_progress:completed")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	statuses, err := NewStatusMachine(transitions)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := statuses.CheckTransition(entity.StatusPending, entity.StatusInProgress); err != nil {
+		t.Errorf("Expected pending -> in_progress to be allowed, got %v", err)
+	}
+
+	var transitionErr *entity.InvalidTransitionError
+	err = statuses.CheckTransition(entity.StatusPending, entity.StatusCompleted)
+	if !errors.As(err, &transitionErr) || transitionErr.From != entity.StatusPending || transitionErr.To != entity.StatusCompleted {
+		t.Errorf("Expected InvalidTransitionError pending -> completed, got %v", err)
+	}
+
+	if _, err := ParseStatusTransitions("pending"); err == nil {
+		t.Errorf("Expected error for rule without targets")
+	}
+	if _, err := NewStatusMachine(map[entity.TaskStatus][]entity.TaskStatus{"pending": {"done"}}); err == nil {
+		t.Errorf("Expected error for unknown status")
+	}
+}
+
+func TestCreateTaskRejectsUnknownStatus(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := &MockUserRepository{
+		GetByIdFunc: func(ctx context.Context, id int) (*entity.User, error) {
+			return &entity.User{ID: id}, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{users: mockUserRepo})
+
+	_, err := service.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Task", Status: "done"}, 1)
+	if err != entity.ErrInvalidTaskData {
+		t.Errorf("Expected ErrInvalidTaskData, got %v", err)
+	}
+}
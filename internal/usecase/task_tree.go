@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+// Глубина дерева задач в GetTaskTree: по умолчанию и максимальная
+const (
+	DefaultTaskTreeDepth = 3
+	MaxTaskTreeDepth     = 10
+)
+
+// ListSubtasks возвращает страницу прямых подзадач задачи
+func (s *TaskService) ListSubtasks(ctx context.Context, taskID int, userID int, req *entity.ListTasksRequest) (*entity.TaskPage, error) {
+	task, err := s.GetTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Доступ к задаче дает доступ к ее подзадачам, поэтому фильтр по пользователю не нужен.
+	// Подзадачи архивируются вместе с задачей
+	req.UserID = userID
+	req.Mode = ""
+	req.ParentID = &taskID
+	req.ProjectID = nil
+	req.Archived = task.ArchivedAt != nil
+	return s.listTasks(ctx, req)
+}
+
+// GetTaskTree возвращает задачу с подзадачами не глубже depth уровней.
+// Прогресс каждого узла считается по всему его поддереву
+func (s *TaskService) GetTaskTree(ctx context.Context, taskID int, userID int, depth int) (*entity.TaskNode, error) {
+	if _, err := s.GetTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if depth <= 0 {
+		depth = DefaultTaskTreeDepth
+	}
+	if depth > MaxTaskTreeDepth {
+		depth = MaxTaskTreeDepth
+	}
+
+	tasks, err := s.taskRepo.GetSubtree(ctx, taskID, depth)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, entity.ErrTaskNotFound // удалена между запросами
+	}
+	statuses, err := s.taskRepo.ListSubtreeStatuses(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*entity.Task, len(tasks))
+	for i := range tasks {
+		refs[i] = &tasks[i]
+	}
+	if err := s.loadDetails(ctx, refs...); err != nil {
+		return nil, err
+	}
+
+	return buildTaskTree(tasks, subtreeProgress(statuses)), nil
+}
+
+// buildTaskTree собирает дерево из задач, упорядоченных по уровню (корень первый)
+func buildTaskTree(tasks []entity.Task, progress map[int]int) *entity.TaskNode {
+	nodes := make(map[int]*entity.TaskNode, len(tasks))
+	root := &entity.TaskNode{Task: tasks[0], Progress: progress[tasks[0].ID], Children: []*entity.TaskNode{}}
+	nodes[root.Task.ID] = root
+
+	for _, task := range tasks[1:] {
+		parent := nodes[*task.ParentID]
+		node := &entity.TaskNode{Task: task, Progress: progress[task.ID], Children: []*entity.TaskNode{}}
+		parent.Children = append(parent.Children, node)
+		nodes[task.ID] = node
+	}
+	return root
+}
+
+// subtreeProgress считает процент выполнения задач поддерева. Задача без подзадач
+// выполнена на 100%, если она завершена, иначе на 0%. У задачи с подзадачами прогресс -
+// среднее по неотмененным детям; если все дети отменены, она считается листом
+func subtreeProgress(nodes []entity.TaskStatusNode) map[int]int {
+	statuses := make(map[int]entity.TaskStatus, len(nodes))
+	children := make(map[int][]int, len(nodes))
+	for _, node := range nodes {
+		statuses[node.ID] = node.Status
+		if node.ParentID != nil {
+			children[*node.ParentID] = append(children[*node.ParentID], node.ID)
+		}
+	}
+
+	done := make(map[int]float64, len(nodes))
+	var visit func(id int) float64
+	visit = func(id int) float64 {
+		if value, ok := done[id]; ok {
+			return value
+		}
+		done[id] = 0 // защита от цикла в данных
+
+		var sum float64
+		var counted int
+		for _, childID := range children[id] {
+			if statuses[childID] == entity.StatusCancelled {
+				continue
+			}
+			sum += visit(childID)
+			counted++
+		}
+
+		value := 0.0
+		switch {
+		case counted > 0:
+			value = sum / float64(counted)
+		case statuses[id] == entity.StatusCompleted:
+			value = 1
+		}
+		done[id] = value
+		return value
+	}
+
+	progress := make(map[int]int, len(nodes))
+	for _, node := range nodes {
+		// Округляем вниз, чтобы 100% было только при полностью завершенном поддереве
+		progress[node.ID] = int(math.Floor(visit(node.ID)*100 + 1e-9))
+	}
+	return progress
+}
+
+// loadParent загружает будущего родителя задачи, ErrInvalidParent если его нет
+func (s *TaskService) loadParent(ctx context.Context, parentID int) (*entity.Task, error) {
+	parent, err := s.taskRepo.GetByTaskId(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, entity.ErrInvalidParent
+	}
+	return parent, nil
+}
+
+// checkParent проверяет, что будущий родитель не в архиве и находится в проекте задачи projectID,
+// а вне проектов принадлежит владельцу задачи ownerID. Пользователь должен иметь право изменять родителя
+func (s *TaskService) checkParent(ctx context.Context, parent *entity.Task, ownerID int, projectID *int, userID int) error {
+	if parent.ArchivedAt != nil || !equalIDs(parent.ProjectID, projectID) {
+		return entity.ErrInvalidParent
+	}
+	if projectID == nil && parent.OwnerId != ownerID {
+		return entity.ErrInvalidParent
+	}
+	access, err := s.taskAccess(ctx, parent, userID)
+	if err != nil {
+		return err
+	}
+	if access < entity.TaskAccessWrite {
+		return entity.ErrInvalidParent
+	}
+	return nil
+}
+
+// checkNoCycle запрещает перенос задачи под саму себя или под своего потомка.
+// Вызывается в транзакции после LockHierarchy
+func (s *TaskService) checkNoCycle(ctx context.Context, taskID int, parentID int) error {
+	if parentID == taskID {
+		return entity.ErrTaskCycle
+	}
+	ancestors, err := s.taskRepo.GetAncestorIDs(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ancestors, taskID) {
+		return entity.ErrTaskCycle
+	}
+	return nil
+}
+
+// deleteSubtree удаляет задачу вместе с поддеревом и пишет аудит по каждой удаленной задаче.
+// Возвращает вложения удаленных задач: их файлы удаляются после коммита
+func (s *TaskService) deleteSubtree(ctx context.Context, task *entity.Task, userID int) ([]entity.TaskAttachment, error) {
+	subtree, err := s.taskRepo.GetSubtree(ctx, task.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	descendants := make([]*entity.Task, 0, len(subtree))
+	taskIDs := []int{task.ID}
+	for i := range subtree {
+		if subtree[i].ID != task.ID {
+			descendants = append(descendants, &subtree[i])
+			taskIDs = append(taskIDs, subtree[i].ID)
+		}
+	}
+	if err := s.loadDetails(ctx, descendants...); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Потомки и вложения удаляются каскадно по внешним ключам
+	if err := s.taskRepo.Delete(ctx, task.ID, task.Version); err != nil {
+		return nil, err
+	}
+	for _, deleted := range append([]*entity.Task{task}, descendants...) {
+		if err := s.sendAuditMessage(ctx, entity.ActionDelete, userID, deleted.ID, deleted, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// deleteReparent поднимает прямые подзадачи к родителю задачи и удаляет ее саму.
+// Возвращает вложения задачи: их файлы удаляются после коммита
+func (s *TaskService) deleteReparent(ctx context.Context, task *entity.Task, userID int) ([]entity.TaskAttachment, error) {
+	subtree, err := s.taskRepo.GetSubtree(ctx, task.ID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subtree {
+		child := &subtree[i]
+		if child.ID == task.ID {
+			continue
+		}
+		if err := s.loadDetails(ctx, child); err != nil {
+			return nil, err
+		}
+
+		updates := map[string]interface{}{"parent_id": task.ParentID}
+		moved, err := s.taskRepo.Update(ctx, child.ID, child.Version, updates)
+		if err != nil {
+			return nil, err
+		}
+		moved.Labels = child.Labels
+		moved.AssigneeIDs = child.AssigneeIDs
+		if err := s.sendAuditMessage(ctx, entity.ActionUpdate, userID, child.ID, child, moved, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	attachments, err := s.attachmentRepo.ListByTasks(ctx, []int{task.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.Delete(ctx, task.ID, task.Version); err != nil {
+		return nil, err
+	}
+	if err := s.sendAuditMessage(ctx, entity.ActionDelete, userID, task.ID, task, nil, nil); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// formatAuditID форматирует необязательную ссылку для аудита (nil -> null)
+func formatAuditID(id *int) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
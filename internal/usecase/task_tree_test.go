@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/St1cky1/task-service/internal/entity"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestSubtreeProgress(t *testing.T) {
+	// 1 -> {2 (completed), 3 -> {4 (completed), 5 (pending)}, 6 (cancelled)}
+	nodes := []entity.TaskStatusNode{
+		{ID: 1, Status: entity.StatusInProgress},
+		{ID: 2, ParentID: intPtr(1), Status: entity.StatusCompleted},
+		{ID: 3, ParentID: intPtr(1), Status: entity.StatusInProgress},
+		{ID: 4, ParentID: intPtr(3), Status: entity.StatusCompleted},
+		{ID: 5, ParentID: intPtr(3), Status: entity.StatusPending},
+		{ID: 6, ParentID: intPtr(1), Status: entity.StatusCancelled},
+	}
+
+	progress := subtreeProgress(nodes)
+	expected := map[int]int{1: 75, 2: 100, 3: 50, 4: 100, 5: 0, 6: 0}
+	for id, want := range expected {
+		if progress[id] != want {
+			t.Errorf("Task %d: expected progress %d, got %d", id, want, progress[id])
+		}
+	}
+}
+
+func TestGetTaskTreeBuildsNodes(t *testing.T) {
+	ctx := context.Background()
+
+	tasks := []entity.Task{
+		{ID: 1, OwnerId: 1, Status: entity.StatusPending},
+		{ID: 2, OwnerId: 1, ParentID: intPtr(1), Status: entity.StatusCompleted},
+		{ID: 3, OwnerId: 1, ParentID: intPtr(1), Status: entity.StatusPending},
+		{ID: 4, OwnerId: 1, ParentID: intPtr(3), Status: entity.StatusPending},
+	}
+	var requestedDepth int
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &tasks[0], nil
+		},
+		GetSubtreeFunc: func(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error) {
+			requestedDepth = maxDepth
+			return tasks, nil
+		},
+		StatusesFunc: func(ctx context.Context, rootID int) ([]entity.TaskStatusNode, error) {
+			nodes := make([]entity.TaskStatusNode, len(tasks))
+			for i, task := range tasks {
+				nodes[i] = entity.TaskStatusNode{ID: task.ID, ParentID: task.ParentID, Status: task.Status}
+			}
+			return nodes, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	tree, err := service.GetTaskTree(ctx, 1, 1, 100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requestedDepth != MaxTaskTreeDepth {
+		t.Errorf("Expected depth clamped to %d, got %d", MaxTaskTreeDepth, requestedDepth)
+	}
+	if tree.Task.ID != 1 || tree.Progress != 50 || len(tree.Children) != 2 {
+		t.Fatalf("Unexpected root %+v", tree)
+	}
+	if child := tree.Children[1]; child.Task.ID != 3 || len(child.Children) != 1 || child.Children[0].Task.ID != 4 {
+		t.Errorf("Unexpected subtree %+v", child)
+	}
+}
+
+func TestUpdateTaskRejectsCycle(t *testing.T) {
+	ctx := context.Background()
+
+	// 1 -> 2 -> 3, переносим 1 под 3
+	mockTaskRepo := &MockTaskRepository{
+		GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+			return &entity.Task{ID: taskId, OwnerId: 1, Version: 1}, nil
+		},
+		AncestorsFunc: func(ctx context.Context, taskID int) ([]int, error) {
+			if taskID == 3 {
+				return []int{2, 1}, nil
+			}
+			return nil, nil
+		},
+		UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+			t.Errorf("Update must not be called for a cycle")
+			return nil, nil
+		},
+	}
+	service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo})
+
+	for _, parentID := range []int{1, 3} {
+		_, err := service.UpdateTask(ctx, 1, 1, &entity.UpdateTaskRequest{ParentID: &entity.OptionalID{ID: intPtr(parentID)}})
+		if err != entity.ErrTaskCycle {
+			t.Errorf("Parent %d: expected ErrTaskCycle, got %v", parentID, err)
+		}
+	}
+}
+
+func TestDeleteTaskSubtaskPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	root := entity.Task{ID: 1, OwnerId: 1, Version: 1, ParentID: intPtr(10)}
+	children := []entity.Task{
+		{ID: 2, OwnerId: 1, Version: 3, ParentID: intPtr(1)},
+		{ID: 3, OwnerId: 1, Version: 1, ParentID: intPtr(2)},
+	}
+
+	tests := []struct {
+		name          string
+		policy        entity.SubtaskPolicy
+		expectedMoves []int
+		expectedAudit []entity.ActionType
+	}{
+		{"reparent", entity.SubtaskReparent, []int{2}, []entity.ActionType{entity.ActionUpdate, entity.ActionDelete}},
+		{"cascade", entity.SubtaskCascade, nil, []entity.ActionType{entity.ActionDelete, entity.ActionDelete, entity.ActionDelete}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var moved []int
+			mockTaskRepo := &MockTaskRepository{
+				GetByTaskIdFunc: func(ctx context.Context, taskId int) (*entity.Task, error) {
+					task := root
+					return &task, nil
+				},
+				GetSubtreeFunc: func(ctx context.Context, rootID int, maxDepth int) ([]entity.Task, error) {
+					if maxDepth == 1 {
+						return []entity.Task{root, children[0]}, nil
+					}
+					return append([]entity.Task{root}, children...), nil
+				},
+				UpdateFunc: func(ctx context.Context, id int, version int, updates map[string]interface{}) (*entity.Task, error) {
+					if parentID, ok := updates["parent_id"].(*int); !ok || *parentID != 10 {
+						t.Errorf("Expected child moved under 10, got %v", updates)
+					}
+					moved = append(moved, id)
+					return &entity.Task{ID: id, OwnerId: 1, Version: version + 1, ParentID: intPtr(10)}, nil
+				},
+			}
+			mockOutbox := &MockAuditOutboxRepository{}
+			service := newTestTaskService(taskServiceDeps{tasks: mockTaskRepo, outbox: mockOutbox})
+
+			if err := service.DeleteTask(ctx, 1, 1, 0, tt.policy); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(moved, tt.expectedMoves) {
+				t.Errorf("Expected moved %v, got %v", tt.expectedMoves, moved)
+			}
+			actions := make([]entity.ActionType, len(mockOutbox.Messages))
+			for i, msg := range mockOutbox.Messages {
+				actions[i] = msg.Action
+			}
+			if !slices.Equal(actions, tt.expectedAudit) {
+				t.Errorf("Expected audit %v, got %v", tt.expectedAudit, actions)
+			}
+		})
+	}
+}
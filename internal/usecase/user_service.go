@@ -5,37 +5,47 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
-	"time"
+	"slices"
 
 	"github.com/St1cky1/task-service/internal/entity"
 	"github.com/St1cky1/task-service/internal/infrastructure/auth"
+	"github.com/St1cky1/task-service/internal/infrastructure/storage"
 	"github.com/St1cky1/task-service/internal/repository"
 )
 
+// Аватарки хранятся в общем слое файлов в подкаталоге avatarBlobDir
+const (
+	avatarBlobDir = "avatars"
+	maxAvatarSize = 5 * 1024 * 1024
+)
+
 type UserService struct {
 	userRepo         repository.IUserRepository
 	avatarRepo       repository.IAvatarRepository
+	blobs            *BlobService
 	passwordManager  *auth.PasswordManager
 	jwtManager       *auth.JWTManager
 	refreshTokenRepo repository.IRefreshTokenRepository
+	transactor       repository.ITransactor
 }
 
 func NewUserService(
 	userRepo repository.IUserRepository,
 	avatarRepo repository.IAvatarRepository,
+	blobs *BlobService,
 	passwordManager *auth.PasswordManager,
 	jwtManager *auth.JWTManager,
 	refreshTokenRepo repository.IRefreshTokenRepository,
+	transactor repository.ITransactor,
 ) *UserService {
 	return &UserService{
 		userRepo:         userRepo,
 		avatarRepo:       avatarRepo,
+		blobs:            blobs,
 		passwordManager:  passwordManager,
 		jwtManager:       jwtManager,
 		refreshTokenRepo: refreshTokenRepo,
+		transactor:       transactor,
 	}
 }
 
@@ -103,11 +113,11 @@ func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
 		return err
 	}
 	if avatar != nil {
-		os.Remove(avatar.FilePath)
 		err := s.avatarRepo.DeleteByUserId(ctx, userID)
 		if err != nil {
 			return err
 		}
+		s.removeAvatarFiles(ctx, &avatar.Blob)
 	}
 
 	// Удаляем пользователя
@@ -119,18 +129,50 @@ func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
 	return nil
 }
 
-// ListUsers получает список пользователей
-func (s *UserService) ListUsers(ctx context.Context) ([]entity.User, error) {
-	users, err := s.userRepo.List(ctx)
+// userSortFields - поля, по которым можно сортировать пользователей
+var userSortFields = []string{
+	entity.UserSortCreatedAt,
+	entity.UserSortName,
+	entity.UserSortID,
+}
+
+// ListUsers получает страницу пользователей
+func (s *UserService) ListUsers(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserPage, error) {
+	if err := normalizePage(&req.Page, entity.UserSortCreatedAt, userSortFields); err != nil {
+		return nil, err
+	}
+
+	page, err := s.userRepo.ListPage(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return users, nil
+	return page, nil
 }
 
-// UploadAvatar загружает аватарку пользователя
-func (s *UserService) UploadAvatar(ctx context.Context, userID int, data []byte, contentType string) (string, error) {
+// NewAvatarUpload начинает потоковую загрузку аватарки: чанки пишутся во временный объект,
+// загрузка обрывается с ErrFileTooLarge, как только файл превысит 5MB
+func (s *UserService) NewAvatarUpload(ctx context.Context) *BlobUpload {
+	return s.blobs.NewUpload(ctx, maxAvatarSize)
+}
+
+// UploadAvatar загружает аватарку пользователя из памяти (см. UploadAvatarStream)
+func (s *UserService) UploadAvatar(ctx context.Context, userID int, data []byte) (string, error) {
+	upload := s.NewAvatarUpload(ctx)
+	defer upload.Abort()
+
+	if _, err := upload.Write(data); err != nil {
+		return "", err
+	}
+	return s.UploadAvatarStream(ctx, userID, upload)
+}
+
+// UploadAvatarStream сохраняет аватарку из потоковой загрузки. Принимаются JPEG, PNG, WebP и GIF:
+// формат определяется по содержимому, изображение перекодируется без EXIF,
+// рядом с ним сохраняются превью размеров AvatarSizes. Исходный файл не сохраняется
+func (s *UserService) UploadAvatarStream(ctx context.Context, userID int, upload *BlobUpload) (string, error) {
+	defer upload.Abort()
+
 	// Проверяем что пользователь существует
 	user, err := s.userRepo.GetById(ctx, userID)
 	if err != nil {
@@ -140,92 +182,106 @@ func (s *UserService) UploadAvatar(ctx context.Context, userID int, data []byte,
 		return "", entity.ErrUserNotFound
 	}
 
-	// Проверяем размер файла (максимум 5MB)
-	if len(data) > 5*1024*1024 {
-		return "", fmt.Errorf("file size exceeds 5MB limit")
-	}
-
-	// Создаем директорию если её нет
-	uploadDir := "var/avatars"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+	if _, err := upload.Close(); err != nil {
 		return "", err
 	}
-
-	// Генерируем имя файла
-	fileName := fmt.Sprintf("avatar_%d_%d", userID, time.Now().UnixNano())
-	filePath := filepath.Join(uploadDir, fileName)
-
-	// Сохраняем файл
-	err = os.WriteFile(filePath, data, 0644)
+	data, err := readUpload(ctx, upload)
 	if err != nil {
 		return "", err
 	}
 
-	// Удаляем старую аватарку если существует
-	oldAvatar, err := s.avatarRepo.GetByUserId(ctx, userID)
-	if err == nil && oldAvatar != nil {
-		os.Remove(oldAvatar.FilePath)
+	images, err := processAvatar(data)
+	if err != nil {
+		return "", err
 	}
 
-	// Сохраняем информацию об аватарке в БД
-	avatar := &entity.Avatar{
-		UserID:      userID,
-		FilePath:    filePath,
-		FileSize:    len(data),
-		ContentType: contentType,
+	key := newBlobKey(avatarBlobDir, fmt.Sprintf("avatar_%d", userID))
+	staged, blob, err := s.stageAvatarImages(ctx, key, images)
+	if err != nil {
+		return "", err
 	}
+	defer abortUploads(staged)
 
-	_, err = s.avatarRepo.Save(ctx, avatar)
+	oldAvatar, err := s.avatarRepo.GetByUserId(ctx, userID)
 	if err != nil {
-		os.Remove(filePath)
 		return "", err
 	}
 
-	// Обновляем поле avatar_url в пользователе
-	updates := make(map[string]interface{})
-	updates["avatar_url"] = filePath
+	// Запись об аватарке и avatar_url сохраняются в одной транзакции. Файлы переносятся
+	// под итоговые ключи последним шагом: если перенос не удался, записи откатятся
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		avatar := &entity.Avatar{
+			UserID: userID,
+			Blob:   *blob,
+		}
+		if _, err := s.avatarRepo.Save(ctx, avatar); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"avatar_url": blob.StorageKey}
+		if _, err := s.userRepo.Update(ctx, userID, updates); err != nil {
+			return err
+		}
 
-	_, err = s.userRepo.Update(ctx, userID, updates)
+		return commitUploads(ctx, staged)
+	})
 	if err != nil {
-		os.Remove(filePath)
-		s.avatarRepo.DeleteByUserId(ctx, userID)
+		// Транзакция не зафиксирована: удаляем файлы, которые успели перенести
+		removeCommittedUploads(ctx, s.blobs, staged)
 		return "", err
 	}
 
-	return filePath, nil
+	// Старые файлы удаляем только после того, как новая аватарка сохранена
+	if oldAvatar != nil {
+		s.removeAvatarFiles(ctx, &oldAvatar.Blob)
+	}
+
+	return blob.StorageKey, nil
 }
 
-// DownloadAvatar скачивает аватарку пользователя
-func (s *UserService) DownloadAvatar(ctx context.Context, userID int) ([]byte, string, error) {
-	// Проверяем что пользователь существует
-	user, err := s.userRepo.GetById(ctx, userID)
+// DownloadAvatar скачивает аватарку пользователя: полную версию (size 0) или превью
+func (s *UserService) DownloadAvatar(ctx context.Context, userID int, size int) ([]byte, string, error) {
+	avatar, err := s.GetAvatar(ctx, userID, size)
 	if err != nil {
 		return nil, "", err
 	}
-	if user == nil {
-		return nil, "", entity.ErrUserNotFound
-	}
 
-	// Получаем информацию об аватарке
-	avatar, err := s.avatarRepo.GetByUserId(ctx, userID)
+	data, err := s.ReadAvatar(ctx, avatar, size)
 	if err != nil {
 		return nil, "", err
 	}
-	if avatar == nil {
-		return nil, "", fmt.Errorf("avatar not found")
-	}
 
-	// Читаем файл
-	data, err := os.ReadFile(avatar.FilePath)
-	if err != nil {
-		return nil, "", err
+	return data, avatar.ContentType, nil
+}
+
+// ReadAvatar читает файл аватарки целиком: полную версию (size 0) или превью
+func (s *UserService) ReadAvatar(ctx context.Context, avatar *entity.Avatar, size int) ([]byte, error) {
+	data, err := s.blobs.ReadAll(ctx, avatarBlob(&avatar.Blob, size))
+	// Аватарки, загруженные до появления превью, отдаем в полном размере
+	if err == storage.ErrNotFound && size != 0 {
+		data, err = s.blobs.ReadAll(ctx, &avatar.Blob)
 	}
+	return data, err
+}
 
-	return data, avatar.ContentType, nil
+// OpenAvatar открывает файл аватарки для чтения с произвольной позиции: полную версию (size 0)
+// или превью. Файл не загружается в память, вызывающий закрывает reader
+func (s *UserService) OpenAvatar(ctx context.Context, avatar *entity.Avatar, size int) (*BlobReader, error) {
+	reader, err := s.blobs.Open(ctx, avatarBlob(&avatar.Blob, size))
+	// Аватарки, загруженные до появления превью, отдаем в полном размере
+	if err == storage.ErrNotFound && size != 0 {
+		reader, err = s.blobs.Open(ctx, &avatar.Blob)
+	}
+	return reader, err
 }
 
-// DownloadAvatarStream скачивает аватарку с использованием stream
-func (s *UserService) DownloadAvatarStream(ctx context.Context, userID int, chunkSize int) (<-chan []byte, <-chan error) {
+// DownloadAvatarStream скачивает аватарку с использованием stream: полную версию (size 0) или превью
+func (s *UserService) DownloadAvatarStream(ctx context.Context, userID int, size int, chunkSize int) (*entity.Avatar, <-chan []byte, <-chan error, error) {
+	avatar, err := s.GetAvatar(ctx, userID, size)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	dataChan := make(chan []byte)
 	errChan := make(chan error, 1)
 
@@ -233,61 +289,89 @@ func (s *UserService) DownloadAvatarStream(ctx context.Context, userID int, chun
 		defer close(dataChan)
 		defer close(errChan)
 
-		// Проверяем что пользователь существует
-		user, err := s.userRepo.GetById(ctx, userID)
-		if err != nil {
-			errChan <- err
-			return
-		}
-		if user == nil {
-			errChan <- entity.ErrUserNotFound
-			return
+		// Читаем и отправляем чанками
+		err := s.blobs.streamTo(ctx, avatarBlob(&avatar.Blob, size), chunkSize, dataChan)
+		// Аватарки, загруженные до появления превью, отдаем в полном размере
+		if err == storage.ErrNotFound && size != 0 {
+			err = s.blobs.streamTo(ctx, &avatar.Blob, chunkSize, dataChan)
 		}
-
-		// Получаем информацию об аватарке
-		avatar, err := s.avatarRepo.GetByUserId(ctx, userID)
 		if err != nil {
 			errChan <- err
-			return
-		}
-		if avatar == nil {
-			errChan <- fmt.Errorf("avatar not found")
-			return
 		}
+	}()
 
-		// Открываем файл
-		file, err := os.Open(avatar.FilePath)
+	return avatar, dataChan, errChan, nil
+}
+
+// GetAvatar проверяет размер превью и возвращает метаданные аватарки пользователя
+func (s *UserService) GetAvatar(ctx context.Context, userID int, size int) (*entity.Avatar, error) {
+	if size != 0 && !slices.Contains(AvatarSizes, size) {
+		return nil, entity.ErrInvalidAvatarSize
+	}
+
+	// Проверяем что пользователь существует
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	avatar, err := s.avatarRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if avatar == nil {
+		return nil, entity.ErrAvatarNotFound
+	}
+	return avatar, nil
+}
+
+// stageAvatarImages записывает полную версию аватарки и превью во временные объекты.
+// Возвращает загрузки по итоговым ключам (превью - под avatarVariantKey) и файл полной версии
+func (s *UserService) stageAvatarImages(ctx context.Context, key string, images *avatarImages) (map[string]*BlobUpload, *entity.Blob, error) {
+	staged := make(map[string]*BlobUpload, len(AvatarSizes)+1)
+
+	upload, blob, err := s.blobs.Stage(ctx, images.Full)
+	if err != nil {
+		return nil, nil, err
+	}
+	staged[key] = upload
+	blob.StorageKey = key
+
+	for _, size := range AvatarSizes {
+		upload, _, err := s.blobs.Stage(ctx, images.Variants[size])
 		if err != nil {
-			errChan <- err
-			return
+			abortUploads(staged)
+			return nil, nil, err
 		}
-		defer file.Close()
+		staged[avatarVariantKey(key, size)] = upload
+	}
+	return staged, blob, nil
+}
 
-		// Читаем и отправляем чанками
-		buffer := make([]byte, chunkSize)
-		for {
-			n, err := file.Read(buffer)
-			if err != nil && err != io.EOF {
-				errChan <- err
-				return
-			}
-
-			if n > 0 {
-				select {
-				case dataChan <- buffer[:n]:
-				case <-ctx.Done():
-					errChan <- ctx.Err()
-					return
-				}
-			}
-
-			if err == io.EOF {
-				break
-			}
-		}
-	}()
+// removeAvatarFiles удаляет полную версию аватарки и все превью
+func (s *UserService) removeAvatarFiles(ctx context.Context, blob *entity.Blob) {
+	s.blobs.Remove(ctx, blob)
+	for _, size := range AvatarSizes {
+		s.blobs.Remove(ctx, avatarBlob(blob, size))
+	}
+}
 
-	return dataChan, errChan
+// avatarBlob возвращает файл полной версии (size 0) или превью аватарки
+func avatarBlob(blob *entity.Blob, size int) *entity.Blob {
+	if size == 0 {
+		return blob
+	}
+	return &entity.Blob{
+		StorageKey:  avatarVariantKey(blob.StorageKey, size),
+		ContentType: blob.ContentType,
+	}
+}
+
+func avatarVariantKey(key string, size int) string {
+	return fmt.Sprintf("%s_%d", key, size)
 }
 
 // HasAvatar проверяет, есть ли аватарка у пользователя
@@ -312,24 +396,17 @@ func (s *UserService) CreateUserWithAvatar(ctx context.Context, name, email, pas
 	}
 
 	// Загружаем аватарку для пользователя
-	_, err = s.UploadAvatar(ctx, user.ID, imageData, "image/jpeg")
+	_, err = s.UploadAvatar(ctx, user.ID, imageData)
 	if err != nil {
 		// Если аватарка не загрузилась, все равно возвращаем пользователя
 		// но логируем ошибку
 		fmt.Printf("⚠️  Warning: Avatar upload failed for user %d: %v\n", user.ID, err)
 	}
 
-	// Генерируем и сохраняем refresh token
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, email)
+	// Генерируем и сохраняем refresh token (новая сессия без устройства)
+	_, err = issueRefreshToken(ctx, s.jwtManager, s.refreshTokenRepo, user.ID, email, entity.ClientInfo{}, nil)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Failed to generate refresh token for user %d: %v\n", user.ID, err)
-	} else {
-		refreshTokenHash := hashToken(refreshToken)
-		expiresAt := time.Now().Add(7 * 24 * time.Hour)
-		err = s.refreshTokenRepo.Save(ctx, user.ID, refreshTokenHash, expiresAt)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to save refresh token for user %d: %v\n", user.ID, err)
-		}
+		fmt.Printf("⚠️  Warning: Failed to issue refresh token for user %d: %v\n", user.ID, err)
 	}
 
 	return user, nil
@@ -0,0 +1,57 @@
+-- Роли пользователей
+CREATE TABLE IF NOT EXISTS "role" (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT
+);
+
+INSERT INTO "role" (name, description) VALUES
+    ('user', 'Обычный пользователь: только свой профиль и свои задачи'),
+    ('admin', 'Администратор: управление всеми пользователями'),
+    ('service', 'Сервисный аккаунт: чтение пользователей и работа с задачами');
+
+-- Права ролей
+CREATE TABLE IF NOT EXISTS "role_permission" (
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+
+    PRIMARY KEY (role, permission),
+    CONSTRAINT fk_role_permission_role
+        FOREIGN KEY (role)
+        REFERENCES "role"(name)
+        ON DELETE CASCADE
+);
+
+INSERT INTO "role_permission" (role, permission) VALUES
+    ('user', 'task:read'),
+    ('user', 'task:write'),
+    ('user', 'user:read'),
+    ('user', 'user:write'),
+    ('admin', 'task:read'),
+    ('admin', 'task:write'),
+    ('admin', 'user:read'),
+    ('admin', 'user:write'),
+    ('admin', 'user:read_any'),
+    ('admin', 'user:manage'),
+    ('service', 'task:read'),
+    ('service', 'task:write'),
+    ('service', 'user:read'),
+    ('service', 'user:read_any');
+
+-- Роли, назначенные пользователям. Пользователь без строк здесь считается 'user'
+CREATE TABLE IF NOT EXISTS "user_role" (
+    user_id INTEGER NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, role),
+    CONSTRAINT fk_user_role_user
+        FOREIGN KEY (user_id)
+        REFERENCES "user"(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_user_role_role
+        FOREIGN KEY (role)
+        REFERENCES "role"(name)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_role_role ON "user_role"(role);
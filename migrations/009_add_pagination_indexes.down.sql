@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_name_id;
+DROP INDEX IF EXISTS idx_user_created_id;
+DROP INDEX IF EXISTS idx_task_owner_title_id;
+DROP INDEX IF EXISTS idx_task_owner_updated_id;
+DROP INDEX IF EXISTS idx_task_owner_created_id;
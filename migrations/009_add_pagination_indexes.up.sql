@@ -0,0 +1,7 @@
+-- Индексы под keyset-пагинацию списков задач и пользователей
+CREATE INDEX IF NOT EXISTS idx_task_owner_created_id ON task(owner_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_task_owner_updated_id ON task(owner_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_task_owner_title_id ON task(owner_id, title, id);
+
+CREATE INDEX IF NOT EXISTS idx_user_created_id ON "user"(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_user_name_id ON "user"(name, id);
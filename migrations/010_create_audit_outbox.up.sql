@@ -0,0 +1,14 @@
+-- Outbox для аудит-сообщений: пишется в одной транзакции с изменением задачи
+CREATE TABLE IF NOT EXISTS "audit_outbox" (
+    id BIGSERIAL PRIMARY KEY,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Частичный индекс только по неотправленным сообщениям
+CREATE INDEX idx_audit_outbox_pending ON "audit_outbox"(next_attempt_at, id) WHERE sent_at IS NULL;
+CREATE INDEX idx_audit_outbox_sent_at ON "audit_outbox"(sent_at) WHERE sent_at IS NOT NULL;
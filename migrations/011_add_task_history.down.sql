@@ -0,0 +1,2 @@
+DELETE FROM "role_permission" WHERE role = 'admin' AND permission = 'task:read_any';
+DROP INDEX IF EXISTS idx_task_audit_entity_changed_id;
@@ -0,0 +1,6 @@
+-- Индекс под постраничное чтение истории задачи
+CREATE INDEX IF NOT EXISTS idx_task_audit_entity_changed_id ON task_audit(entity_type, entity_id, changed_at, id);
+
+-- Администратор может читать историю чужих задач
+INSERT INTO "role_permission" (role, permission) VALUES ('admin', 'task:read_any')
+ON CONFLICT DO NOTHING;
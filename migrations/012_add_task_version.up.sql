@@ -0,0 +1,2 @@
+-- Версия задачи для оптимистичной блокировки, увеличивается при каждом обновлении
+ALTER TABLE task ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
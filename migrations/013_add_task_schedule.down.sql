@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_task_owner_priority_created_id;
+DROP INDEX IF EXISTS idx_task_overdue;
+DROP INDEX IF EXISTS idx_task_owner_due_at;
+
+ALTER TABLE task DROP CONSTRAINT IF EXISTS chk_task_start_before_due;
+ALTER TABLE task DROP CONSTRAINT IF EXISTS chk_task_priority;
+
+ALTER TABLE task DROP COLUMN IF EXISTS priority;
+ALTER TABLE task DROP COLUMN IF EXISTS due_at;
+ALTER TABLE task DROP COLUMN IF EXISTS start_at;
@@ -0,0 +1,16 @@
+-- Сроки и приоритет задачи
+ALTER TABLE task ADD COLUMN IF NOT EXISTS start_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE task ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE task ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'medium';
+
+ALTER TABLE task ADD CONSTRAINT chk_task_priority
+    CHECK (priority IN ('low', 'medium', 'high', 'urgent'));
+ALTER TABLE task ADD CONSTRAINT chk_task_start_before_due
+    CHECK (start_at IS NULL OR due_at IS NULL OR start_at <= due_at);
+
+-- Фильтры по сроку (due_before/due_after) и просроченные задачи
+CREATE INDEX IF NOT EXISTS idx_task_owner_due_at ON task(owner_id, due_at) WHERE due_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_overdue ON task(owner_id, due_at)
+    WHERE due_at IS NOT NULL AND status NOT IN ('completed', 'cancelled');
+-- Фильтр по приоритету с сортировкой по умолчанию
+CREATE INDEX IF NOT EXISTS idx_task_owner_priority_created_id ON task(owner_id, priority, created_at, id);
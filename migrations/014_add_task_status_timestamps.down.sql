@@ -0,0 +1,3 @@
+ALTER TABLE task DROP CONSTRAINT IF EXISTS chk_task_status;
+ALTER TABLE task DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE task DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,33 @@
+-- Статусы проверяются в usecase, но неизвестные значения не должны попасть в БД в обход него.
+-- Уже сохраненные неизвестные статусы не переписываются молча: миграция останавливается
+-- и перечисляет такие задачи до любых изменений, их нужно исправить вручную и запустить миграцию повторно
+DO $$
+DECLARE
+    bad_rows TEXT;
+    bad_count INT;
+BEGIN
+    SELECT COUNT(*), string_agg(format('%s=%L', id, status), ', ' ORDER BY id)
+    INTO bad_count, bad_rows
+    FROM (
+        SELECT id, status FROM task
+        WHERE status NOT IN ('pending', 'in_progress', 'completed', 'cancelled')
+        ORDER BY id
+        LIMIT 100
+    ) bad;
+
+    IF bad_count > 0 THEN
+        RAISE EXCEPTION 'task has statuses outside pending/in_progress/completed/cancelled (id=status, first 100): %', bad_rows;
+    END IF;
+END
+$$;
+
+-- Моменты завершения и отмены задачи
+ALTER TABLE task ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE task ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE;
+
+-- Для уже завершенных/отмененных задач точный момент неизвестен, берем время последнего обновления
+UPDATE task SET completed_at = updated_at WHERE status = 'completed' AND completed_at IS NULL;
+UPDATE task SET cancelled_at = updated_at WHERE status = 'cancelled' AND cancelled_at IS NULL;
+
+ALTER TABLE task ADD CONSTRAINT chk_task_status
+    CHECK (status IN ('pending', 'in_progress', 'completed', 'cancelled'));
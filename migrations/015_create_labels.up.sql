@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "label" (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#808080',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Имя метки уникально в пределах пользователя без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS idx_label_owner_name ON label(owner_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS "task_label" (
+    task_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    label_id INTEGER NOT NULL REFERENCES label(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, label_id)
+);
+
+-- Фильтр ListTasks по меткам идет от метки к задачам
+CREATE INDEX IF NOT EXISTS idx_task_label_label_task ON task_label(label_id, task_id);
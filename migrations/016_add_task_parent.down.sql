@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_parent_created_id;
+
+ALTER TABLE task DROP CONSTRAINT IF EXISTS chk_task_parent_not_self;
+ALTER TABLE task DROP CONSTRAINT IF EXISTS fk_task_parent;
+
+ALTER TABLE task DROP COLUMN IF EXISTS parent_id;
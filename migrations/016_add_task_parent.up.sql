@@ -0,0 +1,12 @@
+-- Подзадачи: задача может ссылаться на родительскую задачу того же владельца.
+-- Переподвешивание детей при удалении выполняет приложение, каскад - страховка
+ALTER TABLE task ADD COLUMN IF NOT EXISTS parent_id INTEGER;
+
+ALTER TABLE task ADD CONSTRAINT fk_task_parent
+    FOREIGN KEY (parent_id) REFERENCES task(id) ON DELETE CASCADE;
+ALTER TABLE task ADD CONSTRAINT chk_task_parent_not_self
+    CHECK (parent_id IS NULL OR parent_id <> id);
+
+-- Обход дерева (рекурсивный CTE) и ListSubtasks с сортировкой по умолчанию
+CREATE INDEX IF NOT EXISTS idx_task_parent_created_id ON task(parent_id, created_at, id)
+    WHERE parent_id IS NOT NULL;
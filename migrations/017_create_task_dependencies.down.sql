@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_dependency_blocked;
+DROP TABLE IF EXISTS "task_dependency";
@@ -0,0 +1,12 @@
+-- Зависимости задач: blocker_id блокирует blocked_id, пока не будет завершена или отменена
+CREATE TABLE IF NOT EXISTS "task_dependency" (
+    blocker_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    blocked_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT chk_task_dependency_not_self CHECK (blocker_id <> blocked_id)
+);
+
+-- Блокирующие задачи (blocked_by) и проверка перехода статуса
+CREATE INDEX IF NOT EXISTS idx_task_dependency_blocked ON task_dependency(blocked_id, blocker_id);
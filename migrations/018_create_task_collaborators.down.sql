@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_task_share_user;
+DROP INDEX IF EXISTS idx_task_assignee_user;
+
+DROP TABLE IF EXISTS "task_share";
+DROP TABLE IF EXISTS "task_assignee";
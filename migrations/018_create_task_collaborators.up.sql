@@ -0,0 +1,23 @@
+-- Исполнители задачи: получают право чтения и изменения задачи и ее подзадач
+CREATE TABLE IF NOT EXISTS "task_assignee" (
+    task_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (task_id, user_id)
+);
+
+-- Доступ к задаче и ее подзадачам для других пользователей: viewer - чтение, editor - изменение
+CREATE TABLE IF NOT EXISTS "task_share" (
+    task_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (task_id, user_id),
+    CONSTRAINT chk_task_share_role CHECK (role IN ('viewer', 'editor'))
+);
+
+-- ListTasks mode=assigned / mode=shared
+CREATE INDEX IF NOT EXISTS idx_task_assignee_user ON task_assignee(user_id, task_id);
+CREATE INDEX IF NOT EXISTS idx_task_share_user ON task_share(user_id, task_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_task_project_created_id;
+
+ALTER TABLE task DROP CONSTRAINT IF EXISTS fk_task_project;
+ALTER TABLE task DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE task DROP COLUMN IF EXISTS project_id;
+
+DROP INDEX IF EXISTS idx_project_member_user;
+
+DROP TABLE IF EXISTS "project_member";
+DROP TABLE IF EXISTS "project";
@@ -0,0 +1,35 @@
+-- Проекты: удаленный проект помечается deleted_at, а его задачи архивируются
+CREATE TABLE IF NOT EXISTS "project" (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Участники проекта: admin - управление проектом и всеми его задачами,
+-- editor - чтение и изменение задач, viewer - чтение
+CREATE TABLE IF NOT EXISTS "project_member" (
+    project_id INTEGER NOT NULL REFERENCES project(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (project_id, user_id),
+    CONSTRAINT chk_project_member_role CHECK (role IN ('admin', 'editor', 'viewer'))
+);
+
+-- ListProjects
+CREATE INDEX IF NOT EXISTS idx_project_member_user ON project_member(user_id, project_id);
+
+ALTER TABLE task ADD COLUMN IF NOT EXISTS project_id INTEGER;
+ALTER TABLE task ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE task ADD CONSTRAINT fk_task_project
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE SET NULL;
+
+-- ListTasks с фильтром по проекту и сортировкой по умолчанию
+CREATE INDEX IF NOT EXISTS idx_task_project_created_id ON task(project_id, created_at, id)
+    WHERE project_id IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_task_comment_revision_comment;
+DROP INDEX IF EXISTS idx_task_comment_parent;
+DROP INDEX IF EXISTS idx_task_comment_thread;
+
+DROP TABLE IF EXISTS "task_comment_revision";
+DROP TABLE IF EXISTS "task_comment";
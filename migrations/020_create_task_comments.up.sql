@@ -0,0 +1,28 @@
+-- Комментарии к задачам, ответы ссылаются на parent_comment_id.
+-- Удаленный комментарий остается в ветке с пустым текстом и deleted_at
+CREATE TABLE IF NOT EXISTS "task_comment" (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    parent_comment_id INTEGER REFERENCES task_comment(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Предыдущие версии текста комментария: строка пишется при каждом редактировании
+CREATE TABLE IF NOT EXISTS "task_comment_revision" (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES task_comment(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ListComments: корневые комментарии задачи или ответы на комментарий
+CREATE INDEX IF NOT EXISTS idx_task_comment_thread ON task_comment(task_id, parent_comment_id, created_at, id);
+-- Подсчет ответов
+CREATE INDEX IF NOT EXISTS idx_task_comment_parent ON task_comment(parent_comment_id)
+    WHERE parent_comment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_comment_revision_comment ON task_comment_revision(comment_id, id);
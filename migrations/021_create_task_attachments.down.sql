@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_task_attachment_uploader;
+DROP INDEX IF EXISTS idx_task_attachment_task;
+
+DROP TABLE IF EXISTS "task_attachment";
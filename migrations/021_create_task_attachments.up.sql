@@ -0,0 +1,17 @@
+-- Вложения задач. Сам файл лежит в хранилище по file_path, как и аватарки
+CREATE TABLE IF NOT EXISTS "task_attachment" (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    uploader_id INTEGER REFERENCES "user"(id) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    file_size BIGINT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ListAttachments и квота задачи
+CREATE INDEX IF NOT EXISTS idx_task_attachment_task ON task_attachment(task_id, id);
+-- Квота пользователя
+CREATE INDEX IF NOT EXISTS idx_task_attachment_uploader ON task_attachment(uploader_id)
+    WHERE uploader_id IS NOT NULL;
@@ -0,0 +1,8 @@
+UPDATE "user" SET avatar_url = 'var/' || avatar_url
+WHERE avatar_url LIKE 'avatars/%';
+
+UPDATE "task_attachment" SET storage_key = 'var/' || storage_key;
+ALTER TABLE "task_attachment" RENAME COLUMN storage_key TO file_path;
+
+UPDATE "avatar" SET storage_key = 'var/' || storage_key;
+ALTER TABLE "avatar" RENAME COLUMN storage_key TO file_path;
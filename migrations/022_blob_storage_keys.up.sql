@@ -0,0 +1,11 @@
+-- Путь к файлу становится ключом в BlobStore. Раньше файлы писались в var/<каталог>/<имя>,
+-- теперь var - корень локального хранилища, а ключ - путь внутри него
+ALTER TABLE "avatar" RENAME COLUMN file_path TO storage_key;
+UPDATE "avatar" SET storage_key = regexp_replace(storage_key, '^(\./)?var/', '');
+
+ALTER TABLE "task_attachment" RENAME COLUMN file_path TO storage_key;
+UPDATE "task_attachment" SET storage_key = regexp_replace(storage_key, '^(\./)?var/', '');
+
+-- avatar_url хранил тот же путь
+UPDATE "user" SET avatar_url = regexp_replace(avatar_url, '^(\./)?var/', '')
+WHERE avatar_url IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "task_attachment" DROP COLUMN sha256;
+ALTER TABLE "avatar" DROP COLUMN sha256;
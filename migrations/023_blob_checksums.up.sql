@@ -0,0 +1,3 @@
+-- SHA-256 содержимого считается при потоковой записи файла
+ALTER TABLE "avatar" ADD COLUMN sha256 TEXT NOT NULL DEFAULT '';
+ALTER TABLE "task_attachment" ADD COLUMN sha256 TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN ip_address,
+    DROP COLUMN user_agent,
+    DROP COLUMN session_started_at,
+    DROP COLUMN family_id;
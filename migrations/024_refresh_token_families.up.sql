@@ -0,0 +1,18 @@
+-- Refresh токены одной сессии (вход и все последующие ротации) объединяются в семейство.
+-- Устройство и IP записываются при выдаче каждого токена
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id VARCHAR(64),
+    ADD COLUMN session_started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip_address VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Выданные раньше токены - каждый своя сессия
+UPDATE refresh_tokens SET family_id = 'legacy-' || id, session_started_at = created_at;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN family_id SET NOT NULL,
+    ALTER COLUMN session_started_at SET NOT NULL,
+    ALTER COLUMN session_started_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
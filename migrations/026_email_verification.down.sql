@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_email_verification_tokens_user_created;
+
+DROP TABLE IF EXISTS email_verification_tokens;
+
+ALTER TABLE "user" DROP COLUMN email_verified_at;
+
+-- Как и до миграции, больше одного пользователя без email не пройдет UNIQUE
+UPDATE "user" SET email = '' WHERE email IS NULL;
+ALTER TABLE "user" ALTER COLUMN email SET NOT NULL;
+ALTER TABLE "user" ALTER COLUMN email SET DEFAULT '';
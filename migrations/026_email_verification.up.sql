@@ -0,0 +1,25 @@
+-- Пользователи без email хранят NULL, а не '': иначе UNIQUE не дает создать второго такого пользователя
+ALTER TABLE "user" ALTER COLUMN email DROP DEFAULT;
+ALTER TABLE "user" ALTER COLUMN email DROP NOT NULL;
+UPDATE "user" SET email = NULL WHERE email = '';
+
+-- Время подтверждения email, NULL - не подтвержден.
+-- Зарегистрированные до появления проверки пользователи считаются подтвержденными
+ALTER TABLE "user" ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+UPDATE "user" SET email_verified_at = created_at WHERE email IS NOT NULL;
+
+-- Токены подтверждения email: хранится только SHA-256 хеш и адрес, который подтверждается
+CREATE TABLE email_verification_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+-- По (user_id, created_at) считается лимит повторных отправок
+CREATE INDEX idx_email_verification_tokens_user_created ON email_verification_tokens(user_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_auth_audit_log_ip_address;
+DROP INDEX IF EXISTS idx_auth_audit_log_user_id;
+
+DROP TABLE IF EXISTS auth_audit_log;
+DROP TABLE IF EXISTS login_attempts;
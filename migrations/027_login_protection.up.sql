@@ -0,0 +1,24 @@
+-- Счетчики неудачных входов. key - "email:<адрес>" или "ip:<адрес>"
+CREATE TABLE login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    blocked_until TIMESTAMP WITH TIME ZONE
+);
+
+-- Журнал входов и блокировок
+CREATE TABLE auth_audit_log (
+    id SERIAL PRIMARY KEY,
+    event VARCHAR(32) NOT NULL,
+    user_id INT,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_auth_audit_log_user_id ON auth_audit_log(user_id, created_at);
+CREATE INDEX idx_auth_audit_log_ip_address ON auth_audit_log(ip_address, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_outbox_dead_at;
+DROP INDEX IF EXISTS idx_audit_outbox_pending;
+CREATE INDEX idx_audit_outbox_pending ON "audit_outbox"(next_attempt_at, id) WHERE sent_at IS NULL;
+
+ALTER TABLE "audit_outbox" DROP COLUMN IF EXISTS dead_at;
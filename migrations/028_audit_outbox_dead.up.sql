@@ -0,0 +1,6 @@
+-- Сообщения, которые нельзя опубликовать (битый payload), больше не забираются relay
+ALTER TABLE "audit_outbox" ADD COLUMN dead_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_audit_outbox_pending;
+CREATE INDEX idx_audit_outbox_pending ON "audit_outbox"(next_attempt_at, id) WHERE sent_at IS NULL AND dead_at IS NULL;
+CREATE INDEX idx_audit_outbox_dead_at ON "audit_outbox"(dead_at) WHERE dead_at IS NOT NULL;
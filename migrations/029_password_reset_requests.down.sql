@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_password_reset_requests_key;
+
+DROP TABLE IF EXISTS password_reset_requests;
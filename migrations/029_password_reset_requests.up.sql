@@ -0,0 +1,9 @@
+-- Запросы сброса пароля для лимита: key - "email:<адрес>" или "ip:<адрес>".
+-- Пишутся и для незарегистрированных адресов, чтобы лимит не выдавал, есть ли аккаунт
+CREATE TABLE password_reset_requests (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR(320) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_reset_requests_key ON password_reset_requests(key, created_at);
@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,11 +24,23 @@ const (
 )
 
 type CreateTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	OwnerId       int32                  `protobuf:"varint,4,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Status      string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// Игнорируется: владельцем задачи становится пользователь из access token
+	OwnerId int32 `protobuf:"varint,4,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// RFC3339, необязательно
+	StartAt string `protobuf:"bytes,5,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	// RFC3339, необязательно
+	DueAt string `protobuf:"bytes,6,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// low, medium (по умолчанию), high, urgent
+	Priority string `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Родительская задача (своя или из того же проекта), 0 - корневая задача
+	ParentId int32 `protobuf:"varint,8,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Проект, где вызывающий editor или admin; 0 - без проекта. Подзадача наследует проект родителя.
+	// Задачу нельзя перенести в другой проект
+	ProjectId     int32 `protobuf:"varint,9,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,6 +103,41 @@ func (x *CreateTaskRequest) GetOwnerId() int32 {
 	return 0
 }
 
+func (x *CreateTaskRequest) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDueAt() string {
+	if x != nil {
+		return x.DueAt
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
 type GetTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -135,11 +183,22 @@ func (x *GetTaskRequest) GetId() int32 {
 }
 
 type UpdateTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	// Переход должен быть разрешен графом статусов, иначе FAILED_PRECONDITION
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Ожидаемая версия задачи (или заголовок If-Match), 0 - без проверки
+	ExpectedVersion int32 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// RFC3339; не передано - не менять, пустая строка - очистить
+	StartAt *string `protobuf:"bytes,6,opt,name=start_at,json=startAt,proto3,oneof" json:"start_at,omitempty"`
+	// RFC3339; не передано - не менять, пустая строка - очистить
+	DueAt *string `protobuf:"bytes,7,opt,name=due_at,json=dueAt,proto3,oneof" json:"due_at,omitempty"`
+	// low, medium, high, urgent
+	Priority *string `protobuf:"bytes,8,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	// Перенос под другую задачу; 0 - сделать корневой. Нельзя перенести под себя или потомка
+	ParentId      *int32 `protobuf:"varint,9,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -202,9 +261,49 @@ func (x *UpdateTaskRequest) GetStatus() string {
 	return ""
 }
 
+func (x *UpdateTaskRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateTaskRequest) GetStartAt() string {
+	if x != nil && x.StartAt != nil {
+		return *x.StartAt
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetDueAt() string {
+	if x != nil && x.DueAt != nil {
+		return *x.DueAt
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetPriority() string {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetParentId() int32 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
 type DeleteTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Ожидаемая версия задачи (или заголовок If-Match), 0 - без проверки
+	ExpectedVersion int32 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Что делать с подзадачами: cascade - удалить, reparent - поднять к родителю.
+	// Пусто - настройка сервера (TASK_DELETE_SUBTASKS)
+	Subtasks      string `protobuf:"bytes,3,opt,name=subtasks,proto3" json:"subtasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -246,6 +345,20 @@ func (x *DeleteTaskRequest) GetId() int32 {
 	return 0
 }
 
+func (x *DeleteTaskRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *DeleteTaskRequest) GetSubtasks() string {
+	if x != nil {
+		return x.Subtasks
+	}
+	return ""
+}
+
 type DeleteTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -291,8 +404,36 @@ func (x *DeleteTaskResponse) GetSuccess() bool {
 }
 
 type ListTasksRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// По умолчанию 20, максимум 100
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token из предыдущего ответа
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// created_at (по умолчанию), updated_at, title, id
+	SortBy string `protobuf:"bytes,4,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc или desc (по умолчанию)
+	SortDirection string `protobuf:"bytes,5,opt,name=sort_direction,json=sortDirection,proto3" json:"sort_direction,omitempty"`
+	// Пусто - не считать total, exact - точный подсчет, estimated - оценка планировщика
+	TotalMode string `protobuf:"bytes,6,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"`
+	// low, medium, high, urgent
+	Priority string `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Только просроченные: срок прошел, а задача не completed и не cancelled
+	Overdue bool `protobuf:"varint,8,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	// RFC3339, срок раньше указанного момента
+	DueBefore string `protobuf:"bytes,9,opt,name=due_before,json=dueBefore,proto3" json:"due_before,omitempty"`
+	// RFC3339, срок не раньше указанного момента
+	DueAfter string `protobuf:"bytes,10,opt,name=due_after,json=dueAfter,proto3" json:"due_after,omitempty"`
+	// Только задачи с метками
+	LabelIds []int32 `protobuf:"varint,11,rep,packed,name=label_ids,json=labelIds,proto3" json:"label_ids,omitempty"`
+	// any (по умолчанию) - хотя бы одна из label_ids, all - все сразу
+	LabelMatch string `protobuf:"bytes,12,opt,name=label_match,json=labelMatch,proto3" json:"label_match,omitempty"`
+	// owned (по умолчанию) - свои задачи, assigned - где я исполнитель, shared - к которым мне выдан доступ
+	Mode string `protobuf:"bytes,13,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Задачи проекта. Без mode - все задачи проекта, с mode - только свои/назначенные/доступные в нем
+	ProjectId int32 `protobuf:"varint,14,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// Только архивные задачи (из удаленных проектов) вместо активных
+	Archived      bool `protobuf:"varint,15,opt,name=archived,proto3" json:"archived,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -334,11 +475,113 @@ func (x *ListTasksRequest) GetStatus() string {
 	return ""
 }
 
+func (x *ListTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetSortDirection() string {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetTotalMode() string {
+	if x != nil {
+		return x.TotalMode
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetOverdue() bool {
+	if x != nil {
+		return x.Overdue
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetDueBefore() string {
+	if x != nil {
+		return x.DueBefore
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetDueAfter() string {
+	if x != nil {
+		return x.DueAfter
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetLabelIds() []int32 {
+	if x != nil {
+		return x.LabelIds
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetLabelMatch() string {
+	if x != nil {
+		return x.LabelMatch
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
 type ListTasksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*TaskResponse        `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tasks []*TaskResponse        `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Пусто, если страница последняя
+	NextPageToken  string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Total          int64  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	TotalEstimated bool   `protobuf:"varint,4,opt,name=total_estimated,json=totalEstimated,proto3" json:"total_estimated,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ListTasksResponse) Reset() {
@@ -378,15 +621,55 @@ func (x *ListTasksResponse) GetTasks() []*TaskResponse {
 	return nil
 }
 
+func (x *ListTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListTasksResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListTasksResponse) GetTotalEstimated() bool {
+	if x != nil {
+		return x.TotalEstimated
+	}
+	return false
+}
+
 type TaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	OwnerId       int32                  `protobuf:"varint,5,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status      string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	OwnerId     int32                  `protobuf:"varint,5,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	CreatedAt   string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Версия задачи, в HTTP ответе дублируется в ETag
+	Version int32 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	// RFC3339, пусто если не задано
+	StartAt string `protobuf:"bytes,9,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	// RFC3339, пусто если не задано
+	DueAt    string `protobuf:"bytes,10,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Priority string `protobuf:"bytes,11,opt,name=priority,proto3" json:"priority,omitempty"`
+	// RFC3339, когда задача перешла в completed
+	CompletedAt string `protobuf:"bytes,12,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// RFC3339, когда задача перешла в cancelled
+	CancelledAt string   `protobuf:"bytes,13,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	Labels      []*Label `protobuf:"bytes,14,rep,name=labels,proto3" json:"labels,omitempty"`
+	// 0 - корневая задача
+	ParentId    int32   `protobuf:"varint,15,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	AssigneeIds []int32 `protobuf:"varint,16,rep,packed,name=assignee_ids,json=assigneeIds,proto3" json:"assignee_ids,omitempty"`
+	// 0 - задача вне проекта
+	ProjectId int32 `protobuf:"varint,17,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// RFC3339, когда задача ушла в архив вместе с проектом
+	ArchivedAt    string `protobuf:"bytes,18,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -470,51 +753,2946 @@ func (x *TaskResponse) GetUpdatedAt() string {
 	return ""
 }
 
-var File_task_service_proto protoreflect.FileDescriptor
+func (x *TaskResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
 
-const file_task_service_proto_rawDesc = "" +
-	"\n" +
-	"\x12task_service.proto\x12\atask.v1\x1a\x1cgoogle/api/annotations.proto\"~\n" +
-	"\x11CreateTaskRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
-	"\bowner_id\x18\x04 \x01(\x05R\aownerId\" \n" +
-	"\x0eGetTaskRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"\x88\x01\n" +
-	"\x11UpdateTaskRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
-	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x16\n" +
-	"\x06status\x18\x04 \x01(\tR\x06statusB\x0e\n" +
-	"\f_description\"#\n" +
-	"\x11DeleteTaskRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\".\n" +
-	"\x12DeleteTaskResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"*\n" +
-	"\x10ListTasksRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"@\n" +
-	"\x11ListTasksResponse\x12+\n" +
-	"\x05tasks\x18\x01 \x03(\v2\x15.task.v1.TaskResponseR\x05tasks\"\xc7\x01\n" +
-	"\fTaskResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06status\x18\x04 \x01(\tR\x06status\x12\x19\n" +
-	"\bowner_id\x18\x05 \x01(\x05R\aownerId\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\a \x01(\tR\tupdatedAt2\xdd\x03\n" +
-	"\vTaskService\x12Y\n" +
-	"\n" +
-	"CreateTask\x12\x1a.task.v1.CreateTaskRequest\x1a\x15.task.v1.TaskResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/v1/tasks\x12U\n" +
-	"\aGetTask\x12\x17.task.v1.GetTaskRequest\x1a\x15.task.v1.TaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/v1/tasks/{id}\x12^\n" +
-	"\n" +
-	"UpdateTask\x12\x1a.task.v1.UpdateTaskRequest\x1a\x15.task.v1.TaskResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\x1a\x12/api/v1/tasks/{id}\x12a\n" +
-	"\n" +
-	"DeleteTask\x12\x1a.task.v1.DeleteTaskRequest\x1a\x1b.task.v1.DeleteTaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/api/v1/tasks/{id}\x12Y\n" +
-	"\tListTasks\x12\x19.task.v1.ListTasksRequest\x1a\x1a.task.v1.ListTasksResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/api/v1/tasksB*Z(github.com/St1cky1/task-service/proto/pbb\x06proto3"
+func (x *TaskResponse) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *TaskResponse) GetDueAt() string {
+	if x != nil {
+		return x.DueAt
+	}
+	return ""
+}
+
+func (x *TaskResponse) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *TaskResponse) GetCompletedAt() string {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return ""
+}
+
+func (x *TaskResponse) GetCancelledAt() string {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return ""
+}
+
+func (x *TaskResponse) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *TaskResponse) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *TaskResponse) GetAssigneeIds() []int32 {
+	if x != nil {
+		return x.AssigneeIds
+	}
+	return nil
+}
+
+func (x *TaskResponse) GetProjectId() int32 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *TaskResponse) GetArchivedAt() string {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return ""
+}
+
+type ListSubtasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// По умолчанию 20, максимум 100
+	PageSize  int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// created_at (по умолчанию), updated_at, title, id
+	SortBy        string `protobuf:"bytes,5,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortDirection string `protobuf:"bytes,6,opt,name=sort_direction,json=sortDirection,proto3" json:"sort_direction,omitempty"`
+	TotalMode     string `protobuf:"bytes,7,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubtasksRequest) Reset() {
+	*x = ListSubtasksRequest{}
+	mi := &file_task_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubtasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubtasksRequest) ProtoMessage() {}
+
+func (x *ListSubtasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubtasksRequest.ProtoReflect.Descriptor instead.
+func (*ListSubtasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSubtasksRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ListSubtasksRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListSubtasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubtasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListSubtasksRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListSubtasksRequest) GetSortDirection() string {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ""
+}
+
+func (x *ListSubtasksRequest) GetTotalMode() string {
+	if x != nil {
+		return x.TotalMode
+	}
+	return ""
+}
+
+type GetTaskTreeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Сколько уровней подзадач вернуть: по умолчанию 3, максимум 10
+	Depth         int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskTreeRequest) Reset() {
+	*x = GetTaskTreeRequest{}
+	mi := &file_task_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskTreeRequest) ProtoMessage() {}
+
+func (x *GetTaskTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskTreeRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTaskTreeRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetTaskTreeRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type TaskTreeNode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Task  *TaskResponse          `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// Процент выполнения по всему поддереву, 0-100
+	Progress int32 `protobuf:"varint,2,opt,name=progress,proto3" json:"progress,omitempty"`
+	// Пусто на последнем запрошенном уровне, даже если подзадачи есть
+	Children      []*TaskTreeNode `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskTreeNode) Reset() {
+	*x = TaskTreeNode{}
+	mi := &file_task_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskTreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTreeNode) ProtoMessage() {}
+
+func (x *TaskTreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTreeNode.ProtoReflect.Descriptor instead.
+func (*TaskTreeNode) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TaskTreeNode) GetTask() *TaskResponse {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskTreeNode) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *TaskTreeNode) GetChildren() []*TaskTreeNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type GetTaskHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Create, Update, Delete, CommentCreate, CommentUpdate или CommentDelete
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// Пользователь, совершивший действие
+	ActorId int32 `protobuf:"varint,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// RFC3339, включительно
+	From string `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	// RFC3339, не включительно
+	To string `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	// По умолчанию 20, максимум 100
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token из предыдущего ответа
+	PageToken string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// asc или desc (по умолчанию)
+	SortDirection string `protobuf:"bytes,8,opt,name=sort_direction,json=sortDirection,proto3" json:"sort_direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskHistoryRequest) Reset() {
+	*x = GetTaskHistoryRequest{}
+	mi := &file_task_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskHistoryRequest) ProtoMessage() {}
+
+func (x *GetTaskHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTaskHistoryRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetTaskHistoryRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *GetTaskHistoryRequest) GetActorId() int32 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *GetTaskHistoryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetTaskHistoryRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetTaskHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetTaskHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetTaskHistoryRequest) GetSortDirection() string {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ""
+}
+
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue      *structpb.Value        `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      *structpb.Value        `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_task_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetOldValue() *structpb.Value {
+	if x != nil {
+		return x.OldValue
+	}
+	return nil
+}
+
+func (x *FieldChange) GetNewValue() *structpb.Value {
+	if x != nil {
+		return x.NewValue
+	}
+	return nil
+}
+
+type TaskHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId        int32                  `protobuf:"varint,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ActorId       int32                  `protobuf:"varint,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	OldValues     *structpb.Struct       `protobuf:"bytes,5,opt,name=old_values,json=oldValues,proto3" json:"old_values,omitempty"`
+	NewValues     *structpb.Struct       `protobuf:"bytes,6,opt,name=new_values,json=newValues,proto3" json:"new_values,omitempty"`
+	Changes       []*FieldChange         `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,8,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskHistoryEntry) Reset() {
+	*x = TaskHistoryEntry{}
+	mi := &file_task_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskHistoryEntry) ProtoMessage() {}
+
+func (x *TaskHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskHistoryEntry.ProtoReflect.Descriptor instead.
+func (*TaskHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TaskHistoryEntry) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskHistoryEntry) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskHistoryEntry) GetActorId() int32 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *TaskHistoryEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *TaskHistoryEntry) GetOldValues() *structpb.Struct {
+	if x != nil {
+		return x.OldValues
+	}
+	return nil
+}
+
+func (x *TaskHistoryEntry) GetNewValues() *structpb.Struct {
+	if x != nil {
+		return x.NewValues
+	}
+	return nil
+}
+
+func (x *TaskHistoryEntry) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *TaskHistoryEntry) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type GetTaskHistoryResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Entries []*TaskHistoryEntry    `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Пусто, если страница последняя
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskHistoryResponse) Reset() {
+	*x = GetTaskHistoryResponse{}
+	mi := &file_task_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskHistoryResponse) ProtoMessage() {}
+
+func (x *GetTaskHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTaskHistoryResponse) GetEntries() []*TaskHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetTaskHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// blocker_id блокирует task_id: пока blocker не completed/cancelled,
+// task_id нельзя перевести в in_progress или completed
+type TaskDependencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	BlockerId     int32                  `protobuf:"varint,2,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskDependencyRequest) Reset() {
+	*x = TaskDependencyRequest{}
+	mi := &file_task_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskDependencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskDependencyRequest) ProtoMessage() {}
+
+func (x *TaskDependencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskDependencyRequest.ProtoReflect.Descriptor instead.
+func (*TaskDependencyRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TaskDependencyRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskDependencyRequest) GetBlockerId() int32 {
+	if x != nil {
+		return x.BlockerId
+	}
+	return 0
+}
+
+type ListDependenciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDependenciesRequest) Reset() {
+	*x = ListDependenciesRequest{}
+	mi := &file_task_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDependenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDependenciesRequest) ProtoMessage() {}
+
+func (x *ListDependenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDependenciesRequest.ProtoReflect.Descriptor instead.
+func (*ListDependenciesRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListDependenciesRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+type TaskDependencies struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Задачи, которые блокируют эту задачу
+	BlockedBy []*TaskResponse `protobuf:"bytes,1,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	// Задачи, которые блокирует эта задача
+	Blocks        []*TaskResponse `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskDependencies) Reset() {
+	*x = TaskDependencies{}
+	mi := &file_task_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskDependencies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskDependencies) ProtoMessage() {}
+
+func (x *TaskDependencies) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskDependencies.ProtoReflect.Descriptor instead.
+func (*TaskDependencies) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TaskDependencies) GetBlockedBy() []*TaskResponse {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *TaskDependencies) GetBlocks() []*TaskResponse {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+// Исполнитель может читать и изменять задачу и ее подзадачи
+type TaskAssigneeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskAssigneeRequest) Reset() {
+	*x = TaskAssigneeRequest{}
+	mi := &file_task_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskAssigneeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskAssigneeRequest) ProtoMessage() {}
+
+func (x *TaskAssigneeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskAssigneeRequest.ProtoReflect.Descriptor instead.
+func (*TaskAssigneeRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TaskAssigneeRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskAssigneeRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Доступ к задаче и ее подзадачам выдает только владелец
+type ShareTaskRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	UserId int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// viewer - чтение, editor - чтение и изменение
+	Role          string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareTaskRequest) Reset() {
+	*x = ShareTaskRequest{}
+	mi := &file_task_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareTaskRequest) ProtoMessage() {}
+
+func (x *ShareTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareTaskRequest.ProtoReflect.Descriptor instead.
+func (*ShareTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ShareTaskRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *ShareTaskRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ShareTaskRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type UnshareTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareTaskRequest) Reset() {
+	*x = UnshareTaskRequest{}
+	mi := &file_task_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareTaskRequest) ProtoMessage() {}
+
+func (x *UnshareTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareTaskRequest.ProtoReflect.Descriptor instead.
+func (*UnshareTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UnshareTaskRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *UnshareTaskRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type UnshareTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareTaskResponse) Reset() {
+	*x = UnshareTaskResponse{}
+	mi := &file_task_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareTaskResponse) ProtoMessage() {}
+
+func (x *UnshareTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareTaskResponse.ProtoReflect.Descriptor instead.
+func (*UnshareTaskResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UnshareTaskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type TaskShare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskShare) Reset() {
+	*x = TaskShare{}
+	mi := &file_task_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskShare) ProtoMessage() {}
+
+func (x *TaskShare) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskShare.ProtoReflect.Descriptor instead.
+func (*TaskShare) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TaskShare) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskShare) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TaskShare) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *TaskShare) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListTaskSharesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskSharesRequest) Reset() {
+	*x = ListTaskSharesRequest{}
+	mi := &file_task_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskSharesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskSharesRequest) ProtoMessage() {}
+
+func (x *ListTaskSharesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskSharesRequest.ProtoReflect.Descriptor instead.
+func (*ListTaskSharesRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListTaskSharesRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+type ListTaskSharesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shares        []*TaskShare           `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskSharesResponse) Reset() {
+	*x = ListTaskSharesResponse{}
+	mi := &file_task_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskSharesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskSharesResponse) ProtoMessage() {}
+
+func (x *ListTaskSharesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskSharesResponse.ProtoReflect.Descriptor instead.
+func (*ListTaskSharesResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListTaskSharesResponse) GetShares() []*TaskShare {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+// Комментировать может любой, кто может читать задачу
+type CreateCommentRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Ответ на комментарий той же задачи, 0 - корневой комментарий
+	ParentCommentId int32 `protobuf:"varint,2,opt,name=parent_comment_id,json=parentCommentId,proto3" json:"parent_comment_id,omitempty"`
+	// 1-10000 символов
+	Body          string `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCommentRequest) Reset() {
+	*x = CreateCommentRequest{}
+	mi := &file_task_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCommentRequest) ProtoMessage() {}
+
+func (x *CreateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCommentRequest.ProtoReflect.Descriptor instead.
+func (*CreateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateCommentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *CreateCommentRequest) GetParentCommentId() int32 {
+	if x != nil {
+		return x.ParentCommentId
+	}
+	return 0
+}
+
+func (x *CreateCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type ListCommentsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Ответы на этот комментарий, 0 - корневые комментарии задачи
+	ParentCommentId int32 `protobuf:"varint,2,opt,name=parent_comment_id,json=parentCommentId,proto3" json:"parent_comment_id,omitempty"`
+	// По умолчанию 20, максимум 100
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token из предыдущего ответа
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// asc (по умолчанию) или desc, сортировка по created_at
+	SortDirection string `protobuf:"bytes,5,opt,name=sort_direction,json=sortDirection,proto3" json:"sort_direction,omitempty"`
+	// Пусто - не считать total, exact - точный подсчет, estimated - оценка планировщика
+	TotalMode     string `protobuf:"bytes,6,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsRequest) Reset() {
+	*x = ListCommentsRequest{}
+	mi := &file_task_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsRequest) ProtoMessage() {}
+
+func (x *ListCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListCommentsRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetParentCommentId() int32 {
+	if x != nil {
+		return x.ParentCommentId
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetSortDirection() string {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetTotalMode() string {
+	if x != nil {
+		return x.TotalMode
+	}
+	return ""
+}
+
+type ListCommentsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Comments []*TaskComment         `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	// Пусто, если страница последняя
+	NextPageToken  string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Total          int64  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	TotalEstimated bool   `protobuf:"varint,4,opt,name=total_estimated,json=totalEstimated,proto3" json:"total_estimated,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_task_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListCommentsResponse) GetComments() []*TaskComment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ListCommentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListCommentsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListCommentsResponse) GetTotalEstimated() bool {
+	if x != nil {
+		return x.TotalEstimated
+	}
+	return false
+}
+
+// Редактировать может только автор, предыдущий текст сохраняется в истории правок
+type UpdateCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CommentId     int32                  `protobuf:"varint,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommentRequest) Reset() {
+	*x = UpdateCommentRequest{}
+	mi := &file_task_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentRequest) ProtoMessage() {}
+
+func (x *UpdateCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateCommentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *UpdateCommentRequest) GetCommentId() int32 {
+	if x != nil {
+		return x.CommentId
+	}
+	return 0
+}
+
+func (x *UpdateCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+// Удалить может автор или владелец задачи. Ответы остаются в ветке
+type DeleteCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CommentId     int32                  `protobuf:"varint,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_task_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentRequest) ProtoMessage() {}
+
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteCommentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *DeleteCommentRequest) GetCommentId() int32 {
+	if x != nil {
+		return x.CommentId
+	}
+	return 0
+}
+
+type DeleteCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentResponse) Reset() {
+	*x = DeleteCommentResponse{}
+	mi := &file_task_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentResponse) ProtoMessage() {}
+
+func (x *DeleteCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommentResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteCommentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListCommentRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CommentId     int32                  `protobuf:"varint,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentRevisionsRequest) Reset() {
+	*x = ListCommentRevisionsRequest{}
+	mi := &file_task_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentRevisionsRequest) ProtoMessage() {}
+
+func (x *ListCommentRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListCommentRevisionsRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *ListCommentRevisionsRequest) GetCommentId() int32 {
+	if x != nil {
+		return x.CommentId
+	}
+	return 0
+}
+
+type ListCommentRevisionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// От старых версий к новым, без текущего текста
+	Revisions     []*CommentRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentRevisionsResponse) Reset() {
+	*x = ListCommentRevisionsResponse{}
+	mi := &file_task_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentRevisionsResponse) ProtoMessage() {}
+
+func (x *ListCommentRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListCommentRevisionsResponse) GetRevisions() []*CommentRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type TaskComment struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId int32                  `protobuf:"varint,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// 0 - корневой комментарий
+	ParentCommentId int32 `protobuf:"varint,3,opt,name=parent_comment_id,json=parentCommentId,proto3" json:"parent_comment_id,omitempty"`
+	AuthorId        int32 `protobuf:"varint,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Пусто у удаленного комментария
+	Body      string `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	CreatedAt string `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// RFC3339, пусто если текст не редактировался
+	EditedAt string `protobuf:"bytes,7,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	Deleted  bool   `protobuf:"varint,8,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// Количество неудаленных прямых ответов
+	ReplyCount    int32 `protobuf:"varint,9,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskComment) Reset() {
+	*x = TaskComment{}
+	mi := &file_task_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskComment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskComment) ProtoMessage() {}
+
+func (x *TaskComment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskComment.ProtoReflect.Descriptor instead.
+func (*TaskComment) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TaskComment) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskComment) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskComment) GetParentCommentId() int32 {
+	if x != nil {
+		return x.ParentCommentId
+	}
+	return 0
+}
+
+func (x *TaskComment) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+func (x *TaskComment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *TaskComment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *TaskComment) GetEditedAt() string {
+	if x != nil {
+		return x.EditedAt
+	}
+	return ""
+}
+
+func (x *TaskComment) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *TaskComment) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+type CommentRevision struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Body  string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	// Когда этот текст был заменен
+	ReplacedAt    string `protobuf:"bytes,3,opt,name=replaced_at,json=replacedAt,proto3" json:"replaced_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentRevision) Reset() {
+	*x = CommentRevision{}
+	mi := &file_task_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentRevision) ProtoMessage() {}
+
+func (x *CommentRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentRevision.ProtoReflect.Descriptor instead.
+func (*CommentRevision) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CommentRevision) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CommentRevision) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CommentRevision) GetReplacedAt() string {
+	if x != nil {
+		return x.ReplacedAt
+	}
+	return ""
+}
+
+type UploadAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequest) Reset() {
+	*x = UploadAttachmentRequest{}
+	mi := &file_task_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequest) ProtoMessage() {}
+
+func (x *UploadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UploadAttachmentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *UploadAttachmentRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *UploadAttachmentRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DownloadAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AttachmentId  int32                  `protobuf:"varint,2,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentRequest) Reset() {
+	*x = DownloadAttachmentRequest{}
+	mi := &file_task_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentRequest) ProtoMessage() {}
+
+func (x *DownloadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DownloadAttachmentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *DownloadAttachmentRequest) GetAttachmentId() int32 {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return 0
+}
+
+type DownloadAttachmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// Заполнены только в первом сообщении
+	ContentType   string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileName      string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentResponse) Reset() {
+	*x = DownloadAttachmentResponse{}
+	mi := &file_task_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentResponse) ProtoMessage() {}
+
+func (x *DownloadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DownloadAttachmentResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *DownloadAttachmentResponse) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+type ListAttachmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttachmentsRequest) Reset() {
+	*x = ListAttachmentsRequest{}
+	mi := &file_task_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttachmentsRequest) ProtoMessage() {}
+
+func (x *ListAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListAttachmentsRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+type ListAttachmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachments   []*TaskAttachment      `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAttachmentsResponse) Reset() {
+	*x = ListAttachmentsResponse{}
+	mi := &file_task_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAttachmentsResponse) ProtoMessage() {}
+
+func (x *ListAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListAttachmentsResponse) GetAttachments() []*TaskAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type DeleteAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AttachmentId  int32                  `protobuf:"varint,2,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAttachmentRequest) Reset() {
+	*x = DeleteAttachmentRequest{}
+	mi := &file_task_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAttachmentRequest) ProtoMessage() {}
+
+func (x *DeleteAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteAttachmentRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *DeleteAttachmentRequest) GetAttachmentId() int32 {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return 0
+}
+
+type DeleteAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAttachmentResponse) Reset() {
+	*x = DeleteAttachmentResponse{}
+	mi := &file_task_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAttachmentResponse) ProtoMessage() {}
+
+func (x *DeleteAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeleteAttachmentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type TaskAttachment struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId int32                  `protobuf:"varint,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// 0, если загрузивший пользователь удален
+	UploaderId int32  `protobuf:"varint,3,opt,name=uploader_id,json=uploaderId,proto3" json:"uploader_id,omitempty"`
+	FileName   string `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FileSize   int64  `protobuf:"varint,5,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	// Определяется сервером по содержимому файла
+	ContentType   string `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	CreatedAt     string `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskAttachment) Reset() {
+	*x = TaskAttachment{}
+	mi := &file_task_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskAttachment) ProtoMessage() {}
+
+func (x *TaskAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskAttachment.ProtoReflect.Descriptor instead.
+func (*TaskAttachment) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TaskAttachment) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskAttachment) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskAttachment) GetUploaderId() int32 {
+	if x != nil {
+		return x.UploaderId
+	}
+	return 0
+}
+
+func (x *TaskAttachment) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *TaskAttachment) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *TaskAttachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *TaskAttachment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type Label struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// #rrggbb
+	Color         string `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	CreatedAt     string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Label) Reset() {
+	*x = Label{}
+	mi := &file_task_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Label) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Label) ProtoMessage() {}
+
+func (x *Label) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Label.ProtoReflect.Descriptor instead.
+func (*Label) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Label) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Label) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Label) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Label) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateLabelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// #rrggbb, по умолчанию #808080
+	Color         string `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLabelRequest) Reset() {
+	*x = CreateLabelRequest{}
+	mi := &file_task_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRequest) ProtoMessage() {}
+
+func (x *CreateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CreateLabelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateLabelRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type ListLabelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelsRequest) Reset() {
+	*x = ListLabelsRequest{}
+	mi := &file_task_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelsRequest) ProtoMessage() {}
+
+func (x *ListLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelsRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{45}
+}
+
+type ListLabelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Labels        []*Label               `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelsResponse) Reset() {
+	*x = ListLabelsResponse{}
+	mi := &file_task_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelsResponse) ProtoMessage() {}
+
+func (x *ListLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelsResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelsResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListLabelsResponse) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type UpdateLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Color         *string                `protobuf:"bytes,3,opt,name=color,proto3,oneof" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLabelRequest) Reset() {
+	*x = UpdateLabelRequest{}
+	mi := &file_task_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLabelRequest) ProtoMessage() {}
+
+func (x *UpdateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLabelRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *UpdateLabelRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateLabelRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateLabelRequest) GetColor() string {
+	if x != nil && x.Color != nil {
+		return *x.Color
+	}
+	return ""
+}
+
+type DeleteLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelRequest) Reset() {
+	*x = DeleteLabelRequest{}
+	mi := &file_task_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelRequest) ProtoMessage() {}
+
+func (x *DeleteLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLabelRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *DeleteLabelRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelResponse) Reset() {
+	*x = DeleteLabelResponse{}
+	mi := &file_task_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelResponse) ProtoMessage() {}
+
+func (x *DeleteLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLabelResponse) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DeleteLabelResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type TaskLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        int32                  `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	LabelId       int32                  `protobuf:"varint,2,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskLabelRequest) Reset() {
+	*x = TaskLabelRequest{}
+	mi := &file_task_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskLabelRequest) ProtoMessage() {}
+
+func (x *TaskLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskLabelRequest.ProtoReflect.Descriptor instead.
+func (*TaskLabelRequest) Descriptor() ([]byte, []int) {
+	return file_task_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *TaskLabelRequest) GetTaskId() int32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskLabelRequest) GetLabelId() int32 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
+var File_task_service_proto protoreflect.FileDescriptor
+
+const file_task_service_proto_rawDesc = "" +
+	"\n" +
+	"\x12task_service.proto\x12\atask.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\x88\x02\n" +
+	"\x11CreateTaskRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
+	"\bowner_id\x18\x04 \x01(\x05R\aownerId\x12\x19\n" +
+	"\bstart_at\x18\x05 \x01(\tR\astartAt\x12\x15\n" +
+	"\x06due_at\x18\x06 \x01(\tR\x05dueAt\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12\x1b\n" +
+	"\tparent_id\x18\b \x01(\x05R\bparentId\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\t \x01(\x05R\tprojectId\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\xe5\x02\n" +
+	"\x11UpdateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x05R\x0fexpectedVersion\x12\x1e\n" +
+	"\bstart_at\x18\x06 \x01(\tH\x01R\astartAt\x88\x01\x01\x12\x1a\n" +
+	"\x06due_at\x18\a \x01(\tH\x02R\x05dueAt\x88\x01\x01\x12\x1f\n" +
+	"\bpriority\x18\b \x01(\tH\x03R\bpriority\x88\x01\x01\x12 \n" +
+	"\tparent_id\x18\t \x01(\x05H\x04R\bparentId\x88\x01\x01B\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_start_atB\t\n" +
+	"\a_due_atB\v\n" +
+	"\t_priorityB\f\n" +
+	"\n" +
+	"_parent_id\"j\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x05R\x0fexpectedVersion\x12\x1a\n" +
+	"\bsubtasks\x18\x03 \x01(\tR\bsubtasks\".\n" +
+	"\x12DeleteTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc4\x03\n" +
+	"\x10ListTasksRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x04 \x01(\tR\x06sortBy\x12%\n" +
+	"\x0esort_direction\x18\x05 \x01(\tR\rsortDirection\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\x06 \x01(\tR\ttotalMode\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12\x18\n" +
+	"\aoverdue\x18\b \x01(\bR\aoverdue\x12\x1d\n" +
+	"\n" +
+	"due_before\x18\t \x01(\tR\tdueBefore\x12\x1b\n" +
+	"\tdue_after\x18\n" +
+	" \x01(\tR\bdueAfter\x12\x1b\n" +
+	"\tlabel_ids\x18\v \x03(\x05R\blabelIds\x12\x1f\n" +
+	"\vlabel_match\x18\f \x01(\tR\n" +
+	"labelMatch\x12\x12\n" +
+	"\x04mode\x18\r \x01(\tR\x04mode\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x0e \x01(\x05R\tprojectId\x12\x1a\n" +
+	"\barchived\x18\x0f \x01(\bR\barchived\"\xa7\x01\n" +
+	"\x11ListTasksResponse\x12+\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x15.task.v1.TaskResponseR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12'\n" +
+	"\x0ftotal_estimated\x18\x04 \x01(\bR\x0etotalEstimated\"\x9d\x04\n" +
+	"\fTaskResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x19\n" +
+	"\bowner_id\x18\x05 \x01(\x05R\aownerId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\b \x01(\x05R\aversion\x12\x19\n" +
+	"\bstart_at\x18\t \x01(\tR\astartAt\x12\x15\n" +
+	"\x06due_at\x18\n" +
+	" \x01(\tR\x05dueAt\x12\x1a\n" +
+	"\bpriority\x18\v \x01(\tR\bpriority\x12!\n" +
+	"\fcompleted_at\x18\f \x01(\tR\vcompletedAt\x12!\n" +
+	"\fcancelled_at\x18\r \x01(\tR\vcancelledAt\x12&\n" +
+	"\x06labels\x18\x0e \x03(\v2\x0e.task.v1.LabelR\x06labels\x12\x1b\n" +
+	"\tparent_id\x18\x0f \x01(\x05R\bparentId\x12!\n" +
+	"\fassignee_ids\x18\x10 \x03(\x05R\vassigneeIds\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x11 \x01(\x05R\tprojectId\x12\x1f\n" +
+	"\varchived_at\x18\x12 \x01(\tR\n" +
+	"archivedAt\"\xd8\x01\n" +
+	"\x13ListSubtasksRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x05 \x01(\tR\x06sortBy\x12%\n" +
+	"\x0esort_direction\x18\x06 \x01(\tR\rsortDirection\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\a \x01(\tR\ttotalMode\":\n" +
+	"\x12GetTaskTreeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"\x88\x01\n" +
+	"\fTaskTreeNode\x12)\n" +
+	"\x04task\x18\x01 \x01(\v2\x15.task.v1.TaskResponseR\x04task\x12\x1a\n" +
+	"\bprogress\x18\x02 \x01(\x05R\bprogress\x121\n" +
+	"\bchildren\x18\x03 \x03(\v2\x15.task.v1.TaskTreeNodeR\bchildren\"\xe1\x01\n" +
+	"\x15GetTaskHistoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\x05R\aactorId\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\tR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\x12%\n" +
+	"\x0esort_direction\x18\b \x01(\tR\rsortDirection\"\x8d\x01\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x123\n" +
+	"\told_value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\boldValue\x123\n" +
+	"\tnew_value\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\bnewValue\"\xad\x02\n" +
+	"\x10TaskHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\x05R\x06taskId\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\x05R\aactorId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x126\n" +
+	"\n" +
+	"old_values\x18\x05 \x01(\v2\x17.google.protobuf.StructR\toldValues\x126\n" +
+	"\n" +
+	"new_values\x18\x06 \x01(\v2\x17.google.protobuf.StructR\tnewValues\x12.\n" +
+	"\achanges\x18\a \x03(\v2\x14.task.v1.FieldChangeR\achanges\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\b \x01(\tR\tchangedAt\"u\n" +
+	"\x16GetTaskHistoryResponse\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.task.v1.TaskHistoryEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"O\n" +
+	"\x15TaskDependencyRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x02 \x01(\x05R\tblockerId\"2\n" +
+	"\x17ListDependenciesRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\"w\n" +
+	"\x10TaskDependencies\x124\n" +
+	"\n" +
+	"blocked_by\x18\x01 \x03(\v2\x15.task.v1.TaskResponseR\tblockedBy\x12-\n" +
+	"\x06blocks\x18\x02 \x03(\v2\x15.task.v1.TaskResponseR\x06blocks\"G\n" +
+	"\x13TaskAssigneeRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"X\n" +
+	"\x10ShareTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"F\n" +
+	"\x12UnshareTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"/\n" +
+	"\x13UnshareTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"p\n" +
+	"\tTaskShare\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"0\n" +
+	"\x15ListTaskSharesRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\"D\n" +
+	"\x16ListTaskSharesResponse\x12*\n" +
+	"\x06shares\x18\x01 \x03(\v2\x12.task.v1.TaskShareR\x06shares\"o\n" +
+	"\x14CreateCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12*\n" +
+	"\x11parent_comment_id\x18\x02 \x01(\x05R\x0fparentCommentId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"\xdc\x01\n" +
+	"\x13ListCommentsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12*\n" +
+	"\x11parent_comment_id\x18\x02 \x01(\x05R\x0fparentCommentId\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12%\n" +
+	"\x0esort_direction\x18\x05 \x01(\tR\rsortDirection\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\x06 \x01(\tR\ttotalMode\"\xaf\x01\n" +
+	"\x14ListCommentsResponse\x120\n" +
+	"\bcomments\x18\x01 \x03(\v2\x14.task.v1.TaskCommentR\bcomments\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12'\n" +
+	"\x0ftotal_estimated\x18\x04 \x01(\bR\x0etotalEstimated\"b\n" +
+	"\x14UpdateCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\x05R\tcommentId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"N\n" +
+	"\x14DeleteCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\x05R\tcommentId\"1\n" +
+	"\x15DeleteCommentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"U\n" +
+	"\x1bListCommentRevisionsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\x05R\tcommentId\"V\n" +
+	"\x1cListCommentRevisionsResponse\x126\n" +
+	"\trevisions\x18\x01 \x03(\v2\x18.task.v1.CommentRevisionR\trevisions\"\x8a\x02\n" +
+	"\vTaskComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\x05R\x06taskId\x12*\n" +
+	"\x11parent_comment_id\x18\x03 \x01(\x05R\x0fparentCommentId\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\x05R\bauthorId\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1b\n" +
+	"\tedited_at\x18\a \x01(\tR\beditedAt\x12\x18\n" +
+	"\adeleted\x18\b \x01(\bR\adeleted\x12\x1f\n" +
+	"\vreply_count\x18\t \x01(\x05R\n" +
+	"replyCount\"V\n" +
+	"\x0fCommentRevision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12\x1f\n" +
+	"\vreplaced_at\x18\x03 \x01(\tR\n" +
+	"replacedAt\"c\n" +
+	"\x17UploadAttachmentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"Y\n" +
+	"\x19DownloadAttachmentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12#\n" +
+	"\rattachment_id\x18\x02 \x01(\x05R\fattachmentId\"p\n" +
+	"\x1aDownloadAttachmentResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\"1\n" +
+	"\x16ListAttachmentsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\"T\n" +
+	"\x17ListAttachmentsResponse\x129\n" +
+	"\vattachments\x18\x01 \x03(\v2\x17.task.v1.TaskAttachmentR\vattachments\"W\n" +
+	"\x17DeleteAttachmentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12#\n" +
+	"\rattachment_id\x18\x02 \x01(\x05R\fattachmentId\"4\n" +
+	"\x18DeleteAttachmentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xd6\x01\n" +
+	"\x0eTaskAttachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\x05R\x06taskId\x12\x1f\n" +
+	"\vuploader_id\x18\x03 \x01(\x05R\n" +
+	"uploaderId\x12\x1b\n" +
+	"\tfile_name\x18\x04 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfile_size\x18\x05 \x01(\x03R\bfileSize\x12!\n" +
+	"\fcontent_type\x18\x06 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"`\n" +
+	"\x05Label\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\">\n" +
+	"\x12CreateLabelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x02 \x01(\tR\x05color\"\x13\n" +
+	"\x11ListLabelsRequest\"<\n" +
+	"\x12ListLabelsResponse\x12&\n" +
+	"\x06labels\x18\x01 \x03(\v2\x0e.task.v1.LabelR\x06labels\"k\n" +
+	"\x12UpdateLabelRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x19\n" +
+	"\x05color\x18\x03 \x01(\tH\x01R\x05color\x88\x01\x01B\a\n" +
+	"\x05_nameB\b\n" +
+	"\x06_color\"$\n" +
+	"\x12DeleteLabelRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"/\n" +
+	"\x13DeleteLabelResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"F\n" +
+	"\x10TaskLabelRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12\x19\n" +
+	"\blabel_id\x18\x02 \x01(\x05R\alabelId2\xda\x1b\n" +
+	"\vTaskService\x12Y\n" +
+	"\n" +
+	"CreateTask\x12\x1a.task.v1.CreateTaskRequest\x1a\x15.task.v1.TaskResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/v1/tasks\x12U\n" +
+	"\aGetTask\x12\x17.task.v1.GetTaskRequest\x1a\x15.task.v1.TaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/v1/tasks/{id}\x12^\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.task.v1.UpdateTaskRequest\x1a\x15.task.v1.TaskResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\x1a\x12/api/v1/tasks/{id}\x12a\n" +
+	"\n" +
+	"DeleteTask\x12\x1a.task.v1.DeleteTaskRequest\x1a\x1b.task.v1.DeleteTaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/api/v1/tasks/{id}\x12Y\n" +
+	"\tListTasks\x12\x19.task.v1.ListTasksRequest\x1a\x1a.task.v1.ListTasksResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/api/v1/tasks\x12u\n" +
+	"\x0eGetTaskHistory\x12\x1e.task.v1.GetTaskHistoryRequest\x1a\x1f.task.v1.GetTaskHistoryResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/v1/tasks/{id}/history\x12m\n" +
+	"\fListSubtasks\x12\x1c.task.v1.ListSubtasksRequest\x1a\x1a.task.v1.ListTasksResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/api/v1/tasks/{id}/subtasks\x12b\n" +
+	"\vGetTaskTree\x12\x1b.task.v1.GetTaskTreeRequest\x1a\x15.task.v1.TaskTreeNode\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/v1/tasks/{id}/tree\x12\x85\x01\n" +
+	"\rAddDependency\x12\x1e.task.v1.TaskDependencyRequest\x1a\x19.task.v1.TaskDependencies\"9\x82\xd3\xe4\x93\x023\x1a1/api/v1/tasks/{task_id}/dependencies/{blocker_id}\x12\x88\x01\n" +
+	"\x10RemoveDependency\x12\x1e.task.v1.TaskDependencyRequest\x1a\x19.task.v1.TaskDependencies\"9\x82\xd3\xe4\x93\x023*1/api/v1/tasks/{task_id}/dependencies/{blocker_id}\x12}\n" +
+	"\x10ListDependencies\x12 .task.v1.ListDependenciesRequest\x1a\x19.task.v1.TaskDependencies\",\x82\xd3\xe4\x93\x02&\x12$/api/v1/tasks/{task_id}/dependencies\x12v\n" +
+	"\n" +
+	"AssignTask\x12\x1c.task.v1.TaskAssigneeRequest\x1a\x15.task.v1.TaskResponse\"3\x82\xd3\xe4\x93\x02-\x1a+/api/v1/tasks/{task_id}/assignees/{user_id}\x12x\n" +
+	"\fUnassignTask\x12\x1c.task.v1.TaskAssigneeRequest\x1a\x15.task.v1.TaskResponse\"3\x82\xd3\xe4\x93\x02-*+/api/v1/tasks/{task_id}/assignees/{user_id}\x12o\n" +
+	"\tShareTask\x12\x19.task.v1.ShareTaskRequest\x1a\x12.task.v1.TaskShare\"3\x82\xd3\xe4\x93\x02-:\x01*\x1a(/api/v1/tasks/{task_id}/shares/{user_id}\x12z\n" +
+	"\vUnshareTask\x12\x1b.task.v1.UnshareTaskRequest\x1a\x1c.task.v1.UnshareTaskResponse\"0\x82\xd3\xe4\x93\x02**(/api/v1/tasks/{task_id}/shares/{user_id}\x12y\n" +
+	"\x0eListTaskShares\x12\x1e.task.v1.ListTaskSharesRequest\x1a\x1f.task.v1.ListTaskSharesResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/api/v1/tasks/{task_id}/shares\x12q\n" +
+	"\rCreateComment\x12\x1d.task.v1.CreateCommentRequest\x1a\x14.task.v1.TaskComment\"+\x82\xd3\xe4\x93\x02%:\x01*\" /api/v1/tasks/{task_id}/comments\x12u\n" +
+	"\fListComments\x12\x1c.task.v1.ListCommentsRequest\x1a\x1d.task.v1.ListCommentsResponse\"(\x82\xd3\xe4\x93\x02\"\x12 /api/v1/tasks/{task_id}/comments\x12~\n" +
+	"\rUpdateComment\x12\x1d.task.v1.UpdateCommentRequest\x1a\x14.task.v1.TaskComment\"8\x82\xd3\xe4\x93\x022:\x01*2-/api/v1/tasks/{task_id}/comments/{comment_id}\x12\x85\x01\n" +
+	"\rDeleteComment\x12\x1d.task.v1.DeleteCommentRequest\x1a\x1e.task.v1.DeleteCommentResponse\"5\x82\xd3\xe4\x93\x02/*-/api/v1/tasks/{task_id}/comments/{comment_id}\x12\xa4\x01\n" +
+	"\x14ListCommentRevisions\x12$.task.v1.ListCommentRevisionsRequest\x1a%.task.v1.ListCommentRevisionsResponse\"?\x82\xd3\xe4\x93\x029\x127/api/v1/tasks/{task_id}/comments/{comment_id}/revisions\x12O\n" +
+	"\x10UploadAttachment\x12 .task.v1.UploadAttachmentRequest\x1a\x17.task.v1.TaskAttachment(\x01\x12_\n" +
+	"\x12DownloadAttachment\x12\".task.v1.DownloadAttachmentRequest\x1a#.task.v1.DownloadAttachmentResponse0\x01\x12\x81\x01\n" +
+	"\x0fListAttachments\x12\x1f.task.v1.ListAttachmentsRequest\x1a .task.v1.ListAttachmentsResponse\"+\x82\xd3\xe4\x93\x02%\x12#/api/v1/tasks/{task_id}/attachments\x12\x94\x01\n" +
+	"\x10DeleteAttachment\x12 .task.v1.DeleteAttachmentRequest\x1a!.task.v1.DeleteAttachmentResponse\";\x82\xd3\xe4\x93\x025*3/api/v1/tasks/{task_id}/attachments/{attachment_id}\x12U\n" +
+	"\vCreateLabel\x12\x1b.task.v1.CreateLabelRequest\x1a\x0e.task.v1.Label\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/v1/labels\x12]\n" +
+	"\n" +
+	"ListLabels\x12\x1a.task.v1.ListLabelsRequest\x1a\x1b.task.v1.ListLabelsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/v1/labels\x12Z\n" +
+	"\vUpdateLabel\x12\x1b.task.v1.UpdateLabelRequest\x1a\x0e.task.v1.Label\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*2\x13/api/v1/labels/{id}\x12e\n" +
+	"\vDeleteLabel\x12\x1b.task.v1.DeleteLabelRequest\x1a\x1c.task.v1.DeleteLabelResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/api/v1/labels/{id}\x12r\n" +
+	"\vAttachLabel\x12\x19.task.v1.TaskLabelRequest\x1a\x15.task.v1.TaskResponse\"1\x82\xd3\xe4\x93\x02+\x1a)/api/v1/tasks/{task_id}/labels/{label_id}\x12r\n" +
+	"\vDetachLabel\x12\x19.task.v1.TaskLabelRequest\x1a\x15.task.v1.TaskResponse\"1\x82\xd3\xe4\x93\x02+*)/api/v1/tasks/{task_id}/labels/{label_id}B*Z(github.com/St1cky1/task-service/proto/pbb\x06proto3"
 
 var (
 	file_task_service_proto_rawDescOnce sync.Once
@@ -528,34 +3706,147 @@ func file_task_service_proto_rawDescGZIP() []byte {
 	return file_task_service_proto_rawDescData
 }
 
-var file_task_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_task_service_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_task_service_proto_goTypes = []any{
-	(*CreateTaskRequest)(nil),  // 0: task.v1.CreateTaskRequest
-	(*GetTaskRequest)(nil),     // 1: task.v1.GetTaskRequest
-	(*UpdateTaskRequest)(nil),  // 2: task.v1.UpdateTaskRequest
-	(*DeleteTaskRequest)(nil),  // 3: task.v1.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil), // 4: task.v1.DeleteTaskResponse
-	(*ListTasksRequest)(nil),   // 5: task.v1.ListTasksRequest
-	(*ListTasksResponse)(nil),  // 6: task.v1.ListTasksResponse
-	(*TaskResponse)(nil),       // 7: task.v1.TaskResponse
+	(*CreateTaskRequest)(nil),            // 0: task.v1.CreateTaskRequest
+	(*GetTaskRequest)(nil),               // 1: task.v1.GetTaskRequest
+	(*UpdateTaskRequest)(nil),            // 2: task.v1.UpdateTaskRequest
+	(*DeleteTaskRequest)(nil),            // 3: task.v1.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),           // 4: task.v1.DeleteTaskResponse
+	(*ListTasksRequest)(nil),             // 5: task.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),            // 6: task.v1.ListTasksResponse
+	(*TaskResponse)(nil),                 // 7: task.v1.TaskResponse
+	(*ListSubtasksRequest)(nil),          // 8: task.v1.ListSubtasksRequest
+	(*GetTaskTreeRequest)(nil),           // 9: task.v1.GetTaskTreeRequest
+	(*TaskTreeNode)(nil),                 // 10: task.v1.TaskTreeNode
+	(*GetTaskHistoryRequest)(nil),        // 11: task.v1.GetTaskHistoryRequest
+	(*FieldChange)(nil),                  // 12: task.v1.FieldChange
+	(*TaskHistoryEntry)(nil),             // 13: task.v1.TaskHistoryEntry
+	(*GetTaskHistoryResponse)(nil),       // 14: task.v1.GetTaskHistoryResponse
+	(*TaskDependencyRequest)(nil),        // 15: task.v1.TaskDependencyRequest
+	(*ListDependenciesRequest)(nil),      // 16: task.v1.ListDependenciesRequest
+	(*TaskDependencies)(nil),             // 17: task.v1.TaskDependencies
+	(*TaskAssigneeRequest)(nil),          // 18: task.v1.TaskAssigneeRequest
+	(*ShareTaskRequest)(nil),             // 19: task.v1.ShareTaskRequest
+	(*UnshareTaskRequest)(nil),           // 20: task.v1.UnshareTaskRequest
+	(*UnshareTaskResponse)(nil),          // 21: task.v1.UnshareTaskResponse
+	(*TaskShare)(nil),                    // 22: task.v1.TaskShare
+	(*ListTaskSharesRequest)(nil),        // 23: task.v1.ListTaskSharesRequest
+	(*ListTaskSharesResponse)(nil),       // 24: task.v1.ListTaskSharesResponse
+	(*CreateCommentRequest)(nil),         // 25: task.v1.CreateCommentRequest
+	(*ListCommentsRequest)(nil),          // 26: task.v1.ListCommentsRequest
+	(*ListCommentsResponse)(nil),         // 27: task.v1.ListCommentsResponse
+	(*UpdateCommentRequest)(nil),         // 28: task.v1.UpdateCommentRequest
+	(*DeleteCommentRequest)(nil),         // 29: task.v1.DeleteCommentRequest
+	(*DeleteCommentResponse)(nil),        // 30: task.v1.DeleteCommentResponse
+	(*ListCommentRevisionsRequest)(nil),  // 31: task.v1.ListCommentRevisionsRequest
+	(*ListCommentRevisionsResponse)(nil), // 32: task.v1.ListCommentRevisionsResponse
+	(*TaskComment)(nil),                  // 33: task.v1.TaskComment
+	(*CommentRevision)(nil),              // 34: task.v1.CommentRevision
+	(*UploadAttachmentRequest)(nil),      // 35: task.v1.UploadAttachmentRequest
+	(*DownloadAttachmentRequest)(nil),    // 36: task.v1.DownloadAttachmentRequest
+	(*DownloadAttachmentResponse)(nil),   // 37: task.v1.DownloadAttachmentResponse
+	(*ListAttachmentsRequest)(nil),       // 38: task.v1.ListAttachmentsRequest
+	(*ListAttachmentsResponse)(nil),      // 39: task.v1.ListAttachmentsResponse
+	(*DeleteAttachmentRequest)(nil),      // 40: task.v1.DeleteAttachmentRequest
+	(*DeleteAttachmentResponse)(nil),     // 41: task.v1.DeleteAttachmentResponse
+	(*TaskAttachment)(nil),               // 42: task.v1.TaskAttachment
+	(*Label)(nil),                        // 43: task.v1.Label
+	(*CreateLabelRequest)(nil),           // 44: task.v1.CreateLabelRequest
+	(*ListLabelsRequest)(nil),            // 45: task.v1.ListLabelsRequest
+	(*ListLabelsResponse)(nil),           // 46: task.v1.ListLabelsResponse
+	(*UpdateLabelRequest)(nil),           // 47: task.v1.UpdateLabelRequest
+	(*DeleteLabelRequest)(nil),           // 48: task.v1.DeleteLabelRequest
+	(*DeleteLabelResponse)(nil),          // 49: task.v1.DeleteLabelResponse
+	(*TaskLabelRequest)(nil),             // 50: task.v1.TaskLabelRequest
+	(*structpb.Value)(nil),               // 51: google.protobuf.Value
+	(*structpb.Struct)(nil),              // 52: google.protobuf.Struct
 }
 var file_task_service_proto_depIdxs = []int32{
-	7, // 0: task.v1.ListTasksResponse.tasks:type_name -> task.v1.TaskResponse
-	0, // 1: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
-	1, // 2: task.v1.TaskService.GetTask:input_type -> task.v1.GetTaskRequest
-	2, // 3: task.v1.TaskService.UpdateTask:input_type -> task.v1.UpdateTaskRequest
-	3, // 4: task.v1.TaskService.DeleteTask:input_type -> task.v1.DeleteTaskRequest
-	5, // 5: task.v1.TaskService.ListTasks:input_type -> task.v1.ListTasksRequest
-	7, // 6: task.v1.TaskService.CreateTask:output_type -> task.v1.TaskResponse
-	7, // 7: task.v1.TaskService.GetTask:output_type -> task.v1.TaskResponse
-	7, // 8: task.v1.TaskService.UpdateTask:output_type -> task.v1.TaskResponse
-	4, // 9: task.v1.TaskService.DeleteTask:output_type -> task.v1.DeleteTaskResponse
-	6, // 10: task.v1.TaskService.ListTasks:output_type -> task.v1.ListTasksResponse
-	6, // [6:11] is the sub-list for method output_type
-	1, // [1:6] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	7,  // 0: task.v1.ListTasksResponse.tasks:type_name -> task.v1.TaskResponse
+	43, // 1: task.v1.TaskResponse.labels:type_name -> task.v1.Label
+	7,  // 2: task.v1.TaskTreeNode.task:type_name -> task.v1.TaskResponse
+	10, // 3: task.v1.TaskTreeNode.children:type_name -> task.v1.TaskTreeNode
+	51, // 4: task.v1.FieldChange.old_value:type_name -> google.protobuf.Value
+	51, // 5: task.v1.FieldChange.new_value:type_name -> google.protobuf.Value
+	52, // 6: task.v1.TaskHistoryEntry.old_values:type_name -> google.protobuf.Struct
+	52, // 7: task.v1.TaskHistoryEntry.new_values:type_name -> google.protobuf.Struct
+	12, // 8: task.v1.TaskHistoryEntry.changes:type_name -> task.v1.FieldChange
+	13, // 9: task.v1.GetTaskHistoryResponse.entries:type_name -> task.v1.TaskHistoryEntry
+	7,  // 10: task.v1.TaskDependencies.blocked_by:type_name -> task.v1.TaskResponse
+	7,  // 11: task.v1.TaskDependencies.blocks:type_name -> task.v1.TaskResponse
+	22, // 12: task.v1.ListTaskSharesResponse.shares:type_name -> task.v1.TaskShare
+	33, // 13: task.v1.ListCommentsResponse.comments:type_name -> task.v1.TaskComment
+	34, // 14: task.v1.ListCommentRevisionsResponse.revisions:type_name -> task.v1.CommentRevision
+	42, // 15: task.v1.ListAttachmentsResponse.attachments:type_name -> task.v1.TaskAttachment
+	43, // 16: task.v1.ListLabelsResponse.labels:type_name -> task.v1.Label
+	0,  // 17: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
+	1,  // 18: task.v1.TaskService.GetTask:input_type -> task.v1.GetTaskRequest
+	2,  // 19: task.v1.TaskService.UpdateTask:input_type -> task.v1.UpdateTaskRequest
+	3,  // 20: task.v1.TaskService.DeleteTask:input_type -> task.v1.DeleteTaskRequest
+	5,  // 21: task.v1.TaskService.ListTasks:input_type -> task.v1.ListTasksRequest
+	11, // 22: task.v1.TaskService.GetTaskHistory:input_type -> task.v1.GetTaskHistoryRequest
+	8,  // 23: task.v1.TaskService.ListSubtasks:input_type -> task.v1.ListSubtasksRequest
+	9,  // 24: task.v1.TaskService.GetTaskTree:input_type -> task.v1.GetTaskTreeRequest
+	15, // 25: task.v1.TaskService.AddDependency:input_type -> task.v1.TaskDependencyRequest
+	15, // 26: task.v1.TaskService.RemoveDependency:input_type -> task.v1.TaskDependencyRequest
+	16, // 27: task.v1.TaskService.ListDependencies:input_type -> task.v1.ListDependenciesRequest
+	18, // 28: task.v1.TaskService.AssignTask:input_type -> task.v1.TaskAssigneeRequest
+	18, // 29: task.v1.TaskService.UnassignTask:input_type -> task.v1.TaskAssigneeRequest
+	19, // 30: task.v1.TaskService.ShareTask:input_type -> task.v1.ShareTaskRequest
+	20, // 31: task.v1.TaskService.UnshareTask:input_type -> task.v1.UnshareTaskRequest
+	23, // 32: task.v1.TaskService.ListTaskShares:input_type -> task.v1.ListTaskSharesRequest
+	25, // 33: task.v1.TaskService.CreateComment:input_type -> task.v1.CreateCommentRequest
+	26, // 34: task.v1.TaskService.ListComments:input_type -> task.v1.ListCommentsRequest
+	28, // 35: task.v1.TaskService.UpdateComment:input_type -> task.v1.UpdateCommentRequest
+	29, // 36: task.v1.TaskService.DeleteComment:input_type -> task.v1.DeleteCommentRequest
+	31, // 37: task.v1.TaskService.ListCommentRevisions:input_type -> task.v1.ListCommentRevisionsRequest
+	35, // 38: task.v1.TaskService.UploadAttachment:input_type -> task.v1.UploadAttachmentRequest
+	36, // 39: task.v1.TaskService.DownloadAttachment:input_type -> task.v1.DownloadAttachmentRequest
+	38, // 40: task.v1.TaskService.ListAttachments:input_type -> task.v1.ListAttachmentsRequest
+	40, // 41: task.v1.TaskService.DeleteAttachment:input_type -> task.v1.DeleteAttachmentRequest
+	44, // 42: task.v1.TaskService.CreateLabel:input_type -> task.v1.CreateLabelRequest
+	45, // 43: task.v1.TaskService.ListLabels:input_type -> task.v1.ListLabelsRequest
+	47, // 44: task.v1.TaskService.UpdateLabel:input_type -> task.v1.UpdateLabelRequest
+	48, // 45: task.v1.TaskService.DeleteLabel:input_type -> task.v1.DeleteLabelRequest
+	50, // 46: task.v1.TaskService.AttachLabel:input_type -> task.v1.TaskLabelRequest
+	50, // 47: task.v1.TaskService.DetachLabel:input_type -> task.v1.TaskLabelRequest
+	7,  // 48: task.v1.TaskService.CreateTask:output_type -> task.v1.TaskResponse
+	7,  // 49: task.v1.TaskService.GetTask:output_type -> task.v1.TaskResponse
+	7,  // 50: task.v1.TaskService.UpdateTask:output_type -> task.v1.TaskResponse
+	4,  // 51: task.v1.TaskService.DeleteTask:output_type -> task.v1.DeleteTaskResponse
+	6,  // 52: task.v1.TaskService.ListTasks:output_type -> task.v1.ListTasksResponse
+	14, // 53: task.v1.TaskService.GetTaskHistory:output_type -> task.v1.GetTaskHistoryResponse
+	6,  // 54: task.v1.TaskService.ListSubtasks:output_type -> task.v1.ListTasksResponse
+	10, // 55: task.v1.TaskService.GetTaskTree:output_type -> task.v1.TaskTreeNode
+	17, // 56: task.v1.TaskService.AddDependency:output_type -> task.v1.TaskDependencies
+	17, // 57: task.v1.TaskService.RemoveDependency:output_type -> task.v1.TaskDependencies
+	17, // 58: task.v1.TaskService.ListDependencies:output_type -> task.v1.TaskDependencies
+	7,  // 59: task.v1.TaskService.AssignTask:output_type -> task.v1.TaskResponse
+	7,  // 60: task.v1.TaskService.UnassignTask:output_type -> task.v1.TaskResponse
+	22, // 61: task.v1.TaskService.ShareTask:output_type -> task.v1.TaskShare
+	21, // 62: task.v1.TaskService.UnshareTask:output_type -> task.v1.UnshareTaskResponse
+	24, // 63: task.v1.TaskService.ListTaskShares:output_type -> task.v1.ListTaskSharesResponse
+	33, // 64: task.v1.TaskService.CreateComment:output_type -> task.v1.TaskComment
+	27, // 65: task.v1.TaskService.ListComments:output_type -> task.v1.ListCommentsResponse
+	33, // 66: task.v1.TaskService.UpdateComment:output_type -> task.v1.TaskComment
+	30, // 67: task.v1.TaskService.DeleteComment:output_type -> task.v1.DeleteCommentResponse
+	32, // 68: task.v1.TaskService.ListCommentRevisions:output_type -> task.v1.ListCommentRevisionsResponse
+	42, // 69: task.v1.TaskService.UploadAttachment:output_type -> task.v1.TaskAttachment
+	37, // 70: task.v1.TaskService.DownloadAttachment:output_type -> task.v1.DownloadAttachmentResponse
+	39, // 71: task.v1.TaskService.ListAttachments:output_type -> task.v1.ListAttachmentsResponse
+	41, // 72: task.v1.TaskService.DeleteAttachment:output_type -> task.v1.DeleteAttachmentResponse
+	43, // 73: task.v1.TaskService.CreateLabel:output_type -> task.v1.Label
+	46, // 74: task.v1.TaskService.ListLabels:output_type -> task.v1.ListLabelsResponse
+	43, // 75: task.v1.TaskService.UpdateLabel:output_type -> task.v1.Label
+	49, // 76: task.v1.TaskService.DeleteLabel:output_type -> task.v1.DeleteLabelResponse
+	7,  // 77: task.v1.TaskService.AttachLabel:output_type -> task.v1.TaskResponse
+	7,  // 78: task.v1.TaskService.DetachLabel:output_type -> task.v1.TaskResponse
+	48, // [48:79] is the sub-list for method output_type
+	17, // [17:48] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_task_service_proto_init() }
@@ -564,13 +3855,14 @@ func file_task_service_proto_init() {
 		return
 	}
 	file_task_service_proto_msgTypes[2].OneofWrappers = []any{}
+	file_task_service_proto_msgTypes[47].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_service_proto_rawDesc), len(file_task_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},